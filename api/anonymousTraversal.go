@@ -0,0 +1,48 @@
+package api
+
+import (
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// anonymousTraversalRoot is the root of an anonymous traversal (a traversal that is not spawned from g)
+type anonymousTraversalRoot struct {
+}
+
+// String returns __ for the CosmosDB and an empty string for TinkerPop (where the steps of
+// the anonymous traversal are statically imported and thus can be used without the __. prefix).
+func (r *anonymousTraversalRoot) String() string {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		return "__"
+	}
+	return ""
+}
+
+// Underscore creates the root of an anonymous traversal, e.g. to be used as sub-traversal for steps like
+// union, coalesce, choose, where or repeat. In contrast to the traversals created via NewGraph it does not
+// start with the name of the graph.
+// Depending on the query language in use the prefix __. is added (QueryLanguageCosmosDB) or omitted (QueryLanguageTinkerpopGremlin).
+//	Underscore().Has("name","hans") ==> __.has("name","hans") or has("name","hans")
+//	g.V().Union(Underscore().InE("knows").OutV(), Underscore().OutE("knows").InV())
+func Underscore() interfaces.Vertex {
+	return &vertex{
+		builders: []interfaces.QueryBuilder{&anonymousTraversalRoot{}},
+	}
+}
+
+// Inject creates an anonymous traversal that starts with the given constant values, e.g. to seed lookups or joins.
+//	Inject("a",1,true) ==> __.inject("a",1,true) or inject("a",1,true)
+func Inject(values ...interface{}) interfaces.Vertex {
+	return Underscore().Inject(values...)
+}
+
+// Constant creates an anonymous traversal that returns the given constant value, e.g. as default branch of coalesce.
+//	Constant("unknown") ==> __.constant("unknown") or constant("unknown")
+func Constant(value interface{}) interfaces.QueryBuilder {
+	return Underscore().Constant(value)
+}
+
+// T is a shorthand for Underscore. It creates the root of an anonymous traversal.
+//	T().Has("name","hans") ==> __.has("name","hans") or has("name","hans")
+func T() interfaces.Vertex {
+	return Underscore()
+}
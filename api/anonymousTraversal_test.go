@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnderscoreCosmos(t *testing.T) {
+	// WHEN
+	vEmpty := Underscore()
+	vHas := Underscore().Has("name", "hans")
+	vOut := T().OutE("knows").InV()
+
+	// THEN
+	assert.Equal(t, "__", vEmpty.String())
+	assert.Equal(t, `__.has("name","hans")`, vHas.String())
+	assert.Equal(t, `__.outE("knows").inV()`, vOut.String())
+}
+
+func TestUnderscoreTinkerpop(t *testing.T) {
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	vEmpty := Underscore()
+	vHas := Underscore().Has("name", "hans")
+	vOut := T().OutE("knows").InV()
+	vEmptyStr := vEmpty.String()
+	vHasStr := vHas.String()
+	vOutStr := vOut.String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, "", vEmptyStr)
+	assert.Equal(t, `has("name","hans")`, vHasStr)
+	assert.Equal(t, `outE("knows").inV()`, vOutStr)
+}
+
+func TestInjectAnonymous(t *testing.T) {
+	// WHEN
+	vInject := Inject("a", 1, false)
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	vInjectTinkerpopStr := Inject("a", 1, false).String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, `__.inject("a",1,false)`, vInject.String())
+	assert.Equal(t, `inject("a",1,false)`, vInjectTinkerpopStr)
+}
+
+func TestUnderscoreAsSubTraversal(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().Union(Underscore().InE("knows").OutV(), Underscore().OutE("knows").InV())
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf(`%s.V().union(__.inE("knows").outV(),__.outE("knows").inV())`, graphName), v.String())
+}
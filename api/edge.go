@@ -1,6 +1,8 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/supplyon/gremcos/interfaces"
 )
 
@@ -52,14 +54,54 @@ func (e *edge) Limit(maxElements int) interfaces.Edge {
 	return e.Add(NewSimpleQB(".limit(%d)", maxElements))
 }
 
-// To adds .to(<vertex>), to the query. The query call will be the second step to add an edge
-func (e *edge) To(v interfaces.Vertex) interfaces.Edge {
-	return e.Add(NewSimpleQB(".to(%s)", v))
+// To adds .to(<traversal>), e.g. .to(g.V().has("name","hans")), to the query. The query call will be the second step to add an edge
+func (e *edge) To(traversal interfaces.QueryBuilder) interfaces.Edge {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of to is nil"))
+	}
+	return e.Add(NewSimpleQB(".to(%s)", traversal))
+}
+
+// From adds .from(<traversal>), e.g. .from(g.V().has("name","hans")), to the query. The query call will be the second step to add an edge
+func (e *edge) From(traversal interfaces.QueryBuilder) interfaces.Edge {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of from is nil"))
+	}
+	return e.Add(NewSimpleQB(".from(%s)", traversal))
+}
+
+// ToId adds .to(g.V("<id>")), to the query. The created edge will point to the vertex with the given id.
+func (e *edge) ToId(id string) interfaces.Edge {
+	return e.Add(NewSimpleQB(".to(g.V(\"%s\"))", Escape(id)))
+}
+
+// FromId adds .from(g.V("<id>")), to the query. The created edge will start at the vertex with the given id.
+func (e *edge) FromId(id string) interfaces.Edge {
+	return e.Add(NewSimpleQB(".from(g.V(\"%s\"))", Escape(id)))
 }
 
-// From adds .from(<vertex>), to the query. The query call will be the second step to add an edge
-func (e *edge) From(v interfaces.Vertex) interfaces.Edge {
-	return e.Add(NewSimpleQB(".from(%s)", v))
+// ToLabel adds .to("<step label>"), to the query. The created edge will point to the vertex that was labeled using As.
+func (e *edge) ToLabel(stepLabel string) interfaces.Edge {
+	return e.Add(NewSimpleQB(".to(\"%s\")", Escape(stepLabel)))
+}
+
+// FromLabel adds .from("<step label>"), to the query. The created edge will start at the vertex that was labeled using As.
+func (e *edge) FromLabel(stepLabel string) interfaces.Edge {
+	return e.Add(NewSimpleQB(".from(\"%s\")", Escape(stepLabel)))
+}
+
+// OnCreate adds .option(Merge.onCreate,<properties>), e.g. .option(Merge.onCreate,["created":true]), to the query.
+// It modulates the previous MergeE step, the given properties are set in case the edge is created.
+// Hint: The mergeE step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (e *edge) OnCreate(properties map[interface{}]interface{}) interfaces.Edge {
+	return e.Add(mergeOptionQuery("onCreate", properties))
+}
+
+// OnMatch adds .option(Merge.onMatch,<properties>), e.g. .option(Merge.onMatch,["updated":true]), to the query.
+// It modulates the previous MergeE step, the given properties are set in case the edge already exists.
+// Hint: The mergeE step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (e *edge) OnMatch(properties map[interface{}]interface{}) interfaces.Edge {
+	return e.Add(mergeOptionQuery("onMatch", properties))
 }
 
 // Drop adds .drop(), to the query. The query call will drop/ delete all referenced entities
@@ -108,3 +150,9 @@ func (e *edge) HasId(id string) interfaces.Edge {
 func (e *edge) Count() interfaces.QueryBuilder {
 	return e.Add(NewSimpleQB(".count()"))
 }
+
+// CountLocal adds .count(local), to the query. The query call will return the number of elements within each collection,
+// e.g. the number of vertices per group of g.V().group().by(label).count(local).
+func (e *edge) CountLocal() interfaces.QueryBuilder {
+	return e.Add(NewSimpleQB(".count(local)"))
+}
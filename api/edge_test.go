@@ -89,6 +89,62 @@ func TestFrom(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.from(%s.V())", graphName, graphName), e.String())
 }
 
+func TestFromToTraversal(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+
+	// WHEN
+	query := g.AddV("EmployeeBulkData").Property("user_id", "1").As("y").
+		AddE("employes").From(g.V().Has("user_id", "1234567890")).ToLabel("y")
+
+	// THEN
+	assert.Equal(t, `g.addV("EmployeeBulkData").property("user_id","1").as("y").addE("employes").from(g.V().has("user_id","1234567890")).to("y")`, query.String())
+}
+
+func TestChainedInsert(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+	userID := 42
+
+	// WHEN
+	// the idiom used to seed the employes edges
+	seed := g.AddV("EmployeeBulkData").Property("user_id", fmt.Sprintf("%d", userID)).Property("timestamp", "2018-07-01T13:37:45-05:00").Property("source", "tree").As("y").
+		AddE("employes").From(g.V().Has("user_id", "1234567890")).ToLabel("y")
+	reversed := g.AddV("EmployerBulkData").As("x").
+		AddE("employes").FromLabel("x").To(g.V().Has("user_id", "42"))
+
+	// THEN
+	assert.Equal(t, `g.addV("EmployeeBulkData").property("user_id","42").property("timestamp","2018-07-01T13:37:45-05:00").property("source","tree").as("y").addE("employes").from(g.V().has("user_id","1234567890")).to("y")`, seed.String())
+	assert.Equal(t, `g.addV("EmployerBulkData").as("x").addE("employes").from("x").to(g.V().has("user_id","42"))`, reversed.String())
+}
+
+func TestFromToId(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+
+	// WHEN
+	query := g.V().HasId("1").AddE("knows").FromId("1").ToId("2")
+	queryFromLabel := g.V().HasId("2").As("x").AddE("knows").FromLabel("x").To(g.V().HasId("3"))
+
+	// THEN
+	assert.Equal(t, `g.V().hasId("1").addE("knows").from(g.V("1")).to(g.V("2"))`, query.String())
+	assert.Equal(t, `g.V().hasId("2").as("x").addE("knows").from("x").to(g.V().hasId("3"))`, queryFromLabel.String())
+}
+
+func TestFromToNilTraversal(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+	e := g.V().AddE("knows")
+
+	// WHEN + THEN
+	assert.Panics(t, func() { e.From(nil) }, "The code did not panic")
+	assert.Panics(t, func() { e.To(nil) }, "The code did not panic")
+}
+
 func TestEdgeDrop(t *testing.T) {
 
 	// GIVEN
@@ -230,6 +286,23 @@ func TestEdgeCount(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.count()", graphName), qb.String())
 }
 
+func TestEdgeCountLocal(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	e := NewEdgeG(g)
+	require.NotNil(t, e)
+
+	// WHEN
+	qb := e.CountLocal()
+
+	// THEN
+	assert.NotNil(t, qb)
+	assert.Equal(t, fmt.Sprintf("%s.count(local)", graphName), qb.String())
+}
+
 func TestEdgeHasId(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
@@ -6,6 +6,9 @@ import (
 )
 
 // Escape escapes all values that are not allowed to be stored directly into the cosmos-db.
+// Values containing quotes (' and "), backslashes, $ (groovy string interpolation) or control characters
+// like newline, carriage return or tab are url query escaped. This ensures that the value can be
+// safely used as string literal inside a query without breaking it or enabling an injection.
 func Escape(value string) string {
 	if !ShouldEscape(value) {
 		return value
@@ -29,7 +32,9 @@ func UnEscape(value string) string {
 	return result
 }
 
-var regexpSpecialChars = regexp.MustCompile(`(\$|\\n|\\v|\\r|\\t|\\f|\\s|\\b|'|"|\\)`)
+// regexpSpecialChars matches all characters that are not allowed inside a string literal of a query.
+// Besides the escape sequences written as text (e.g. \n) the actual control characters (e.g. a newline) are matched as well.
+var regexpSpecialChars = regexp.MustCompile(`(\$|\\n|\\v|\\r|\\t|\\f|\\s|\\b|'|"|\\|\n|\v|\r|\t|\f|\x08)`)
 var regexpURLEncoded = regexp.MustCompile(`%[a-fA-F0-9]{2}`)
 
 // ShouldEscape returns true in case the given string needs to be escaped.
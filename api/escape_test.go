@@ -120,3 +120,38 @@ func TestShouldUnEscape(t *testing.T) {
 	assert.False(t, shouldUnEscape6)
 	assert.False(t, shouldUnEscape7)
 }
+
+func TestEscapeNastyInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "quotes, newline and dollar", input: "he said \"hi\"\n$x", expected: "he+said+%22hi%22%0A%24x"},
+		{name: "single quote", input: "it's", expected: "it%27s"},
+		{name: "backslash", input: `a\b`, expected: "a%5Cb"},
+		{name: "newline", input: "line1\nline2", expected: "line1%0Aline2"},
+		{name: "carriage return", input: "line1\r\nline2", expected: "line1%0D%0Aline2"},
+		{name: "tab", input: "col1\tcol2", expected: "col1%09col2"},
+		{name: "groovy interpolation", input: "${System.exit(0)}", expected: "%24%7BSystem.exit%280%29%7D"},
+		{name: "query injection", input: `x").drop();g.V().has("a`, expected: "x%22%29.drop%28%29%3Bg.V%28%29.has%28%22a"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// WHEN
+			escaped := Escape(test.input)
+
+			// THEN
+			assert.Equal(t, test.expected, escaped)
+			assert.NotContains(t, escaped, `"`)
+			assert.NotContains(t, escaped, `'`)
+			assert.NotContains(t, escaped, `\`)
+			assert.NotContains(t, escaped, `$`)
+			assert.NotContains(t, escaped, "\n")
+			assert.NotContains(t, escaped, "\r")
+			assert.NotContains(t, escaped, "\t")
+			assert.Equal(t, test.input, UnEscape(escaped))
+		})
+	}
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"github.com/supplyon/gremcos/interfaces"
 )
 
@@ -23,6 +25,29 @@ func SetQueryLanguageTo(ql QueryLanguage) {
 	gUSE_COSMOS_DB_QUERY_LANGUAGE = (ql == QueryLanguageCosmosDB)
 }
 
+// QueryLanguageInUse returns the query language that is currently in use.
+func QueryLanguageInUse() QueryLanguage {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		return QueryLanguageCosmosDB
+	}
+	return QueryLanguageTinkerpopGremlin
+}
+
+// Token is a gremlin token that can be used as key of the search criteria of MergeV and MergeE, e.g. T.label.
+// Tokens are rendered unquoted and in parentheses, e.g. [(T.label):"person"].
+type Token string
+
+const (
+	// TokenID is the id of an element (T.id)
+	TokenID Token = "T.id"
+	// TokenLabel is the label of an element (T.label)
+	TokenLabel Token = "T.label"
+	// TokenDirectionOut is the outgoing vertex of an edge (Direction.OUT), used by MergeE
+	TokenDirectionOut Token = "Direction.OUT"
+	// TokenDirectionIn is the incoming vertex of an edge (Direction.IN), used by MergeE
+	TokenDirectionIn Token = "Direction.IN"
+)
+
 // NewGraph creates a new graph query with the given name
 // Hint: The actual graph has to exist on the server in order to execute the
 // query that will be generated with this query builder
@@ -64,6 +89,15 @@ func (g *graph) VByStr(id string) interfaces.Vertex {
 	return vertex
 }
 
+// VById adds .V(<id_1>,<id_2>,..,<id_n>), e.g. .V("1a",2), to the query. The query call returns the vertices with the given ids.
+// String ids are quoted while numeric (integer) ids are not. Looking up the vertices by id is much cheaper than g.V().hasId(..).
+// It panics in case no id is given or an id is neither a string nor an integer.
+func (g *graph) VById(ids ...interface{}) interfaces.Vertex {
+	vertex := NewVertexG(g)
+	vertex.Add(NewSimpleQB(".V(%s)", toIDList("V", ids)))
+	return vertex
+}
+
 // AddV adds .addV("<label>"), e.g. .addV("user")
 func (g *graph) AddV(label string) interfaces.Vertex {
 	vertex := NewVertexG(g)
@@ -78,6 +112,140 @@ func (g *graph) E() interfaces.Edge {
 	return edge
 }
 
+// EById adds .E(<id_1>,<id_2>,..,<id_n>), e.g. .E("1a",2), to the query. The query call returns the edges with the given ids.
+// String ids are quoted while numeric (integer) ids are not.
+// It panics in case no id is given or an id is neither a string nor an integer.
+func (g *graph) EById(ids ...interface{}) interfaces.Edge {
+	edge := NewEdgeG(g)
+	edge.Add(NewSimpleQB(".E(%s)", toIDList("E", ids)))
+	return edge
+}
+
+// toIDList returns the comma separated ids for the given step, the string ids are quoted and escaped
+func toIDList(step string, ids []interface{}) string {
+	if len(ids) == 0 {
+		panic(fmt.Errorf("%s needs at least one id", step))
+	}
+
+	idStrs := make([]string, 0, len(ids))
+	for i, id := range ids {
+		switch id.(type) {
+		case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		default:
+			panic(fmt.Errorf("id %d of %s is neither a string nor an integer but %T", i, step, id))
+		}
+		idStr, err := toValueString(id)
+		if err != nil {
+			panic(errors.Wrapf(err, "rendering id %d of %s failed", i, step))
+		}
+		idStrs = append(idStrs, idStr)
+	}
+	return strings.Join(idStrs, ",")
+}
+
+// Inject adds .inject(<values>), e.g. .inject("a",1,true), depending on the given type the quotes for the values are omitted.
+// The query call returns the given constant values. It panics in case a value is nil or not supported.
+func (g *graph) Inject(values ...interface{}) interfaces.Vertex {
+	return NewVertexG(g).Inject(values...)
+}
+
+// MergeV adds .mergeV(<search criteria>), e.g. .mergeV([(T.label):"person","name":"hans"]), to the query.
+// The vertex matching the search criteria is returned, if no such vertex exists it is created (upsert).
+// The keys of the search criteria are either property names (strings) or tokens like TokenLabel and TokenID.
+// Hint: The mergeV step was introduced in TinkerPop 3.6 and is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (g *graph) MergeV(searchCriteria map[interface{}]interface{}) interfaces.Vertex {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("mergeV is not supported by the CosmosDB (use the coalesce idiom instead)"))
+	}
+
+	criteria, err := toGroovyMap(searchCriteria)
+	if err != nil {
+		panic(errors.Wrapf(err, "rendering the search criteria of mergeV failed"))
+	}
+
+	vertex := NewVertexG(g)
+	vertex.Add(NewSimpleQB(".mergeV(%s)", criteria))
+	return vertex
+}
+
+// MergeE adds .mergeE(<search criteria>), e.g. .mergeE([(T.label):"knows",(Direction.OUT):"1",(Direction.IN):"2"]), to the query.
+// The edge matching the search criteria is returned, if no such edge exists it is created (upsert).
+// The keys of the search criteria are either property names (strings) or tokens like TokenLabel, TokenDirectionOut and TokenDirectionIn.
+// Hint: The mergeE step was introduced in TinkerPop 3.6 and is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (g *graph) MergeE(searchCriteria map[interface{}]interface{}) interfaces.Edge {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("mergeE is not supported by the CosmosDB (use the coalesce idiom instead)"))
+	}
+
+	criteria, err := toGroovyMap(searchCriteria)
+	if err != nil {
+		panic(errors.Wrapf(err, "rendering the search criteria of mergeE failed"))
+	}
+
+	edge := NewEdgeG(g)
+	edge.Add(NewSimpleQB(".mergeE(%s)", criteria))
+	return edge
+}
+
+// mergeOptionQuery creates .option(Merge.<option>,<properties>) to modulate a mergeV or mergeE step.
+// It panics in case QueryLanguageCosmosDB is in use or the properties can't be rendered.
+func mergeOptionQuery(option string, properties map[interface{}]interface{}) interfaces.QueryBuilder {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("the %s option of mergeV and mergeE is not supported by the CosmosDB", option))
+	}
+
+	rendered, err := toGroovyMap(properties)
+	if err != nil {
+		panic(errors.Wrapf(err, "rendering the properties of the %s option failed", option))
+	}
+	return NewSimpleQB(".option(Merge.%s,%s)", option, rendered)
+}
+
+// toGroovyMap renders the given map as groovy map literal, e.g. [(T.label):"person","name":"hans"].
+// Keys of type Token are rendered unquoted in parentheses, all other keys are quoted. The tokens come first,
+// the remaining keys are sorted alphabetically to get a deterministic order.
+func toGroovyMap(input map[interface{}]interface{}) (string, error) {
+	if len(input) == 0 {
+		return "[:]", nil
+	}
+
+	type entry struct {
+		key     string
+		isToken bool
+		value   string
+	}
+
+	entries := make([]entry, 0, len(input))
+	for key, value := range input {
+		valueStr, err := toValueString(value)
+		if err != nil {
+			return "", errors.Wrapf(err, "value of key '%v' is invalid", key)
+		}
+
+		switch casted := key.(type) {
+		case Token:
+			entries = append(entries, entry{key: fmt.Sprintf("(%s)", casted), isToken: true, value: valueStr})
+		case string:
+			entries = append(entries, entry{key: fmt.Sprintf("\"%s\"", Escape(casted)), value: valueStr})
+		default:
+			return "", fmt.Errorf("key '%v' is of type %T, only strings and tokens are supported", key, key)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].isToken != entries[j].isToken {
+			return entries[i].isToken
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	rendered := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		rendered = append(rendered, fmt.Sprintf("%s:%s", entry.key, entry.value))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(rendered, ",")), nil
+}
+
 func (g *graph) String() string {
 	return g.name
 }
@@ -81,6 +81,30 @@ func TestVByStr(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.V(\"%s\")", graphName, id), v.String())
 }
 
+func TestVById(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	vSingleStr := g.VById("1234ABCD")
+	vSingleInt := g.VById(42)
+	vMultiple := g.VById("id1", "id2")
+	vMixed := g.VById("id1", int64(2), uint8(3))
+	vEscaped := g.VById(`a"b`)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V(\"1234ABCD\")", graphName), vSingleStr.String())
+	assert.Equal(t, fmt.Sprintf("%s.V(42)", graphName), vSingleInt.String())
+	assert.Equal(t, fmt.Sprintf("%s.V(\"id1\",\"id2\")", graphName), vMultiple.String())
+	assert.Equal(t, fmt.Sprintf("%s.V(\"id1\",2,3)", graphName), vMixed.String())
+	assert.Equal(t, fmt.Sprintf("%s.V(\"a%%22b\")", graphName), vEscaped.String())
+	assert.Panics(t, func() { g.VById() }, "The code did not panic")
+	assert.Panics(t, func() { g.VById("id1", 1.5) }, "The code did not panic")
+	assert.Panics(t, func() { g.VById(nil) }, "The code did not panic")
+}
+
 func TestAddV(t *testing.T) {
 
 	// GIVEN
@@ -96,6 +120,20 @@ func TestAddV(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.addV(\"%s\")", graphName, label), v.String())
 }
 
+func TestAddVWithProperties(t *testing.T) {
+
+	// GIVEN
+	g := NewGraph("g")
+
+	// WHEN
+	v := g.AddV("X").Property("k", "v")
+	seed := g.AddV("EmployeeBulkData").Property("user_id", "1").Property("timestamp", "2018-07-01T13:37:45-05:00").Property("source", "tree")
+
+	// THEN
+	assert.Equal(t, `g.addV("X").property("k","v")`, v.String())
+	assert.Equal(t, `g.addV("EmployeeBulkData").property("user_id","1").property("timestamp","2018-07-01T13:37:45-05:00").property("source","tree")`, seed.String())
+}
+
 func TestE(t *testing.T) {
 
 	// GIVEN
@@ -110,6 +148,27 @@ func TestE(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.E()", graphName), v.String())
 }
 
+func TestEById(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	eSingleStr := g.EById("623709d5-fe22-4377-bc5b-9cb150fff124")
+	eSingleInt := g.EById(7)
+	eMultipleStr := g.EById("e1", "e2")
+	eMultipleInt := g.EById(7, int32(8)).Count()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.E(\"623709d5-fe22-4377-bc5b-9cb150fff124\")", graphName), eSingleStr.String())
+	assert.Equal(t, fmt.Sprintf("%s.E(7)", graphName), eSingleInt.String())
+	assert.Equal(t, fmt.Sprintf("%s.E(\"e1\",\"e2\")", graphName), eMultipleStr.String())
+	assert.Equal(t, fmt.Sprintf("%s.E(7,8).count()", graphName), eMultipleInt.String())
+	assert.Panics(t, func() { g.EById() }, "The code did not panic")
+	assert.Panics(t, func() { g.EById(true) }, "The code did not panic")
+}
+
 func TestMultiparamQuery(t *testing.T) {
 
 	// GIVEN
@@ -128,3 +187,111 @@ func TestMultiparamQuery(t *testing.T) {
 	assert.Equal(t, ".outE(\"label1\")", q2.String())
 	assert.Equal(t, ".outE(\"label1\",\"label2\")", q3.String())
 }
+
+func TestMergeV(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	v := g.MergeV(map[interface{}]interface{}{TokenLabel: "person", "name": "hans", "age": 42, TokenID: "1"})
+	vEmpty := g.MergeV(map[interface{}]interface{}{})
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.mergeV([(T.id):\"1\",(T.label):\"person\",\"age\":42,\"name\":\"hans\"])", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.mergeV([:])", graphName), vEmpty.String())
+}
+
+func TestMergeE(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	e := g.MergeE(map[interface{}]interface{}{TokenLabel: "knows", TokenDirectionOut: "1", TokenDirectionIn: "2", "since": 2015})
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.NotNil(t, e)
+	assert.Equal(t, fmt.Sprintf("%s.mergeE([(Direction.IN):\"2\",(Direction.OUT):\"1\",(T.label):\"knows\",\"since\":2015])", graphName), e.String())
+}
+
+func TestMergeOptions(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	v := g.MergeV(map[interface{}]interface{}{TokenLabel: "person", "name": "hans"}).
+		OnCreate(map[interface{}]interface{}{"created": true, "visits": 1}).
+		OnMatch(map[interface{}]interface{}{"updated": true})
+	e := g.MergeE(map[interface{}]interface{}{TokenLabel: "knows", TokenDirectionOut: "1", TokenDirectionIn: "2"}).
+		OnCreate(map[interface{}]interface{}{"since": 2015}).
+		OnMatch(map[interface{}]interface{}{})
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.mergeV([(T.label):\"person\",\"name\":\"hans\"]).option(Merge.onCreate,[\"created\":true,\"visits\":1]).option(Merge.onMatch,[\"updated\":true])", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.mergeE([(Direction.IN):\"2\",(Direction.OUT):\"1\",(T.label):\"knows\"]).option(Merge.onCreate,[\"since\":2015]).option(Merge.onMatch,[:])", graphName), e.String())
+}
+
+func TestMergeOptionsFail(t *testing.T) {
+	// GIVEN
+	g := NewGraph("mygraph")
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().OnCreate(map[interface{}]interface{}{"created": true}) }, "not supported by cosmos")
+	assert.Panics(t, func() { g.E().OnMatch(map[interface{}]interface{}{"updated": true}) }, "not supported by cosmos")
+
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	defer SetQueryLanguageTo(QueryLanguageCosmosDB)
+	assert.Panics(t, func() { g.V().OnMatch(map[interface{}]interface{}{"updated": nil}) }, "invalid value")
+}
+
+func TestGraphInject(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	v := g.Inject("a", 1, true, 2.5)
+	vChained := g.Inject("1", "2").Constant("x y")
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.inject(\"a\",1,true,2.5)", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.inject(\"1\",\"2\").constant(\"x y\")", graphName), vChained.String())
+	assert.Panics(t, func() { g.Inject(nil) }, "The code did not panic")
+}
+
+func TestMergeVEscapesKeys(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	v := g.MergeV(map[interface{}]interface{}{`na"me`: "hans"})
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.mergeV([\"na%%22me\":\"hans\"])", graphName), v.String())
+}
+
+func TestMergeFail(t *testing.T) {
+	// GIVEN
+	g := NewGraph("mygraph")
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.MergeV(map[interface{}]interface{}{"name": "hans"}) }, "not supported by cosmos")
+	assert.Panics(t, func() { g.MergeE(map[interface{}]interface{}{TokenLabel: "knows"}) }, "not supported by cosmos")
+
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	defer SetQueryLanguageTo(QueryLanguageCosmosDB)
+	assert.Panics(t, func() { g.MergeV(map[interface{}]interface{}{1: "hans"}) }, "invalid key")
+	assert.Panics(t, func() { g.MergeV(map[interface{}]interface{}{"name": nil}) }, "invalid value")
+}
@@ -0,0 +1,624 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+const (
+	graphSONTypeKey  = "@type"
+	graphSONValueKey = "@value"
+)
+
+// GraphSON type identifiers as they are used in the "@type" field of a GraphSON v2/ v3 envelope
+const (
+	graphSONTypeInt32          = "g:Int32"
+	graphSONTypeInt64          = "g:Int64"
+	graphSONTypeFloat          = "g:Float"
+	graphSONTypeDouble         = "g:Double"
+	graphSONTypeDate           = "g:Date"
+	graphSONTypeTimestamp      = "g:Timestamp"
+	graphSONTypeUUID           = "g:UUID"
+	graphSONTypeList           = "g:List"
+	graphSONTypeSet            = "g:Set"
+	graphSONTypeMap            = "g:Map"
+	graphSONTypeVertex         = "g:Vertex"
+	graphSONTypeEdge           = "g:Edge"
+	graphSONTypeVertexProperty = "g:VertexProperty"
+	graphSONTypeProperty       = "g:Property"
+	graphSONTypeTraverser      = "g:Traverser"
+)
+
+// MaxExpandedTraversers is the maximum number of results a list may have after expanding its bulked traversers (g:Traverser).
+// Decoding data exceeding it fails with an error, the bulk counts of such results can be obtained via ParseTraversers.
+const MaxExpandedTraversers = 100000
+
+// Traverser represents a GraphSON g:Traverser, e.g. {"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"tree"}}.
+// The bulk is the number of identical traversers (results) represented by this traverser.
+type Traverser struct {
+	// Bulk is the number of identical results represented by this traverser (at least 1)
+	Bulk int64
+	// Value is the result itself (as plain go type)
+	Value interface{}
+}
+
+// Decode decodes the data of the given response into out.
+// The GraphSON (v2 and v3) envelopes like {"@type":"g:Int64","@value":9147} are unwrapped,
+// vertices and edges are flattened (id, label and all properties on top level) and
+// property lists containing exactly one element are unwrapped in case the target field is a scalar.
+// Bulked traversers (g:Traverser) are expanded, i.e. a traverser with bulk 3 results in three identical results
+// (use ParseTraversers to obtain the bulk counts instead). An error is returned in case the expansion would exceed MaxExpandedTraversers.
+// The fields of the target type have to be annotated with 'mapstructure' tags that match the property names.
+// Example:
+//
+// type Employee struct {
+//  ID        string    `mapstructure:"id"`
+//  Source    string    `mapstructure:"source"`
+//  Timestamp time.Time `mapstructure:"timestamp"`
+// }
+//
+// var employees []Employee
+// err := Decode(response, &employees)
+//
+// In case out points to a slice all results of the response are decoded, otherwise the response has to
+// contain exactly one result.
+func Decode(response interfaces.Response, out interface{}) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("Decode target has to be a non nil pointer but is %T", out)
+	}
+
+	if response.IsEmpty() {
+		return nil
+	}
+
+	results, err := parseGraphSONData(response.Result.Data)
+	if err != nil {
+		return err
+	}
+
+	var source interface{} = results
+	if target.Elem().Kind() != reflect.Slice && target.Elem().Kind() != reflect.Array {
+		if len(results) != 1 {
+			return fmt.Errorf("Response contains %d results, expected exactly one (use a slice as target instead)", len(results))
+		}
+		source = results[0]
+	}
+
+	if err := decodeInto(source, out); err != nil {
+		return errors.Wrapf(err, "Decoding response into %T failed", out)
+	}
+	return nil
+}
+
+// UnmarshalGraphSON unmarshals the given GraphSON data into target. In contrast to Decode the data is not expected to be
+// the (list of) results of a response, but can be any GraphSON value, e.g. {"@type":"g:Int64","@value":9147}.
+// The GraphSON envelopes are unwrapped into native go types:
+//	g:Int32 -> int32, g:Int64 -> int64, g:Float -> float32, g:Double -> float64, g:Date/ g:Timestamp -> time.Time (UTC),
+//	g:UUID -> string, g:List/ g:Set -> []interface{} and g:Map -> map[string]interface{}
+// Bulked traversers (g:Traverser) contained in lists are expanded. A single traverser is unwrapped into its value,
+// unless the target is a pointer to a Traverser.
+// The target can be a pointer to a TypedValue, to an interface{} or to any type that can be decoded using 'mapstructure' tags.
+func UnmarshalGraphSON(data json.RawMessage, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return fmt.Errorf("Unmarshal target has to be a non nil pointer but is %T", target)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return err
+	}
+
+	value, err := fromGraphSON(parsed)
+	if err != nil {
+		return err
+	}
+
+	if traverser, ok := value.(Traverser); ok {
+		if casted, ok := target.(*Traverser); ok {
+			*casted = traverser
+			return nil
+		}
+		value = traverser.Value
+	}
+
+	switch casted := target.(type) {
+	case *TypedValue:
+		casted.Value = value
+		return nil
+	case *interface{}:
+		*casted = value
+		return nil
+	}
+
+	if err := decodeInto(value, target); err != nil {
+		return errors.Wrapf(err, "Unmarshalling GraphSON into %T failed", target)
+	}
+	return nil
+}
+
+// decodeInto decodes the given source (plain go types without GraphSON envelopes) into out
+func decodeInto(source interface{}, out interface{}) error {
+	config := &mapstructure.DecoderConfig{
+		Result:           out,
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.ComposeDecodeHookFunc(unwrapSingleElementListHook, stringToTimeHook),
+	}
+
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(source)
+}
+
+// parseGraphSONData parses the given GraphSON data and returns the contained results
+// as plain go types.
+func parseGraphSONData(data []byte) ([]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	value, err := fromGraphSON(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	results, ok := value.([]interface{})
+	if !ok {
+		return expandTraversers([]interface{}{value})
+	}
+	return results, nil
+}
+
+// ParseTraversers parses the given GraphSON data (the results of a response) and returns one Traverser per result.
+// In contrast to Decode the bulked traversers (g:Traverser) are not expanded but their bulk count is exposed.
+// This is useful for queries where the bulk encodes a count (e.g. groupCount-style queries), since expanding them
+// might result in a huge number of identical results. Results that are no traversers have a bulk of 1.
+//	[{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"tree"}},"leaf"]
+// becomes
+//	[]Traverser{{Bulk: 3, Value: "tree"}, {Bulk: 1, Value: "leaf"}}
+func ParseTraversers(data json.RawMessage) ([]Traverser, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	// unwrap a typed list, e.g. {"@type":"g:List","@value":[...]}
+	if envelope, ok := parsed.(map[string]interface{}); ok {
+		if typ, ok := envelope[graphSONTypeKey].(string); ok && (typ == graphSONTypeList || typ == graphSONTypeSet) {
+			parsed = envelope[graphSONValueKey]
+		}
+	}
+
+	results, ok := parsed.([]interface{})
+	if !ok {
+		results = []interface{}{parsed}
+	}
+
+	traversers := make([]Traverser, 0, len(results))
+	for _, result := range results {
+		value, err := fromGraphSON(result)
+		if err != nil {
+			return nil, err
+		}
+
+		traverser, ok := value.(Traverser)
+		if !ok {
+			traverser = Traverser{Bulk: 1, Value: value}
+		}
+		traversers = append(traversers, traverser)
+	}
+	return traversers, nil
+}
+
+// fromGraphSONTraverser converts the value of a g:Traverser envelope into a Traverser
+func fromGraphSONTraverser(value interface{}) (Traverser, error) {
+	traverser, ok := value.(map[string]interface{})
+	if !ok {
+		return Traverser{}, fmt.Errorf("Value of %s is not a map but %T", graphSONTypeTraverser, value)
+	}
+
+	// the bulk is optional, a missing bulk means 1
+	var bulk int64 = 1
+	if rawBulk, ok := traverser["bulk"]; ok {
+		parsedBulk, err := fromGraphSON(rawBulk)
+		if err != nil {
+			return Traverser{}, err
+		}
+		switch casted := parsedBulk.(type) {
+		case int64:
+			bulk = casted
+		case int32:
+			bulk = int64(casted)
+		default:
+			return Traverser{}, fmt.Errorf("Bulk of %s is not an integer but %T", graphSONTypeTraverser, parsedBulk)
+		}
+	}
+
+	if bulk < 1 {
+		return Traverser{}, fmt.Errorf("Bulk of %s has to be >=1 but is %d", graphSONTypeTraverser, bulk)
+	}
+
+	result, err := fromGraphSON(traverser["value"])
+	if err != nil {
+		return Traverser{}, err
+	}
+	return Traverser{Bulk: bulk, Value: result}, nil
+}
+
+// expandTraversers replaces each traverser in the given list by bulk times its value.
+// It fails in case the expanded list would contain more than MaxExpandedTraversers elements.
+func expandTraversers(list []interface{}) ([]interface{}, error) {
+	var total int64
+	for _, element := range list {
+		bulk := int64(1)
+		if traverser, ok := element.(Traverser); ok {
+			bulk = traverser.Bulk
+		}
+		total += bulk
+		if total > MaxExpandedTraversers {
+			return nil, fmt.Errorf("expanding the bulked %s results exceeds the maximum of %d results, use ParseTraversers to obtain the bulk counts instead", graphSONTypeTraverser, MaxExpandedTraversers)
+		}
+	}
+
+	result := make([]interface{}, 0, total)
+	for _, element := range list {
+		traverser, ok := element.(Traverser)
+		if !ok {
+			result = append(result, element)
+			continue
+		}
+		for i := int64(0); i < traverser.Bulk; i++ {
+			result = append(result, traverser.Value)
+		}
+	}
+	return result, nil
+}
+
+// fromGraphSON converts the given json value (as returned by json.Unmarshal) into plain go types
+// by removing all GraphSON envelopes.
+func fromGraphSON(value interface{}) (interface{}, error) {
+	switch casted := value.(type) {
+	case []interface{}:
+		return fromGraphSONList(casted)
+	case map[string]interface{}:
+		if typ, ok := casted[graphSONTypeKey].(string); ok {
+			return fromGraphSONTyped(typ, casted[graphSONValueKey])
+		}
+		if isElement(casted) {
+			return flattenElement(casted)
+		}
+		return fromGraphSONMap(casted)
+	case json.Number:
+		return fromJSONNumber(casted)
+	default:
+		return casted, nil
+	}
+}
+
+// fromGraphSONTyped converts the value of a GraphSON envelope of the given type into a plain go type.
+func fromGraphSONTyped(typ string, value interface{}) (interface{}, error) {
+	switch typ {
+	case graphSONTypeInt32:
+		number, err := toJSONNumber(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		asInt, err := number.Int64()
+		return int32(asInt), err
+	case graphSONTypeInt64:
+		number, err := toJSONNumber(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		return number.Int64()
+	case graphSONTypeFloat:
+		number, err := toJSONNumber(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		asFloat, err := number.Float64()
+		return float32(asFloat), err
+	case graphSONTypeDouble:
+		number, err := toJSONNumber(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		return number.Float64()
+	case graphSONTypeDate, graphSONTypeTimestamp:
+		number, err := toJSONNumber(typ, value)
+		if err != nil {
+			return nil, err
+		}
+		millis, err := number.Int64()
+		if err != nil {
+			return nil, err
+		}
+		return time.Unix(0, millis*int64(time.Millisecond)).UTC(), nil
+	case graphSONTypeUUID:
+		return fmt.Sprintf("%v", value), nil
+	case graphSONTypeList, graphSONTypeSet:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Value of %s is not a list but %T", typ, value)
+		}
+		return fromGraphSONList(list)
+	case graphSONTypeMap:
+		return fromGraphSONTypedMap(value)
+	case graphSONTypeVertex, graphSONTypeEdge:
+		element, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Value of %s is not a map but %T", typ, value)
+		}
+		return flattenElement(element)
+	case graphSONTypeVertexProperty, graphSONTypeProperty:
+		property, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Value of %s is not a map but %T", typ, value)
+		}
+		return fromGraphSON(property["value"])
+	case graphSONTypeTraverser:
+		return fromGraphSONTraverser(value)
+	default:
+		// unknown types are just unwrapped
+		return fromGraphSON(value)
+	}
+}
+
+// fromGraphSONTypedMap converts the value of a g:Map envelope into a map.
+// In GraphSON v3 the value is a flat list of alternating keys and values, in v2 it is a json object.
+func fromGraphSONTypedMap(value interface{}) (interface{}, error) {
+	switch casted := value.(type) {
+	case map[string]interface{}:
+		return fromGraphSONMap(casted)
+	case []interface{}:
+		if len(casted)%2 != 0 {
+			return nil, fmt.Errorf("Value of %s has an odd number of elements (%d)", graphSONTypeMap, len(casted))
+		}
+		result := make(map[string]interface{}, len(casted)/2)
+		for i := 0; i < len(casted); i += 2 {
+			key, err := fromGraphSON(casted[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := fromGraphSON(casted[i+1])
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprintf("%v", key)] = value
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("Value of %s is neither a map nor a list but %T", graphSONTypeMap, value)
+	}
+}
+
+func fromGraphSONList(list []interface{}) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(list))
+	for _, element := range list {
+		value, err := fromGraphSON(element)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return expandTraversers(result)
+}
+
+func fromGraphSONMap(input map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(input))
+	for key, element := range input {
+		value, err := fromGraphSON(element)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// isElement returns true in case the given map represents a (GraphSON untyped) vertex or edge
+// as it is returned by the CosmosDB.
+func isElement(input map[string]interface{}) bool {
+	typ, ok := input["type"].(string)
+	if !ok {
+		return false
+	}
+	if _, ok := input["id"]; !ok {
+		return false
+	}
+	return typ == string(TypeVertex) || typ == string(TypeEdge)
+}
+
+// flattenElement converts a vertex or edge into a map where id, label and all
+// properties are located on the top level.
+//	{"id":"1","label":"user","properties":{"name":[{"id":"p1","value":"hans"}]}}
+// becomes
+//	{"id":"1","label":"user","name":["hans"]}
+func flattenElement(element map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(element))
+	for key, entry := range element {
+		if key == "properties" {
+			continue
+		}
+		value, err := fromGraphSON(entry)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	properties, ok := element["properties"].(map[string]interface{})
+	if !ok {
+		return result, nil
+	}
+
+	for key, entry := range properties {
+		// don't override the id or label of the element
+		if _, exists := result[key]; exists {
+			continue
+		}
+
+		value, err := fromGraphSONProperty(entry)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// fromGraphSONProperty converts a property of a vertex or edge. For vertices the property
+// is a list of vertex properties ({"id":"..","value":".."}), for edges it is the value itself.
+func fromGraphSONProperty(property interface{}) (interface{}, error) {
+	list, ok := property.([]interface{})
+	if !ok {
+		return fromPropertyValue(property)
+	}
+
+	values := make([]interface{}, 0, len(list))
+	for _, entry := range list {
+		value, err := fromPropertyValue(entry)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// fromPropertyValue extracts the value of a (vertex-) property. The property might be
+// wrapped in a GraphSON envelope.
+func fromPropertyValue(property interface{}) (interface{}, error) {
+	casted, ok := property.(map[string]interface{})
+	if !ok {
+		return fromGraphSON(property)
+	}
+
+	if _, ok := casted[graphSONTypeKey]; ok {
+		return fromGraphSON(casted)
+	}
+
+	if value, ok := casted["value"]; ok {
+		return fromGraphSON(value)
+	}
+	return fromGraphSON(casted)
+}
+
+func toJSONNumber(typ string, value interface{}) (json.Number, error) {
+	switch casted := value.(type) {
+	case json.Number:
+		return casted, nil
+	case float64:
+		return json.Number(fmt.Sprintf("%v", casted)), nil
+	case string:
+		return json.Number(casted), nil
+	default:
+		return "", fmt.Errorf("Value of %s is not a number but %T", typ, value)
+	}
+}
+
+// fromJSONNumber converts the given number into an int64 if possible, into a float64 otherwise.
+func fromJSONNumber(number json.Number) (interface{}, error) {
+	if asInt, err := number.Int64(); err == nil {
+		return asInt, nil
+	}
+	return number.Float64()
+}
+
+// unwrapSingleElementListHook is a mapstructure decode hook that unwraps lists with exactly one
+// element in case the target is not a list.
+// This is needed since CosmosDB returns each property value as list (e.g. "source":["tree"]).
+func unwrapSingleElementListHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from == nil || from.Kind() != reflect.Slice {
+		return data, nil
+	}
+
+	switch to.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Interface:
+		return data, nil
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Len() != 1 {
+		return data, nil
+	}
+	return value.Index(0).Interface(), nil
+}
+
+// stringToTimeHook is a mapstructure decode hook that converts RFC3339 formatted strings into time.Time.
+func stringToTimeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from == nil || from.Kind() != reflect.String || to != reflect.TypeOf(time.Time{}) {
+		return data, nil
+	}
+	return time.Parse(time.RFC3339Nano, reflect.ValueOf(data).String())
+}
+
+// valueMapConfig holds the settings used by ParseValueMap
+type valueMapConfig struct {
+	keepArrays bool
+}
+
+// ValueMapOption is an option that can be passed to ParseValueMap
+type ValueMapOption func(*valueMapConfig)
+
+// KeepArrays keeps the single-element arrays intact, e.g. "source":["tree"] is parsed as []interface{}{"tree"}
+func KeepArrays() ValueMapOption {
+	return func(config *valueMapConfig) {
+		config.keepArrays = true
+	}
+}
+
+// ParseValueMap parses one entry of a valueMap() result, e.g. {"id":9147,"label":"EmployeeBulkData","source":["tree"]}.
+// Since the CosmosDB returns each property value as array, single-element arrays are collapsed into scalars ("source":"tree"),
+// while arrays of multiple elements (multi-value properties) are preserved. This can be turned off using the option KeepArrays.
+// The GraphSON envelopes like {"@type":"g:Int64","@value":9147} are unwrapped as well.
+func ParseValueMap(data json.RawMessage, options ...ValueMapOption) (map[string]interface{}, error) {
+	config := valueMapConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+
+	results, err := parseGraphSONData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("Data contains %d entries, expected exactly one valueMap entry", len(results))
+	}
+
+	valueMap, ok := results[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Data is not a valueMap entry but %T", results[0])
+	}
+
+	if config.keepArrays {
+		return valueMap, nil
+	}
+
+	for key, value := range valueMap {
+		if list, ok := value.([]interface{}); ok && len(list) == 1 {
+			valueMap[key] = list[0]
+		}
+	}
+	return valueMap, nil
+}
@@ -0,0 +1,401 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// dataValueMapCosmos is a typical response of a g.V().valueMap(true) query from Cosmos
+const dataValueMapCosmos = `[
+	{
+		"id": {"@type":"g:Int64","@value":9147},
+		"label": "EmployeeBulkData",
+		"source": ["tree"],
+		"timestamp": ["2018-07-01T13:37:45-05:00"],
+		"rating": [{"@type":"g:Double","@value":4.5}],
+		"tags": ["a","b"]
+	},
+	{
+		"id": {"@type":"g:Int64","@value":9148},
+		"label": "EmployeeBulkData",
+		"source": ["forest"],
+		"timestamp": ["2018-07-02T13:37:45-05:00"],
+		"rating": [{"@type":"g:Double","@value":3}],
+		"tags": ["c"]
+	}
+]`
+
+type employee struct {
+	ID        int64     `mapstructure:"id"`
+	Label     string    `mapstructure:"label"`
+	Source    string    `mapstructure:"source"`
+	Timestamp time.Time `mapstructure:"timestamp"`
+	Rating    float64   `mapstructure:"rating"`
+	Tags      []string  `mapstructure:"tags"`
+}
+
+func TestDecodeValueMap(t *testing.T) {
+	// GIVEN
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(dataValueMapCosmos)}}
+	var employees []employee
+
+	// WHEN
+	err := Decode(response, &employees)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, employees, 2)
+	assert.Equal(t, int64(9147), employees[0].ID)
+	assert.Equal(t, "EmployeeBulkData", employees[0].Label)
+	assert.Equal(t, "tree", employees[0].Source)
+	assert.Equal(t, time.Date(2018, 7, 1, 18, 37, 45, 0, time.UTC), employees[0].Timestamp.UTC())
+	assert.Equal(t, 4.5, employees[0].Rating)
+	assert.Equal(t, []string{"a", "b"}, employees[0].Tags)
+	assert.Equal(t, int64(9148), employees[1].ID)
+	assert.Equal(t, "forest", employees[1].Source)
+	assert.Equal(t, float64(3), employees[1].Rating)
+	assert.Equal(t, []string{"c"}, employees[1].Tags)
+}
+
+func TestDecodeSingle(t *testing.T) {
+	// GIVEN
+	data := `[{"id":"1","label":"user","name":["hans"]}]`
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(data)}}
+	var user struct {
+		ID   string `mapstructure:"id"`
+		Name string `mapstructure:"name"`
+	}
+
+	// WHEN
+	err := Decode(response, &user)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "1", user.ID)
+	assert.Equal(t, "hans", user.Name)
+}
+
+func TestDecodeVertex(t *testing.T) {
+	// GIVEN
+	data := `{"@type":"g:List","@value":[{
+		"@type":"g:Vertex",
+		"@value":{
+			"id":{"@type":"g:Int64","@value":1},
+			"label":"person",
+			"properties":{
+				"name":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":0},"value":"marko","label":"name"}}],
+				"age":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":2},"value":{"@type":"g:Int32","@value":29},"label":"age"}}],
+				"birthday":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":3},"value":{"@type":"g:Date","@value":1530470265000},"label":"birthday"}}]
+			}
+		}
+	}]}`
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(data)}}
+	var person struct {
+		ID       int64     `mapstructure:"id"`
+		Label    string    `mapstructure:"label"`
+		Name     string    `mapstructure:"name"`
+		Age      int       `mapstructure:"age"`
+		Birthday time.Time `mapstructure:"birthday"`
+	}
+
+	// WHEN
+	err := Decode(response, &person)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), person.ID)
+	assert.Equal(t, "person", person.Label)
+	assert.Equal(t, "marko", person.Name)
+	assert.Equal(t, 29, person.Age)
+	assert.Equal(t, time.Date(2018, 7, 1, 18, 37, 45, 0, time.UTC), person.Birthday)
+}
+
+func TestDecodeCosmosVertexNested(t *testing.T) {
+	// GIVEN
+	data := `[{
+		"id":"8fff9259",
+		"label":"device",
+		"type":"vertex",
+		"properties":{
+			"vendor":[{"id":"p1","value":"example"}],
+			"location":[{"id":"p2","value":{"building":"A","floor":3}}]
+		}
+	}]`
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(data)}}
+	type location struct {
+		Building string `mapstructure:"building"`
+		Floor    int    `mapstructure:"floor"`
+	}
+	var devices []struct {
+		ID       string   `mapstructure:"id"`
+		Vendor   string   `mapstructure:"vendor"`
+		Location location `mapstructure:"location"`
+	}
+
+	// WHEN
+	err := Decode(response, &devices)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "8fff9259", devices[0].ID)
+	assert.Equal(t, "example", devices[0].Vendor)
+	assert.Equal(t, "A", devices[0].Location.Building)
+	assert.Equal(t, 3, devices[0].Location.Floor)
+}
+
+func TestDecodeFail(t *testing.T) {
+	// GIVEN
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(dataValueMapCosmos)}}
+	var single employee
+	var notAPointer employee
+
+	// WHEN
+	errNoPointer := Decode(response, notAPointer)
+	errNil := Decode(response, nil)
+	errMultiple := Decode(response, &single)
+	errInvalid := Decode(interfaces.Response{Result: interfaces.Result{Data: []byte(`[{"@type":"g:Int64","@value":"abc"}]`)}}, &single)
+
+	// THEN
+	assert.Error(t, errNoPointer)
+	assert.Error(t, errNil)
+	assert.Error(t, errMultiple)
+	assert.Error(t, errInvalid)
+}
+
+func TestDecodeEmpty(t *testing.T) {
+	// GIVEN
+	response := interfaces.Response{}
+	var employees []employee
+
+	// WHEN
+	err := Decode(response, &employees)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Empty(t, employees)
+}
+
+func TestUnmarshalGraphSONWrappers(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	millis := int64(1530470265000)
+	testCases := []struct {
+		data     string
+		expected interface{}
+	}{
+		{`{"@type":"g:Int32","@value":42}`, int32(42)},
+		{`{"@type":"g:Int64","@value":9147}`, int64(9147)},
+		{`{"@type":"g:Double","@value":4.5}`, float64(4.5)},
+		{`{"@type":"g:Float","@value":1.5}`, float32(1.5)},
+		{`{"@type":"g:Date","@value":1530470265000}`, time.Unix(0, millis*int64(time.Millisecond)).UTC()},
+		{`{"@type":"g:UUID","@value":"41d2e28a-20a4-4ab0-b379-d810dede3786"}`, "41d2e28a-20a4-4ab0-b379-d810dede3786"},
+		{`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":1},"two"]}`, []interface{}{int32(1), "two"}},
+		{`{"@type":"g:Map","@value":["a",{"@type":"g:Int64","@value":1},"b","c"]}`, map[string]interface{}{"a": int64(1), "b": "c"}},
+	}
+
+	for _, testCase := range testCases {
+		// WHEN
+		var value TypedValue
+		err := UnmarshalGraphSON([]byte(testCase.data), &value)
+
+		// THEN
+		require.NoError(t, err, testCase.data)
+		assert.Equal(t, testCase.expected, value.Value, testCase.data)
+	}
+}
+
+func TestUnmarshalGraphSONNestedMap(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	type stats struct {
+		Count   int64              `mapstructure:"count"`
+		Average float64            `mapstructure:"average"`
+		Since   time.Time          `mapstructure:"since"`
+		Groups  map[string]int32   `mapstructure:"groups"`
+		Ids     []string           `mapstructure:"ids"`
+		Extra   map[string]float64 `mapstructure:"extra"`
+	}
+	data := `{"@type":"g:Map","@value":[
+		"count",{"@type":"g:Int64","@value":9147},
+		"average",{"@type":"g:Double","@value":2.5},
+		"since",{"@type":"g:Date","@value":0},
+		"groups",{"@type":"g:Map","@value":["admin",{"@type":"g:Int32","@value":2},"user",{"@type":"g:Int32","@value":7}]},
+		"ids",{"@type":"g:List","@value":[{"@type":"g:UUID","@value":"41d2e28a-20a4-4ab0-b379-d810dede3786"}]},
+		"extra",{"rating":{"@type":"g:Float","@value":0.5}}
+	]}`
+
+	// WHEN
+	var result stats
+	err := UnmarshalGraphSON([]byte(data), &result)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(9147), result.Count)
+	assert.Equal(t, 2.5, result.Average)
+	assert.Equal(t, time.Unix(0, 0).UTC(), result.Since)
+	assert.Equal(t, map[string]int32{"admin": 2, "user": 7}, result.Groups)
+	assert.Equal(t, []string{"41d2e28a-20a4-4ab0-b379-d810dede3786"}, result.Ids)
+	assert.Equal(t, map[string]float64{"rating": 0.5}, result.Extra)
+}
+
+func TestUnmarshalGraphSONFail(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	var value TypedValue
+	var notAPointer TypedValue
+
+	// WHEN + THEN
+	assert.Error(t, UnmarshalGraphSON([]byte(`{"@type":"g:Int64","@value":"abc"}`), &value))
+	assert.Error(t, UnmarshalGraphSON([]byte(`invalid`), &value))
+	assert.Error(t, UnmarshalGraphSON([]byte(`{"@type":"g:Int64","@value":1}`), notAPointer))
+}
+
+// dataValueMapEntry is one entry of the valueMap(true) result of the integration test
+const dataValueMapEntry = `{
+	"id":{"@type":"g:Int64","@value":9147},
+	"label":"EmployeeBulkData",
+	"source":["tree"],
+	"timestamp":["2018-07-01T13:37:45-05:00"],
+	"tags":["a","b"]
+}`
+
+func TestParseValueMap(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+
+	// WHEN
+	valueMap, err := ParseValueMap([]byte(dataValueMapEntry))
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"id":        int64(9147),
+		"label":     "EmployeeBulkData",
+		"source":    "tree",
+		"timestamp": "2018-07-01T13:37:45-05:00",
+		"tags":      []interface{}{"a", "b"},
+	}, valueMap)
+}
+
+func TestParseValueMapKeepArrays(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+
+	// WHEN
+	valueMap, err := ParseValueMap([]byte(dataValueMapEntry), KeepArrays())
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"tree"}, valueMap["source"])
+	assert.Equal(t, []interface{}{"2018-07-01T13:37:45-05:00"}, valueMap["timestamp"])
+	assert.Equal(t, []interface{}{"a", "b"}, valueMap["tags"])
+}
+
+func TestParseValueMapFail(t *testing.T) {
+	t.Parallel()
+	// WHEN + THEN
+	_, err := ParseValueMap([]byte(`invalid`))
+	assert.Error(t, err)
+	_, err = ParseValueMap([]byte(`"just a string"`))
+	assert.Error(t, err)
+	_, err = ParseValueMap([]byte(dataValueMapCosmos))
+	assert.Error(t, err, "multiple entries")
+}
+
+func TestDecodeExpandsTraversers(t *testing.T) {
+	// GIVEN
+	data := `[
+		{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"tree"}},
+		{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":1},"value":"leaf"}},
+		"root"
+	]`
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(data)}}
+
+	// WHEN
+	var results []string
+	err := Decode(response, &results)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tree", "tree", "tree", "leaf", "root"}, results)
+}
+
+func TestDecodeExpandTraversersLimit(t *testing.T) {
+	// GIVEN
+	data := `[{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":1000000000},"value":"tree"}}]`
+	dataAtLimit := fmt.Sprintf(`[{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":%d},"value":"tree"}}]`, MaxExpandedTraversers)
+	response := interfaces.Response{Result: interfaces.Result{Data: []byte(data)}}
+
+	// WHEN
+	var results []string
+	err := Decode(response, &results)
+	var resultsUnmarshal []string
+	errUnmarshal := UnmarshalGraphSON([]byte(data), &resultsUnmarshal)
+	var resultsAtLimit []string
+	errAtLimit := UnmarshalGraphSON([]byte(dataAtLimit), &resultsAtLimit)
+	traversers, errParse := ParseTraversers([]byte(data))
+
+	// THEN
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ParseTraversers")
+	assert.Empty(t, results)
+	assert.Error(t, errUnmarshal)
+	require.NoError(t, errAtLimit)
+	assert.Len(t, resultsAtLimit, MaxExpandedTraversers)
+	require.NoError(t, errParse)
+	assert.Equal(t, []Traverser{{Bulk: 1000000000, Value: "tree"}}, traversers)
+}
+
+func TestUnmarshalGraphSONTraverser(t *testing.T) {
+	// GIVEN
+	data := []byte(`{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":2},"value":{"@type":"g:Int32","@value":42}}}`)
+
+	// WHEN
+	var traverser Traverser
+	errTraverser := UnmarshalGraphSON(data, &traverser)
+	var value TypedValue
+	errValue := UnmarshalGraphSON(data, &value)
+
+	// THEN
+	require.NoError(t, errTraverser)
+	require.NoError(t, errValue)
+	assert.Equal(t, Traverser{Bulk: 2, Value: int32(42)}, traverser)
+	assert.Equal(t, int32(42), value.AsInt32())
+}
+
+func TestParseTraversers(t *testing.T) {
+	// GIVEN
+	data := []byte(`{"@type":"g:List","@value":[
+		{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"tree"}},
+		{"@type":"g:Traverser","@value":{"value":"leaf"}},
+		"root"
+	]}`)
+
+	// WHEN
+	traversers, err := ParseTraversers(data)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []Traverser{{Bulk: 3, Value: "tree"}, {Bulk: 1, Value: "leaf"}, {Bulk: 1, Value: "root"}}, traversers)
+}
+
+func TestParseTraversersFail(t *testing.T) {
+	// WHEN + THEN
+	_, err := ParseTraversers([]byte(`[{"@type":"g:Traverser","@value":{"bulk":0,"value":"tree"}}]`))
+	assert.Error(t, err, "bulk < 1")
+
+	_, err = ParseTraversers([]byte(`[{"@type":"g:Traverser","@value":{"bulk":"three","value":"tree"}}]`))
+	assert.Error(t, err, "bulk no integer")
+
+	_, err = ParseTraversers([]byte(`[{"@type":"g:Traverser","@value":"tree"}]`))
+	assert.Error(t, err, "no map")
+
+	_, err = ParseTraversers([]byte(`[`))
+	assert.Error(t, err, "invalid json")
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+type predicate struct {
+	value string
+}
+
+func (p *predicate) String() string {
+	return p.value
+}
+
+// And combines the predicate with the given one to <predicate>.and(<other>), e.g. gt(10).and(lt(20)).
+// It panics in case the given predicate is nil.
+func (p *predicate) And(other interfaces.Predicate) interfaces.Predicate {
+	return p.connect("and", other)
+}
+
+// Or combines the predicate with the given one to <predicate>.or(<other>), e.g. lt(10).or(gt(20)).
+// It panics in case the given predicate is nil.
+func (p *predicate) Or(other interfaces.Predicate) interfaces.Predicate {
+	return p.connect("or", other)
+}
+
+// Negate creates the predicate <predicate>.negate(), e.g. within("a","b").negate().
+func (p *predicate) Negate() interfaces.Predicate {
+	return &predicate{
+		value: p.value + ".negate()",
+	}
+}
+
+// connect combines the predicate with the given one using the given connective (and/ or)
+func (p *predicate) connect(connective string, other interfaces.Predicate) *predicate {
+	if other == nil {
+		panic(errors.Errorf("the predicate to %s is nil", connective))
+	}
+	return &predicate{
+		value: p.value + "." + connective + "(" + other.String() + ")",
+	}
+}
+
+// Within creates the predicate within(<value_1>,<value_2>,..,<value_n>), e.g. within("user","admin").
+// It matches if the value is equal to one of the given values.
+// Depending on the given type of the values the quotes are omitted, e.g. within(1,2,3).
+func Within(values ...interface{}) interfaces.Predicate {
+	return multiValuePredicate("within", values...)
+}
+
+// Between creates the predicate between(<lower>,<upper>), e.g. between(47.1,47.9).
+// It matches if the value is greater than or equal to lower and less than upper.
+// Depending on the given type of the values the quotes are omitted.
+func Between(lower, upper interface{}) interfaces.Predicate {
+	return multiValuePredicate("between", lower, upper)
+}
+
+// Gt creates the predicate gt(<value>), e.g. gt(10). It matches if the value is greater than the given one.
+// Depending on the given type of the value the quotes are omitted.
+func Gt(value interface{}) interfaces.Predicate {
+	return multiValuePredicate("gt", value)
+}
+
+// Gte creates the predicate gte(<value>), e.g. gte(10). It matches if the value is greater than or equal to the given one.
+// Depending on the given type of the value the quotes are omitted.
+func Gte(value interface{}) interfaces.Predicate {
+	return multiValuePredicate("gte", value)
+}
+
+// Lt creates the predicate lt(<value>), e.g. lt(10). It matches if the value is less than the given one.
+// Depending on the given type of the value the quotes are omitted.
+func Lt(value interface{}) interfaces.Predicate {
+	return multiValuePredicate("lt", value)
+}
+
+// Lte creates the predicate lte(<value>), e.g. lte(10). It matches if the value is less than or equal to the given one.
+// Depending on the given type of the value the quotes are omitted.
+func Lte(value interface{}) interfaces.Predicate {
+	return multiValuePredicate("lte", value)
+}
+
+// multiValuePredicate creates a predicate with the given name and the given values as parameters.
+func multiValuePredicate(name string, values ...interface{}) *predicate {
+	valueStrs := make([]string, 0, len(values))
+	for _, value := range values {
+		valueStr, err := toValueString(value)
+		if err != nil {
+			panic(errors.Wrapf(err, "cast %s value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", name, value))
+		}
+		valueStrs = append(valueStrs, valueStr)
+	}
+
+	return &predicate{
+		value: name + "(" + strings.Join(valueStrs, ",") + ")",
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithin(t *testing.T) {
+	// GIVEN
+	now := "now"
+
+	// WHEN
+	pStrings := Within("a", "b")
+	pMixed := Within(1, true, 2.5, now)
+	pEmpty := Within()
+
+	// THEN
+	assert.Equal(t, `within("a","b")`, pStrings.String())
+	assert.Equal(t, `within(1,true,2.5,"now")`, pMixed.String())
+	assert.Equal(t, `within()`, pEmpty.String())
+}
+
+func TestHasWithin(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+
+	// WHEN
+	v := g.V().Has("name", Within("hans", "max"))
+
+	// THEN
+	assert.Equal(t, `g.V().has("name",within("hans","max"))`, v.String())
+}
+
+func TestWithinNil(t *testing.T) {
+	// WHEN + THEN
+	assert.Panics(t, func() { Within("a", nil) })
+}
+
+func TestBetween(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	pFloat := Between(47.1, 47.9)
+	pInt := Between(1, 10)
+	v := g.V().Has("lat", Between(47.1, 47.9)).Has("lon", Between(8.2, 8.9))
+
+	// THEN
+	assert.Equal(t, `between(47.1,47.9)`, pFloat.String())
+	assert.Equal(t, `between(1,10)`, pInt.String())
+	assert.Equal(t, fmt.Sprintf(`%s.V().has("lat",between(47.1,47.9)).has("lon",between(8.2,8.9))`, graphName), v.String())
+	assert.Panics(t, func() { Between(nil, 1) })
+}
+
+func TestComparisonPredicates(t *testing.T) {
+	// WHEN
+	pGt := Gt(10)
+	pGte := Gte(2.5)
+	pLt := Lt("m")
+	pLte := Lte(20)
+
+	// THEN
+	assert.Equal(t, `gt(10)`, pGt.String())
+	assert.Equal(t, `gte(2.5)`, pGte.String())
+	assert.Equal(t, `lt("m")`, pLt.String())
+	assert.Equal(t, `lte(20)`, pLte.String())
+	assert.Panics(t, func() { Gt(nil) })
+}
+
+func TestPredicateComposition(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+
+	// WHEN
+	pAnd := Gt(10).And(Lt(20))
+	pOr := Lt(10).Or(Gt(20).And(Lte(30)))
+	pNegate := Within("a", "b").Negate()
+	v := g.V().Has("age", Gte(18).And(Lt(65)))
+
+	// THEN
+	assert.Equal(t, `gt(10).and(lt(20))`, pAnd.String())
+	assert.Equal(t, `lt(10).or(gt(20).and(lte(30)))`, pOr.String())
+	assert.Equal(t, `within("a","b").negate()`, pNegate.String())
+	assert.Equal(t, `g.V().has("age",gte(18).and(lt(65)))`, v.String())
+	assert.Panics(t, func() { Gt(10).And(nil) })
+	assert.Panics(t, func() { Gt(10).Or(nil) })
+}
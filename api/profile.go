@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// Profile represents the result of a profiled query.
+// As it would be returned by a call to .executionProfile() (CosmosDB) or .profile() (TinkerPop).
+type Profile struct {
+	// Query is the profiled query (only provided by the CosmosDB)
+	Query string
+	// Duration is the total time spent to execute the query
+	Duration time.Duration
+	// Metrics contains the metrics of each step of the query
+	Metrics []ProfileMetric
+}
+
+// ProfileMetric represents the metrics of one step of a profiled query.
+type ProfileMetric struct {
+	// Name is the name of the step
+	Name string
+	// Duration is the time spent in this step
+	Duration time.Duration
+	// Count is the number of results/ elements produced by this step
+	Count int64
+}
+
+// ToProfiles converts the given input byte array into an array of Profile type.
+// Both the format of the CosmosDB (.executionProfile()) and the format of TinkerPop (.profile()) are supported.
+// The method will fail in case the data in the given byte array does not contain profiling information.
+func ToProfiles(input []byte) ([]Profile, error) {
+	if input == nil {
+		return nil, fmt.Errorf("Data is nil")
+	}
+
+	results, err := parseGraphSONData(input)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]Profile, 0, len(results))
+	for _, result := range results {
+		profile, err := toProfile(result)
+		if err != nil {
+			return nil, errors.Wrap(err, "Mapping of response to Profile failed. Please ensure that the response contains only profiling information.")
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func toProfile(input interface{}) (Profile, error) {
+	profileMap, ok := input.(map[string]interface{})
+	if !ok {
+		return Profile{}, fmt.Errorf("Failed to cast %v (%T) into map[string]interface{}", input, input)
+	}
+
+	metricsRaw, ok := profileMap["metrics"].([]interface{})
+	if !ok {
+		return Profile{}, fmt.Errorf("'metrics' are missing")
+	}
+
+	profile := Profile{
+		Query:    cast.ToString(profileMap["gremlin"]),
+		Duration: toProfileDuration(profileMap, "totalTime", "dur"),
+		Metrics:  make([]ProfileMetric, 0, len(metricsRaw)),
+	}
+
+	for _, metricRaw := range metricsRaw {
+		metricMap, ok := metricRaw.(map[string]interface{})
+		if !ok {
+			return Profile{}, fmt.Errorf("Failed to cast metric %v (%T) into map[string]interface{}", metricRaw, metricRaw)
+		}
+
+		metric := ProfileMetric{
+			Name:     cast.ToString(metricMap["name"]),
+			Duration: toProfileDuration(metricMap, "time", "dur"),
+		}
+
+		if counts, ok := metricMap["counts"].(map[string]interface{}); ok {
+			metric.Count = toProfileCount(counts, "resultCount", "elementCount")
+		}
+		profile.Metrics = append(profile.Metrics, metric)
+	}
+	return profile, nil
+}
+
+// toProfileDuration returns the duration (given in ms) of the first of the given keys found in the map.
+func toProfileDuration(input map[string]interface{}, keys ...string) time.Duration {
+	for _, key := range keys {
+		if value, ok := input[key]; ok {
+			return time.Duration(cast.ToFloat64(value) * float64(time.Millisecond))
+		}
+	}
+	return 0
+}
+
+// toProfileCount returns the count of the first of the given keys found in the map.
+func toProfileCount(input map[string]interface{}, keys ...string) int64 {
+	for _, key := range keys {
+		if value, ok := input[key]; ok {
+			return cast.ToInt64(value)
+		}
+	}
+	return 0
+}
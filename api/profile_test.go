@@ -0,0 +1,72 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dataExecutionProfileCosmos = `[{
+	"gremlin": "g.V().hasLabel('tweet').out()",
+	"totalTime": 28,
+	"metrics": [
+		{"name": "GetVertices", "time": 24, "annotations": {"percentTime": 85.71}, "counts": {"resultCount": 2}},
+		{"name": "GetEdges", "time": 4, "annotations": {"percentTime": 14.29}, "counts": {"resultCount": 3}}
+	]
+}]`
+
+const dataProfileTinkerpop = `[{
+	"@type": "g:TraversalMetrics",
+	"@value": {
+		"dur": {"@type": "g:Double", "@value": 1.5},
+		"metrics": [
+			{"@type": "g:Metrics", "@value": {
+				"dur": {"@type": "g:Double", "@value": 1.25},
+				"counts": {"traverserCount": {"@type": "g:Int64", "@value": 6}, "elementCount": {"@type": "g:Int64", "@value": 6}},
+				"name": "TinkerGraphStep(vertex,[])",
+				"id": "7.0.0()"
+			}}
+		]
+	}
+}]`
+
+func TestToProfilesCosmos(t *testing.T) {
+	// WHEN
+	profiles, err := ToProfiles([]byte(dataExecutionProfileCosmos))
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "g.V().hasLabel('tweet').out()", profiles[0].Query)
+	assert.Equal(t, time.Millisecond*28, profiles[0].Duration)
+	require.Len(t, profiles[0].Metrics, 2)
+	assert.Equal(t, ProfileMetric{Name: "GetVertices", Duration: time.Millisecond * 24, Count: 2}, profiles[0].Metrics[0])
+	assert.Equal(t, ProfileMetric{Name: "GetEdges", Duration: time.Millisecond * 4, Count: 3}, profiles[0].Metrics[1])
+}
+
+func TestToProfilesTinkerpop(t *testing.T) {
+	// WHEN
+	profiles, err := ToProfiles([]byte(dataProfileTinkerpop))
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Empty(t, profiles[0].Query)
+	assert.Equal(t, time.Microsecond*1500, profiles[0].Duration)
+	require.Len(t, profiles[0].Metrics, 1)
+	assert.Equal(t, ProfileMetric{Name: "TinkerGraphStep(vertex,[])", Duration: time.Microsecond * 1250, Count: 6}, profiles[0].Metrics[0])
+}
+
+func TestToProfilesFail(t *testing.T) {
+	// WHEN
+	_, errNil := ToProfiles(nil)
+	_, errNoProfile := ToProfiles([]byte(`[{"id":"1"}]`))
+	_, errNoMap := ToProfiles([]byte(`[1,2]`))
+
+	// THEN
+	assert.Error(t, errNil)
+	assert.Error(t, errNoProfile)
+	assert.Error(t, errNoMap)
+}
@@ -50,6 +50,12 @@ func (p *property) Count() interfaces.QueryBuilder {
 	return p.Add(NewSimpleQB(".count()"))
 }
 
+// CountLocal adds .count(local), to the query. The query call will return the number of elements within each collection,
+// e.g. the number of vertices per group of g.V().group().by(label).count(local).
+func (p *property) CountLocal() interfaces.QueryBuilder {
+	return p.Add(NewSimpleQB(".count(local)"))
+}
+
 // Limit adds .limit(<num>), to the query. The query call will limit the results of the query to the given number.
 func (p *property) Limit(maxElements int) interfaces.Property {
 	return p.Add(NewSimpleQB(".limit(%d)", maxElements))
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// regexpScriptPlaceholder matches the placeholders of a script template, e.g. {{name}}
+var regexpScriptPlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// Placeholder is a placeholder {{<name>}} of a template, see ScanPlaceholders
+type Placeholder struct {
+	// Name is the name of the placeholder (without braces and surrounding whitespace)
+	Name string
+	// Start and End are the byte offsets of the placeholder (including the braces) within the template
+	Start int
+	End   int
+}
+
+// ScanPlaceholders returns the placeholders {{<name>}} of the given template in the order they appear.
+// The string literals of the template are tracked (honouring \ escapes), an error is returned in case a placeholder
+// is located inside of a string literal, e.g. has("name","user_{{id}}"), since its value could break out of the literal.
+func ScanPlaceholders(template string) ([]Placeholder, error) {
+	matches := regexpScriptPlaceholder.FindAllStringSubmatchIndex(template, -1)
+	placeholders := make([]Placeholder, 0, len(matches))
+
+	// quote is the quote character of the string literal at position pos, 0 if pos is not inside of a literal
+	var quote byte
+	pos := 0
+	for _, match := range matches {
+		for ; pos < match[0]; pos++ {
+			switch char := template[pos]; {
+			case quote != 0 && char == '\\':
+				// skip the escaped character
+				pos++
+			case quote != 0 && char == quote:
+				quote = 0
+			case quote == 0 && (char == '"' || char == '\''):
+				quote = char
+			}
+		}
+
+		if quote != 0 {
+			return nil, fmt.Errorf("placeholder '%s' must not be quoted", template[match[0]:match[1]])
+		}
+		placeholders = append(placeholders, Placeholder{Name: template[match[2]:match[3]], Start: match[0], End: match[1]})
+		pos = match[1]
+	}
+	return placeholders, nil
+}
+
+// regexpPlaceholderName matches valid placeholder names
+var regexpPlaceholderName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Identifier is a value that is rendered by RenderScript without quotes, e.g. to be used as dynamic step name or as label.
+// To avoid injections only letters, digits and '_' are allowed.
+type Identifier string
+
+// RenderScript substitutes the placeholders {{<name>}} of the given template by the according values.
+// The values are rendered the same way as for the typed builder methods: strings are quoted and escaped, numbers and
+// booleans are not quoted, predicates are rendered as is (see Escape for the escaping of strings). Values of type Identifier are rendered without quotes, but only
+// letters, digits and '_' are allowed for them.
+//	RenderScript(`g.V().{{step}}("knows").has("name",{{name}})`, map[string]interface{}{"step": Identifier("outE"), "name": "hans"})
+//	==> g.V().outE("knows").has("name","hans")
+// An error is returned in case a placeholder has no matching value, a value is not referenced, a placeholder is located
+// inside of a string literal (see ScanPlaceholders) or a value can't be rendered.
+// Hint: This is meant for cases where server side bindings (see ExecuteWithBindings) can't be used, e.g. for dynamic step names.
+// Bindings should be preferred whenever possible, since client side rendering relies on the escaping to prevent injections.
+// Never use the rendered script as template again and don't put placeholders into quotes or comments.
+func RenderScript(template string, values map[string]interface{}) (string, error) {
+	placeholders, err := ScanPlaceholders(template)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	used := make(map[string]bool)
+	script := strings.Builder{}
+	last := 0
+	for _, placeholder := range placeholders {
+		if !regexpPlaceholderName.MatchString(placeholder.Name) {
+			return "", fmt.Errorf("placeholder '%s' is invalid, only letters, digits and '_' are allowed", placeholder.Name)
+		}
+
+		script.WriteString(template[last:placeholder.Start])
+		last = placeholder.End
+
+		value, ok := values[placeholder.Name]
+		if !ok {
+			missing = append(missing, placeholder.Name)
+			continue
+		}
+		used[placeholder.Name] = true
+
+		rendered, err := renderScriptValue(value)
+		if err != nil {
+			return "", errors.Wrapf(err, "value of placeholder '%s' is invalid", placeholder.Name)
+		}
+		script.WriteString(rendered)
+	}
+	script.WriteString(template[last:])
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing values for the placeholders %s", strings.Join(missing, ","))
+	}
+
+	var unused []string
+	for name := range values {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", fmt.Errorf("values %s are not referenced by the template", strings.Join(unused, ","))
+	}
+
+	return script.String(), nil
+}
+
+// renderScriptValue renders the given value to be used in a script
+func renderScriptValue(value interface{}) (string, error) {
+	identifier, ok := value.(Identifier)
+	if !ok {
+		return toValueString(value)
+	}
+
+	if !regexpStepName.MatchString(string(identifier)) {
+		return "", fmt.Errorf("identifier '%s' is invalid, only letters, digits and '_' are allowed", identifier)
+	}
+	return string(identifier), nil
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderScript(t *testing.T) {
+	// GIVEN
+	template := `g.V().{{step}}({{label}}).has("name",{{name}}).has("age",{{ age }}).has("rating",gt({{rating}}))`
+	values := map[string]interface{}{
+		"step":   Identifier("outE"),
+		"label":  "knows",
+		"name":   `hans").drop();g.V("`,
+		"age":    42,
+		"rating": 4.5,
+	}
+
+	// WHEN
+	script, err := RenderScript(template, values)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `g.V().outE("knows").has("name","hans%22%29.drop%28%29%3Bg.V%28%22").has("age",42).has("rating",gt(4.5))`, script)
+}
+
+func TestRenderScriptPredicate(t *testing.T) {
+	// WHEN
+	script, err := RenderScript(`g.V().has("age",{{range}})`, map[string]interface{}{"range": Between(18, 65)})
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `g.V().has("age",between(18,65))`, script)
+}
+
+func TestRenderScriptFail(t *testing.T) {
+	// WHEN + THEN
+	_, err := RenderScript(`g.V().has("name",{{name}})`, map[string]interface{}{})
+	assert.EqualError(t, err, "missing values for the placeholders name")
+
+	_, err = RenderScript(`g.V()`, map[string]interface{}{"name": "hans"})
+	assert.EqualError(t, err, "values name are not referenced by the template")
+
+	_, err = RenderScript(`g.V().{{step}}()`, map[string]interface{}{"step": Identifier("drop();g.V")})
+	assert.Error(t, err, "invalid identifier")
+
+	_, err = RenderScript(`g.V().has("name","{{name}}")`, map[string]interface{}{"name": "hans"})
+	assert.Error(t, err, "quoted placeholder")
+
+	_, err = RenderScript(`g.V().has("name",{{na-me}})`, map[string]interface{}{"na-me": "hans"})
+	assert.Error(t, err, "invalid placeholder")
+
+	_, err = RenderScript(`g.V().has("name",{{name}})`, map[string]interface{}{"name": nil})
+	assert.Error(t, err, "nil value")
+}
+
+func TestRenderScriptPlaceholderInsideLiteral(t *testing.T) {
+	// WHEN + THEN
+	_, err := RenderScript(`g.V().has("name","user_{{id}}")`, map[string]interface{}{"id": `") .drop() //`})
+	assert.EqualError(t, err, "placeholder '{{id}}' must not be quoted")
+
+	_, err = RenderScript(`g.V().has('name','a\'{{id}}')`, map[string]interface{}{"id": "x"})
+	assert.Error(t, err, "placeholder inside of a literal with an escaped quote")
+
+	script, err := RenderScript(`g.V().has("name","a\"b").has("id",{{id}})`, map[string]interface{}{"id": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, `g.V().has("name","a\"b").has("id","x")`, script)
+
+	script, err = RenderScript(`g.V().has("name","{{").has("id",{{id}})`, map[string]interface{}{"id": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, `g.V().has("name","{{").has("id","x")`, script)
+}
+
+func TestScanPlaceholders(t *testing.T) {
+	// WHEN
+	placeholders, err := ScanPlaceholders(`g.V().{{ step }}("a{b}").has("name",{{name}})`)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []Placeholder{{Name: "step", Start: 6, End: 16}, {Name: "name", Start: 36, End: 44}}, placeholders)
+}
@@ -57,6 +57,7 @@ func (responses ResponseArray) ToVertices() ([]Vertex, error) {
 }
 
 // ToEdges converts the given ResponseArray into an array of Edge type.
+// Besides the edges as returned by the CosmosDB, GraphSON edges (g:Edge) and the value maps of edges (valueMap(true), elementMap()) are supported.
 // The method will fail in case the data in the given ResponseArray does not contain values of type edge.
 func (responses ResponseArray) ToEdges() ([]Edge, error) {
 	result := make([]Edge, 0)
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/supplyon/gremcos/interfaces"
 )
 
@@ -160,6 +161,113 @@ func TestResponseToEdges(t *testing.T) {
 	assert.Equal(t, "1111ba4e-be30-486e-88e1-b2f5937a9001", edges[0].OutV)
 }
 
+func TestResponseToEdgesWithProperties(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	// edge as returned by the CosmosDB for g.V('1111').outE('knows')
+	data := `[{
+		"id":"623709d5-fe22-4377-bc5b-9cb150fff124",
+		"label":"knows",
+		"type":"edge",
+		"inVLabel":"user",
+		"outVLabel":"admin",
+		"inV":"7404ba4e-be30-486e-88e1-b2f5937a9001",
+		"outV":"1111ba4e-be30-486e-88e1-b2f5937a9001",
+		"properties":{"since":2015,"weight":0.5,"note":"colleague"}
+	}]`
+	responses := createTestResponse(data)
+
+	// WHEN
+	edges, err := responses.ToEdges()
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	edge := edges[0]
+	assert.Equal(t, "1111ba4e-be30-486e-88e1-b2f5937a9001", edge.OutV)
+	assert.Equal(t, "admin", edge.OutVLabel)
+	assert.Equal(t, "7404ba4e-be30-486e-88e1-b2f5937a9001", edge.InV)
+	assert.Equal(t, "user", edge.InVLabel)
+	assert.Equal(t, int32(2015), edge.Properties["since"].AsInt32())
+	assert.Equal(t, 0.5, edge.Properties["weight"].AsFloat64())
+	assert.Equal(t, "colleague", edge.Properties["note"].AsString())
+	assert.Equal(t, "admin (1111ba4e-be30-486e-88e1-b2f5937a9001)-knows->user (7404ba4e-be30-486e-88e1-b2f5937a9001) - type edge", edge.String())
+}
+
+func TestResponseToEdgesGraphSON(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	// employes edge as returned by a TinkerPop server (GraphSON v3) for g.E().hasLabel('employes')
+	data := `{"@type":"g:List","@value":[{"@type":"g:Edge","@value":{
+		"id":{"@type":"g:Int64","@value":42},
+		"label":"employes",
+		"inVLabel":"EmployeeBulkData",
+		"outVLabel":"EmployerBulkData",
+		"inV":{"@type":"g:Int64","@value":7},
+		"outV":{"@type":"g:Int64","@value":1},
+		"properties":{
+			"since":{"@type":"g:Property","@value":{"key":"since","value":{"@type":"g:Int32","@value":2018}}},
+			"source":{"@type":"g:Property","@value":{"key":"source","value":"tree"}}
+		}
+	}}]}`
+	responses := createTestResponse(data)
+
+	// WHEN
+	edges, err := responses.ToEdges()
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+	edge := edges[0]
+	assert.Equal(t, "42", edge.ID)
+	assert.Equal(t, "employes", edge.Label)
+	assert.Equal(t, TypeEdge, edge.Type)
+	assert.Equal(t, "7", edge.InV)
+	assert.Equal(t, "EmployeeBulkData", edge.InVLabel)
+	assert.Equal(t, "1", edge.OutV)
+	assert.Equal(t, "EmployerBulkData", edge.OutVLabel)
+	assert.Len(t, edge.Properties, 2)
+	assert.Equal(t, int32(2018), edge.Properties["since"].AsInt32())
+	assert.Equal(t, "tree", edge.Properties["source"].AsString())
+}
+
+func TestResponseToEdgesValueMap(t *testing.T) {
+	t.Parallel()
+	// GIVEN
+	// employes edge as returned for g.E().hasLabel('employes').valueMap(true) and g.E().hasLabel('employes').elementMap()
+	valueMap := `[{"id":"623709d5-fe22-4377-bc5b-9cb150fff124","label":"employes","since":[2018]}]`
+	elementMap := `{"@type":"g:List","@value":[{"@type":"g:Map","@value":[
+		{"@type":"g:T","@value":"id"},"623709d5-fe22-4377-bc5b-9cb150fff124",
+		{"@type":"g:T","@value":"label"},"employes",
+		{"@type":"g:Direction","@value":"IN"},{"@type":"g:Map","@value":[{"@type":"g:T","@value":"id"},"7404ba4e-be30-486e-88e1-b2f5937a9001",{"@type":"g:T","@value":"label"},"EmployeeBulkData"]},
+		{"@type":"g:Direction","@value":"OUT"},{"@type":"g:Map","@value":[{"@type":"g:T","@value":"id"},"1111ba4e-be30-486e-88e1-b2f5937a9001",{"@type":"g:T","@value":"label"},"EmployerBulkData"]},
+		"since",{"@type":"g:Int32","@value":2018}
+	]}]}`
+
+	// WHEN
+	valueMapEdges, errValueMap := createTestResponse(valueMap).ToEdges()
+	elementMapEdges, errElementMap := createTestResponse(elementMap).ToEdges()
+
+	// THEN
+	require.NoError(t, errValueMap)
+	require.Len(t, valueMapEdges, 1)
+	assert.Equal(t, "623709d5-fe22-4377-bc5b-9cb150fff124", valueMapEdges[0].ID)
+	assert.Equal(t, "employes", valueMapEdges[0].Label)
+	assert.Empty(t, valueMapEdges[0].InV)
+	assert.Equal(t, int32(2018), valueMapEdges[0].Properties["since"].AsInt32())
+
+	require.NoError(t, errElementMap)
+	require.Len(t, elementMapEdges, 1)
+	edge := elementMapEdges[0]
+	assert.Equal(t, "623709d5-fe22-4377-bc5b-9cb150fff124", edge.ID)
+	assert.Equal(t, "7404ba4e-be30-486e-88e1-b2f5937a9001", edge.InV)
+	assert.Equal(t, "EmployeeBulkData", edge.InVLabel)
+	assert.Equal(t, "1111ba4e-be30-486e-88e1-b2f5937a9001", edge.OutV)
+	assert.Equal(t, "EmployerBulkData", edge.OutVLabel)
+	assert.Len(t, edge.Properties, 1)
+	assert.Equal(t, int32(2018), edge.Properties["since"].AsInt32())
+}
+
 func TestResponseToEdges_Null(t *testing.T) {
 	t.Parallel()
 	// GIVEN
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// regexpStepName matches valid names of gremlin steps, e.g. hasNot or outE
+var regexpStepName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+type strictBuilder struct {
+	value string
+}
+
+// NewStrictBuilder creates a QueryBuilder for the custom step .<step>(<param_1>,<param_2>,..,<param_n>), e.g. .hasNot("name").
+// In contrast to NewSimpleQB the given step name is validated and all parameters are rendered the same way as for the typed
+// builder methods (strings are quoted and escaped, numbers and booleans are not quoted, predicates are rendered as is).
+// Hence it can be used as safe replacement for raw NewSimpleQB calls to avoid injection via custom steps.
+//	g.V().Add(NewStrictBuilder("hasNot", "name"))
+// It panics in case the step name is invalid or a parameter is not supported.
+func NewStrictBuilder(step string, params ...interface{}) interfaces.QueryBuilder {
+	builder, err := NewStrictBuilderE(step, params...)
+	if err != nil {
+		panic(err)
+	}
+	return builder
+}
+
+// NewStrictBuilderE is the same as NewStrictBuilder, but it returns an error instead of a panic in case the step name
+// is invalid or a parameter is not supported.
+func NewStrictBuilderE(step string, params ...interface{}) (interfaces.QueryBuilder, error) {
+	if !regexpStepName.MatchString(step) {
+		return nil, fmt.Errorf("step name '%s' is invalid, only letters, digits and '_' are allowed", step)
+	}
+
+	paramStrs := make([]string, 0, len(params))
+	for i, param := range params {
+		paramStr, err := toValueString(param)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parameter %d of step '%s' is invalid", i, step)
+		}
+		paramStrs = append(paramStrs, paramStr)
+	}
+
+	return &strictBuilder{
+		value: fmt.Sprintf(".%s(%s)", step, strings.Join(paramStrs, ",")),
+	}, nil
+}
+
+func (sb *strictBuilder) String() string {
+	return sb.value
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStrictBuilder(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().Add(NewStrictBuilder("hasNot", "name")).Add(NewStrictBuilder("has", "age", Between(18, 30))).Add(NewStrictBuilder("fold"))
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf(`%s.V().hasNot("name").has("age",between(18,30)).fold()`, graphName), v.String())
+}
+
+func TestNewStrictBuilderEscapes(t *testing.T) {
+	// GIVEN
+	injection := `x").drop().V().has("a`
+
+	// WHEN
+	builder, err := NewStrictBuilderE("hasNot", injection)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`.hasNot("%s")`, Escape(injection)), builder.String())
+	assert.NotContains(t, builder.String(), `").drop()`)
+}
+
+func TestNewStrictBuilderFail(t *testing.T) {
+	// WHEN
+	_, errStep := NewStrictBuilderE("drop().V")
+	_, errEmpty := NewStrictBuilderE("")
+	_, errParam := NewStrictBuilderE("has", "name", nil)
+
+	// THEN
+	assert.Error(t, errStep)
+	assert.Error(t, errEmpty)
+	assert.Error(t, errParam)
+	assert.Panics(t, func() { NewStrictBuilder("drop();g.V") })
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -22,6 +23,7 @@ func mapStructToType(source map[string]interface{}, target interface{}) error {
 		Result:           target,
 		WeaklyTypedInput: true,
 		ErrorUnused:      true,
+		DecodeHook:       toTypedValueHook,
 	}
 
 	decoder, err := mapstructure.NewDecoder(config)
@@ -36,6 +38,14 @@ func mapStructToType(source map[string]interface{}, target interface{}) error {
 	return nil
 }
 
+// toTypedValueHook wraps primitive values, e.g. the values of edge properties, into a TypedValue
+func toTypedValueHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(TypedValue{}) || from == to || from.Kind() == reflect.Map || from.Kind() == reflect.Slice {
+		return data, nil
+	}
+	return TypedValue{Value: data}, nil
+}
+
 // toTypeArray converts a given byte slice into the provided slice of one type
 // Example
 //
@@ -47,8 +57,8 @@ func toTypeArray(input []byte, target interface{}) error {
 		return fmt.Errorf("Data is nil")
 	}
 
-	parsedInput := make([]interface{}, 0)
-	if err := json.Unmarshal(input, &parsedInput); err != nil {
+	parsedInput, err := parseResultList(input)
+	if err != nil {
 		return err
 	}
 
@@ -78,8 +88,12 @@ func toTypeArray(input []byte, target interface{}) error {
 			}
 			*targetValue = append(*targetValue, property)
 		case *[]Edge:
+			edgeMap, err := toEdgeMap(mapStrct)
+			if err != nil {
+				return errors.Wrap(err, "Mapping of response to Edge failed. Please ensure that the response contains only edges.")
+			}
 			var edge Edge
-			if err := mapStructToType(mapStrct, &edge); err != nil {
+			if err := mapStructToType(edgeMap, &edge); err != nil {
 				return errors.Wrap(err, "Mapping of response to Edge failed. Please ensure that the response contains only edges.")
 			}
 			*targetValue = append(*targetValue, edge)
@@ -96,6 +110,79 @@ func toTypeArray(input []byte, target interface{}) error {
 	return nil
 }
 
+// parseResultList parses the given results of a response into a list. Besides a plain json array
+// a list wrapped in a GraphSON envelope ({"@type":"g:List","@value":[..]}) is supported.
+func parseResultList(input []byte) ([]interface{}, error) {
+	var parsed interface{}
+	if err := json.Unmarshal(input, &parsed); err != nil {
+		return nil, err
+	}
+
+	if envelope, ok := parsed.(map[string]interface{}); ok {
+		if typ := envelope[graphSONTypeKey]; typ == graphSONTypeList || typ == graphSONTypeSet {
+			parsed = envelope[graphSONValueKey]
+		}
+	}
+
+	switch casted := parsed.(type) {
+	case nil:
+		return []interface{}{}, nil
+	case []interface{}:
+		return casted, nil
+	default:
+		return nil, fmt.Errorf("Failed to cast %v (%T) into []interface{}", parsed, parsed)
+	}
+}
+
+// edgeFields are the keys of an edge that are no properties
+var edgeFields = map[string]bool{"id": true, "label": true, "type": true, "inV": true, "inVLabel": true, "outV": true, "outVLabel": true}
+
+// toEdgeMap converts the given edge into the (untyped) form returned by the CosmosDB for g.E(), which can be mapped to Edge.
+// Besides that form the GraphSON form ({"@type":"g:Edge","@value":{..}}) and the value map forms as returned by valueMap(true)
+// ({"id":"1","label":"knows","since":2015}) or elementMap() ({"id":"1","label":"knows","IN":{"id":"2","label":"user"},"OUT":{..},"since":2015}) are supported.
+func toEdgeMap(element map[string]interface{}) (map[string]interface{}, error) {
+	if isElement(element) {
+		return element, nil
+	}
+
+	value, err := fromGraphSON(element)
+	if err != nil {
+		return nil, err
+	}
+	flattened, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Failed to cast %v (%T) into map[string]interface{}", value, value)
+	}
+
+	edge := map[string]interface{}{"type": string(TypeEdge)}
+	properties := make(map[string]interface{})
+	for key, entry := range flattened {
+		switch {
+		case edgeFields[key]:
+			edge[key] = entry
+		case key == "IN" || key == "OUT":
+			vertex, ok := entry.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("The %s vertex of the edge is not a map but %T", key, entry)
+			}
+			prefix := "outV"
+			if key == "IN" {
+				prefix = "inV"
+			}
+			edge[prefix] = vertex["id"]
+			edge[prefix+"Label"] = vertex["label"]
+		default:
+			// the values of a value map might be wrapped into a list
+			if list, ok := entry.([]interface{}); ok && len(list) == 1 {
+				entry = list[0]
+			}
+			properties[key] = entry
+		}
+	}
+	edge["properties"] = properties
+	return edge, nil
+}
+
 // ToValues converts the given input byte array into an array of TypedValue type.
 // The method will fail in case the data in the given byte array does not contain primitive values.
 func ToValues(input []byte) ([]TypedValue, error) {
@@ -127,6 +214,7 @@ func ToVertices(input []byte) ([]Vertex, error) {
 }
 
 // ToEdges converts the given input byte array into an array of Edge type.
+// Besides the edges as returned by the CosmosDB, GraphSON edges (g:Edge) and the value maps of edges (valueMap(true), elementMap()) are supported.
 // The method will fail in case the data in the given byte array does not contain values of type edge.
 func ToEdges(input []byte) ([]Edge, error) {
 	var edges []Edge
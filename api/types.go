@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cast"
@@ -16,14 +17,16 @@ type Property struct {
 
 // Edge represents the cosmos DB type for an edge.
 // As it would be returned by a call to g.E().
+// The edge is directed from the outgoing vertex (OutV) to the incoming vertex (InV).
 type Edge struct {
-	ID        string `mapstructure:"id"`
-	Label     string `mapstructure:"label"`
-	Type      Type   `mapstructure:"type"`
-	InVLabel  string `mapstructure:"inVLabel"`
-	InV       string `mapstructure:"inV"`
-	OutVLabel string `mapstructure:"outVLabel"`
-	OutV      string `mapstructure:"outV"`
+	ID         string          `mapstructure:"id"`
+	Label      string          `mapstructure:"label"`
+	Type       Type            `mapstructure:"type"`
+	InVLabel   string          `mapstructure:"inVLabel"`
+	InV        string          `mapstructure:"inV"`
+	OutVLabel  string          `mapstructure:"outVLabel"`
+	OutV       string          `mapstructure:"outV"`
+	Properties EdgePropertyMap `mapstructure:"properties"`
 }
 
 // Vertex represents the cosmos DB type for an vertex.
@@ -44,6 +47,10 @@ type ValueWithID struct {
 
 type VertexPropertyMap map[string][]ValueWithID
 
+// EdgePropertyMap represents the properties of an edge.
+// In contrast to the properties of a vertex they are plain key value pairs.
+type EdgePropertyMap map[string]TypedValue
+
 // Type defines the cosmos db complex types
 type Type string
 
@@ -117,6 +124,23 @@ func (tv TypedValue) AsString() string {
 	return UnEscape(cast.ToString(tv.Value))
 }
 
+// AsJSON decodes the value into the given target (a pointer) by unmarshalling it as json.
+// This is the counterpart of storing a struct as property value, e.g.
+//	type Address struct {
+//		City string `json:"city"`
+//	}
+//	g.V("1").Property("address", Address{City: "Berlin"}) // stored as json string {"city":"Berlin"}
+//	...
+//	var address Address
+//	err := vertex.Properties["address"][0].Value.AsJSON(&address)
+func (tv TypedValue) AsJSON(target interface{}) error {
+	value, err := tv.AsStringE()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(value), target)
+}
+
 func (tv TypedValue) String() string {
 	return fmt.Sprintf("%v", tv.Value)
 }
@@ -126,7 +150,7 @@ func (v Vertex) String() string {
 }
 
 func (e Edge) String() string {
-	return fmt.Sprintf("%s (%s)-%s->%s (%s) - type %s", e.InVLabel, e.InV, e.Label, e.OutVLabel, e.OutV, e.Type)
+	return fmt.Sprintf("%s (%s)-%s->%s (%s) - type %s", e.OutVLabel, e.OutV, e.Label, e.InVLabel, e.InV, e.Type)
 }
 
 // Value returns the first value of the properties for this key
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_toValues(t *testing.T) {
@@ -139,3 +140,25 @@ func TestVertexPropertyAsInt32(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, value, valueWithIDExtracted)
 }
+
+func TestTypedValueAsJSON(t *testing.T) {
+	// GIVEN
+	type address struct {
+		City string `json:"city"`
+		Zip  int    `json:"zip"`
+	}
+	// the value as it is stored by Property (escaped json string)
+	stored := TypedValue{Value: Escape(`{"city":"Berlin","zip":10115}`)}
+	invalid := TypedValue{Value: "no json"}
+
+	// WHEN
+	var decoded address
+	err := stored.AsJSON(&decoded)
+	var decodedInvalid address
+	errInvalid := invalid.AsJSON(&decodedInvalid)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, address{City: "Berlin", Zip: 10115}, decoded)
+	assert.Error(t, errInvalid)
+}
@@ -1,7 +1,12 @@
 package api
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,9 +26,23 @@ func (v *vertex) String() string {
 		queryString += queryBuilder.String()
 	}
 
+	// the bare form of an anonymous traversal starts directly with the first step
+	if root, ok := v.anonymousRoot(); ok && root.String() == "" {
+		queryString = strings.TrimPrefix(queryString, ".")
+	}
+
 	return queryString
 }
 
+// anonymousRoot returns the root of the anonymous traversal in case this vertex is part of one.
+func (v *vertex) anonymousRoot() (*anonymousTraversalRoot, bool) {
+	if len(v.builders) == 0 {
+		return nil, false
+	}
+	root, ok := v.builders[0].(*anonymousTraversalRoot)
+	return root, ok
+}
+
 func NewVertexG(g interfaces.Graph) interfaces.Vertex {
 	queryBuilders := make([]interfaces.QueryBuilder, 0)
 	queryBuilders = append(queryBuilders, g)
@@ -47,12 +66,69 @@ func (v *vertex) Limit(maxElements int) interfaces.Vertex {
 	return v.Add(NewSimpleQB(".limit(%d)", maxElements))
 }
 
+// LimitLocal adds .limit(local,<num>), to the query. The query call will limit the elements within each collection to the given number.
+func (v *vertex) LimitLocal(maxElements int) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".limit(local,%d)", maxElements))
+}
+
+// Sample adds .sample(<num>), e.g. .sample(1000), to the query. The query call returns the given number of randomly chosen elements.
+func (v *vertex) Sample(n int) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".sample(%d)", n))
+}
+
+// Dedup adds .dedup(), to the query. The query call removes the duplicated elements.
+func (v *vertex) Dedup() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".dedup()"))
+}
+
+// DedupBy adds .dedup().by("<key>"), e.g. .dedup().by("name"), to the query. The query call removes the elements with a
+// duplicated value of the given property, only the first element per value is kept.
+func (v *vertex) DedupBy(key string) interfaces.Vertex {
+	return v.Dedup().By(key)
+}
+
+// DedupById adds .dedup().by(id), to the query. The query call removes the elements with a duplicated id.
+// Dedup compares the elements themselves, which are identified by id and partition key on a partitioned CosmosDB
+// (the id is only unique per partition). DedupById compares only the id and thus merges elements with the same id
+// located in different partitions. For TinkerPop (ids are unique per graph) both variants behave the same.
+func (v *vertex) DedupById() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".dedup().by(id)"))
+}
+
 // As adds .as([<label_1>,<label_2>,..,<label_n>]), to the query to label that query step for later access.
 func (v *vertex) As(labels ...string) interfaces.Vertex {
 	query := multiParamQuery(".as", labels...)
 	return v.Add(query)
 }
 
+// Aggregate adds .aggregate("<key>"), e.g. .aggregate("x"), to the query. The query call collects all elements of this step
+// into the side-effect with the given key before the traversal continues. The side-effect can be read using Cap.
+func (v *vertex) Aggregate(key string) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".aggregate(\"%s\")", key))
+}
+
+// Store adds .store("<key>"), e.g. .store("x"), to the query. The query call collects the elements of this step
+// into the side-effect with the given key while the traversal continues. The side-effect can be read using Cap.
+func (v *vertex) Store(key string) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".store(\"%s\")", key))
+}
+
+// Cap adds .cap("<key_1>",..,"<key_n>"), e.g. .cap("x","y"), to the query. The query call emits the side-effect(s)
+// with the given key(s). It panics in case no key is given.
+func (v *vertex) Cap(keys ...string) interfaces.QueryBuilder {
+	if len(keys) == 0 {
+		panic(fmt.Errorf("cap needs at least one key"))
+	}
+	return v.Add(multiParamQuery(".cap", keys...))
+}
+
+// CollectInto adds .aggregate("<key>").cap("<key>"), e.g. .aggregate("x").cap("x"), to the query.
+// The query call returns the list of all elements that reached this step of the traversal, e.g. all friends of the users:
+//	g.V().HasLabel("user").OutE("knows").InV().CollectInto("friends")
+func (v *vertex) CollectInto(key string) interfaces.QueryBuilder {
+	return v.Aggregate(key).Cap(key)
+}
+
 // Add can be used to add a custom QueryBuilder
 // e.g. g.V().Add(NewSimpleQB(".myCustomCall("%s")",label))
 func (v *vertex) Add(builder interfaces.QueryBuilder) interfaces.Vertex {
@@ -71,12 +147,70 @@ func (v *vertex) Has(key string, value ...interface{}) interfaces.Vertex {
 		return v.Add(NewSimpleQB(".has(\"%s\")", key))
 	}
 
-	keyVal, err := toKeyValueString(key, value[0])
+	query, err := hasQuery(key, value[0])
 	if err != nil {
-		panic(errors.Wrapf(err, "cast has value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", value))
+		panic(err)
 	}
+	return v.Add(query)
+}
 
-	return v.Add(NewSimpleQB(".has%s", keyVal))
+// HasE adds .has("<key>","<value>"), e.g. .has("name","hans") depending on the given type the quotes for the value are omitted.
+// In contrast to Has an error is returned (instead of a panic) in case the given value is not supported.
+func (v *vertex) HasE(key string, value interface{}) (interfaces.Vertex, error) {
+	query, err := hasQuery(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return v.Add(query), nil
+}
+
+// HasNotValue adds .not(__.has("<key>","<value>")), e.g. .not(__.has("name","hans")) depending on the given type the quotes for the value are omitted.
+// The query call excludes all vertices with the given property value. Like for Underscore the prefix __. is omitted
+// for QueryLanguageTinkerpopGremlin.
+func (v *vertex) HasNotValue(key string, value interface{}) interfaces.Vertex {
+	query, err := hasQuery(key, value)
+	if err != nil {
+		panic(err)
+	}
+	return v.Add(NewSimpleQB(".not(%s)", Underscore().Add(query)))
+}
+
+// Inject adds .inject(<values>), e.g. .inject("a",1,true), depending on the given type the quotes for the values are omitted.
+// The query call adds the given constant values to the traversal. It panics in case a value is nil or not supported.
+func (v *vertex) Inject(values ...interface{}) interfaces.Vertex {
+	valueStrs := make([]string, 0, len(values))
+	for _, value := range values {
+		valueStr, err := toValueString(value)
+		if err != nil {
+			panic(errors.Wrap(err, "inject value is not supported"))
+		}
+		valueStrs = append(valueStrs, valueStr)
+	}
+	return v.Add(NewSimpleQB(".inject(%s)", strings.Join(valueStrs, ",")))
+}
+
+// Constant adds .constant(<value>), e.g. .constant("unknown"), depending on the given type the quotes for the value are omitted.
+// The query call replaces each element by the given constant value. It panics in case the value is nil or not supported.
+func (v *vertex) Constant(value interface{}) interfaces.QueryBuilder {
+	valueStr, err := toValueString(value)
+	if err != nil {
+		panic(errors.Wrap(err, "constant value is not supported"))
+	}
+	return v.Add(NewSimpleQB(".constant(%s)", valueStr))
+}
+
+// hasQuery creates the query for .has("<key>","<value>"). An error is returned in case the value is nil
+// or can't be converted into a string.
+func hasQuery(key string, value interface{}) (interfaces.QueryBuilder, error) {
+	if value == nil {
+		return nil, fmt.Errorf("has value for key '%s' is nil, null values are not supported (use .not(has(\"%s\")) to query for vertices without this property)", key, key)
+	}
+
+	keyVal, err := toKeyValueString(key, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cast has value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", value)
+	}
+	return NewSimpleQB(".has%s", keyVal), nil
 }
 
 // HasLabel adds .hasLabel([<label_1>,<label_2>,..,<label_n>]), e.g. .hasLabel('user','name'), to the query. The query call returns all vertices with the given label.
@@ -85,6 +219,63 @@ func (v *vertex) HasLabel(vertexLabel ...string) interfaces.Vertex {
 	return v.Add(query)
 }
 
+// HasLabelPredicate adds .hasLabel(<predicate>), e.g. .hasLabel(within("user","admin")), to the query. The query call returns all vertices
+// with a label matching the given predicate.
+func (v *vertex) HasLabelPredicate(predicate interfaces.Predicate) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".hasLabel(%s)", predicate))
+}
+
+// Label adds .label(), to the query. The query call returns the label of the vertex.
+func (v *vertex) Label() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".label()"))
+}
+
+// Math adds .math("<expression>"), e.g. .math("a + b"), to the query. The query call evaluates the given arithmetic expression,
+// the variables of the expression are specified by adding By modulators.
+//	g.V().Project("score").ByTraversal(Underscore().Math("a / b").By("wins").By("games"))
+// Since the expression can't be escaped without changing it, it panics in case the expression contains quotes, backslashes,
+// $ or control characters. Hint: The math step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) Math(expression string) interfaces.Vertex {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("math is not supported by the CosmosDB"))
+	}
+	if ShouldEscape(expression) {
+		panic(fmt.Errorf("the math expression '%s' contains characters that are not allowed (quotes, backslashes, $ or control characters)", expression))
+	}
+	return v.Add(NewSimpleQB(".math(\"%s\")", expression))
+}
+
+// IncrementProperty adds .sideEffect(property("<key>",union(values("<key>"),constant(<by>)).sum())), e.g.
+// .sideEffect(property("views",union(values("views"),constant(1)).sum())). The query call increments the numeric property by the given value
+// on server side, in case the property is missing it is set to the given value.
+// Hint: The CosmosDB supports neither the sideEffect step nor traversals as property values, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) IncrementProperty(key string, by int) interfaces.Vertex {
+	query, err := incrementPropertyQuery(key, by)
+	if err != nil {
+		panic(err)
+	}
+	return v.Add(query)
+}
+
+// IncrementPropertyE adds .sideEffect(property("<key>",union(values("<key>"),constant(<by>)).sum())), like IncrementProperty.
+// In contrast to IncrementProperty an error is returned (instead of a panic) in case QueryLanguageCosmosDB is in use.
+func (v *vertex) IncrementPropertyE(key string, by int) (interfaces.Vertex, error) {
+	query, err := incrementPropertyQuery(key, by)
+	if err != nil {
+		return nil, err
+	}
+	return v.Add(query), nil
+}
+
+// incrementPropertyQuery creates the query for IncrementProperty. An error is returned in case the query language
+// in use doesn't support incrementing properties on server side.
+func incrementPropertyQuery(key string, by int) (interfaces.QueryBuilder, error) {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		return nil, fmt.Errorf("incrementing property '%s' on server side is not supported by the CosmosDB (read the value and update it instead)", key)
+	}
+	return NewSimpleQB(".sideEffect(property(\"%s\",union(values(\"%s\"),constant(%d)).sum()))", key, key, by), nil
+}
+
 // ValuesBy adds .values("<label>"), e.g. .values("user")
 func (v *vertex) ValuesBy(label string) interfaces.QueryBuilder {
 	return v.Add(NewSimpleQB(".values(\"%s\")", label))
@@ -100,6 +291,25 @@ func (v *vertex) ValueMap() interfaces.QueryBuilder {
 	return v.Add(NewSimpleQB(".valueMap()"))
 }
 
+// ValueMapWithTokens adds .valueMap(true) or .valueMap().with(WithOptions.tokens)
+// The CosmosDB supports only .valueMap(true) to include the id and the label, while TinkerPop deprecated it in favor of
+// .with(WithOptions.tokens). Hence the rendered step depends on the query language in use.
+func (v *vertex) ValueMapWithTokens() interfaces.QueryBuilder {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		return v.Add(NewSimpleQB(".valueMap(true)"))
+	}
+	return v.Add(NewSimpleQB(".valueMap().with(WithOptions.tokens)"))
+}
+
+// ElementMap adds .elementMap() or .elementMap("<key_1>",...,"<key_n>")
+// Hint: The elementMap step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) ElementMap(keys ...string) interfaces.QueryBuilder {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("elementMap is not supported by the CosmosDB (use valueMap instead)"))
+	}
+	return v.Add(multiParamQuery(".elementMap", keys...))
+}
+
 // Properties adds .properties() or .properties("<prop1 name>","<prop2 name>",...)
 func (v *vertex) Properties(keys ...string) interfaces.Property {
 
@@ -166,6 +376,36 @@ func (v *vertex) Count() interfaces.QueryBuilder {
 	return v.Add(NewSimpleQB(".count()"))
 }
 
+// CountLocal adds .count(local), to the query. The query call will return the number of elements within each collection,
+// e.g. the number of vertices per group of g.V().group().by(label).count(local).
+func (v *vertex) CountLocal() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".count(local)"))
+}
+
+// Sum adds .sum(), to the query. The query call will return the sum of the preceding numeric values,
+// e.g. g.V().hasLabel("product").values("price").sum().
+func (v *vertex) Sum() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".sum()"))
+}
+
+// Max adds .max(), to the query. The query call will return the largest of the preceding numeric values,
+// e.g. g.V().hasLabel("product").values("price").max().
+func (v *vertex) Max() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".max()"))
+}
+
+// Min adds .min(), to the query. The query call will return the smallest of the preceding numeric values,
+// e.g. g.V().hasLabel("product").values("price").min().
+func (v *vertex) Min() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".min()"))
+}
+
+// Mean adds .mean(), to the query. The query call will return the average of the preceding numeric values,
+// e.g. g.V().hasLabel("product").values("price").mean().
+func (v *vertex) Mean() interfaces.QueryBuilder {
+	return v.Add(NewSimpleQB(".mean()"))
+}
+
 // PropertyList adds .property(list,"<key>","<value>"), e.g. .property(list, "name","hans"), to the query. The query call will add the given property.
 func (v *vertex) PropertyList(key, value string) interfaces.Vertex {
 	return v.Add(NewSimpleQB(".property(list,\"%s\",\"%s\")", key, Escape(value)))
@@ -173,13 +413,308 @@ func (v *vertex) PropertyList(key, value string) interfaces.Vertex {
 
 // Property adds .property("<key>","<value>"), e.g. .property("name","hans") depending on the given type the quotes for the value are omitted.
 // e.g. .property("temperature",23.02) or .property("available",true)
+// Structs are marshalled to json and stored as (escaped) string, use TypedValue.AsJSON to decode them again.
 func (v *vertex) Property(key, value interface{}) interfaces.Vertex {
+	query, err := propertyQuery(key, value)
+	if err != nil {
+		panic(err)
+	}
+	return v.Add(query)
+}
+
+// PropertyE adds .property("<key>","<value>"), e.g. .property("name","hans") depending on the given type the quotes for the value are omitted.
+// In contrast to Property an error is returned (instead of a panic) in case the given value is not supported.
+func (v *vertex) PropertyE(key, value interface{}) (interfaces.Vertex, error) {
+	query, err := propertyQuery(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return v.Add(query), nil
+}
+
+// propertyQuery creates the query for .property("<key>","<value>"). An error is returned in case the value is nil
+// or can't be converted into a string.
+func propertyQuery(key, value interface{}) (interfaces.QueryBuilder, error) {
+	if value == nil {
+		return nil, fmt.Errorf("property value for key '%v' is nil, null values are not supported (use .properties(\"%v\").drop() to remove this property)", key, key)
+	}
+
 	keyVal, err := toKeyValueString(key, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cast property value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", value)
+	}
+	return NewSimpleQB(".property%s", keyVal), nil
+}
+
+// UpsertV adds <match>.fold().coalesce(__.unfold(),<create>), e.g. .has("name","hans").fold().coalesce(__.unfold(),addV("user").property("name","hans")),
+// to the query. The query call returns the vertex found by the match traversal or creates it using the create traversal in case it does not exist.
+// The match traversal is optional, if it is nil the vertices selected so far are used as match.
+// Like for Underscore the prefix __. of the unfold step is omitted for QueryLanguageTinkerpopGremlin.
+//	g.V().UpsertV(NewSimpleQB(".has(\"name\",\"hans\")"), NewSimpleQB("addV(\"user\").property(\"name\",\"hans\")"))
+func (v *vertex) UpsertV(matchTraversal interfaces.QueryBuilder, createTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	if createTraversal == nil {
+		panic(fmt.Errorf("the create traversal of UpsertV is nil"))
+	}
+
+	if matchTraversal != nil {
+		v.Add(matchTraversal)
+	}
+	return v.Add(NewSimpleQB(".fold().coalesce(%s,%s)", Underscore().Add(NewSimpleQB(".unfold()")), createTraversal))
+}
+
+// Project adds .project([<key_1>,<key_2>,..,<key_n>]), e.g. .project("name","age"), to the query. The query call returns one map per vertex
+// containing the given keys. The values are specified by adding one By modulator per key (in the order of the keys).
+//	g.V().Project("name","age").By("name").By("age")
+// The result is a list of maps, e.g. [{"name":"hans","age":32}], that can be decoded into a slice of structs with matching
+// 'mapstructure' tags using Decode.
+func (v *vertex) Project(keys ...string) interfaces.Vertex {
+	if len(keys) == 0 {
+		panic(fmt.Errorf("project needs at least one key"))
+	}
+	return v.Add(multiParamQuery(".project", keys...))
+}
+
+// By adds .by("<key>") or .by("<key>",<order>), e.g. .by("name") or .by("age",decr), to the query.
+// It modulates the previous step (e.g. Project or Order). It panics in case more than one order is given.
+func (v *vertex) By(key string, order ...interfaces.Order) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".by(%s)", withOrder(fmt.Sprintf("\"%s\"", key), order)))
+}
+
+// ByTraversal adds .by(<traversal>) or .by(<traversal>,<order>), e.g. .by(out("knows").count(),decr), to the query.
+// It modulates the previous step (e.g. Project or Order) by the result of the given (anonymous) traversal.
+// It panics in case the traversal is nil or more than one order is given.
+//	g.V().Order().ByTraversal(Underscore().OutE("knows").Count(), interfaces.OrderDesc)
+func (v *vertex) ByTraversal(traversal interfaces.QueryBuilder, order ...interfaces.Order) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of by is nil"))
+	}
+	return v.Add(NewSimpleQB(".by(%s)", withOrder(traversal.String(), order)))
+}
+
+// withOrder appends the given (optional) order to the given by parameter, e.g. "age",decr
+func withOrder(parameter string, order []interfaces.Order) string {
+	if len(order) > 1 {
+		panic(fmt.Errorf("by accepts at most one order but %d are given", len(order)))
+	}
+	if len(order) == 0 {
+		return parameter
+	}
+	return parameter + "," + orderToken(order[0])
+}
+
+// orderToken returns the token for the given order. The CosmosDB supports only incr and decr, while TinkerPop
+// removed them in favor of asc and desc. Hence the rendered token depends on the query language in use.
+func orderToken(order interfaces.Order) string {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		switch order {
+		case interfaces.OrderAsc:
+			return "incr"
+		case interfaces.OrderDesc:
+			return "decr"
+		}
+	}
+	return string(order)
+}
+
+// Order adds .order(), to the query. The query call sorts the elements, the sort criteria are specified by adding By modulators.
+//	g.V().HasLabel("user").Order().By("age", interfaces.OrderDesc)
+func (v *vertex) Order() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".order()"))
+}
+
+// Path adds .path(), to the query. The query call returns the path (the visited elements) of each traverser.
+// The elements of the path can be shaped by following By modulators.
+//	g.V().HasLabel("user").OutE("knows").InV().Path().By("name")
+func (v *vertex) Path() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".path()"))
+}
+
+// SimplePath adds .simplePath(), to the query. It filters out traversers that visited an element more than once.
+//	g.V().Repeat(Underscore().OutE("knows").InV().SimplePath()).Times(3)
+func (v *vertex) SimplePath() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".simplePath()"))
+}
+
+// CyclicPath adds .cyclicPath(), to the query. It keeps only the traversers that visited an element more than once.
+func (v *vertex) CyclicPath() interfaces.Vertex {
+	return v.Add(NewSimpleQB(".cyclicPath()"))
+}
+
+// Union adds .union(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .union(in("knows"),out("knows")), to the query.
+// The query call returns the merged results of all given (anonymous) traversals.
+//	g.V().Union(NewSimpleQB("in(\"knows\")"), NewSimpleQB("out(\"knows\")"))
+func (v *vertex) Union(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	if len(traversals) == 0 {
+		panic(fmt.Errorf("union needs at least one traversal"))
+	}
+
+	traversalStrs := make([]string, 0, len(traversals))
+	for i, traversal := range traversals {
+		if traversal == nil {
+			panic(fmt.Errorf("traversal %d of union is nil", i))
+		}
+		traversalStrs = append(traversalStrs, traversal.String())
+	}
+	return v.Add(NewSimpleQB(".union(%s)", strings.Join(traversalStrs, ",")))
+}
+
+// Local adds .local(<traversal>), e.g. .local(out("knows").limit(1)), to the query.
+// The query call applies the given (anonymous) traversal to each element separately, e.g. to limit the results per element.
+//	g.V().Local(Underscore().OutE("knows").InV().Limit(1))
+func (v *vertex) Local(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of local is nil"))
+	}
+	return v.Add(NewSimpleQB(".local(%s)", traversal))
+}
+
+// Barrier adds .barrier() or with a size .barrier(<maxBarrierSize>), e.g. .barrier(2500), to the query.
+// The query call collects the elements of the previous steps before continuing. Equal elements are bulked, hence the
+// following steps are executed only once per distinct element, which can reduce the consumed RU.
+//	g.V().HasLabel("user").OutE("knows").InV().Barrier(2500).OutE("knows").InV()
+func (v *vertex) Barrier(maxBarrierSize ...int) interfaces.Vertex {
+	if len(maxBarrierSize) > 1 {
+		panic(fmt.Errorf("barrier accepts at most one size but %d are given", len(maxBarrierSize)))
+	}
+	if len(maxBarrierSize) == 0 {
+		return v.Add(NewSimpleQB(".barrier()"))
+	}
+	if maxBarrierSize[0] < 1 {
+		panic(fmt.Errorf("the size of barrier has to be >=1 but is %d", maxBarrierSize[0]))
+	}
+	return v.Add(NewSimpleQB(".barrier(%d)", maxBarrierSize[0]))
+}
+
+// SideEffect adds .sideEffect(<traversal>), e.g. .sideEffect(properties("x").drop()), to the query.
+// The query call executes the given (anonymous) traversal for each element, the main traversal continues with the unchanged elements.
+//	g.V().HasLabel("user").SideEffect(Underscore().Properties("x").Drop()).Count()
+// Hint: The sideEffect step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) SideEffect(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("sideEffect is not supported by the CosmosDB"))
+	}
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of sideEffect is nil"))
+	}
+	return v.Add(NewSimpleQB(".sideEffect(%s)", traversal))
+}
+
+// Not adds .not(<traversal>), e.g. .not(has("x")), to the query.
+// The query call keeps only the elements for which the given (anonymous) traversal returns no result.
+//	g.V().HasLabel("user").Not(Underscore().Has("email"))
+func (v *vertex) Not(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of not is nil"))
+	}
+	return v.Add(NewSimpleQB(".not(%s)", traversal))
+}
+
+// And adds .and(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .and(has("a"),has("b")), to the query.
+// The query call keeps only the elements for which all given (anonymous) traversals return a result.
+//	g.V().And(Underscore().Has("age"), Underscore().OutE("knows"))
+func (v *vertex) And(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".and(%s)", joinFilterTraversals("and", traversals)))
+}
+
+// Or adds .or(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .or(has("a"),has("b")), to the query.
+// The query call keeps only the elements for which at least one of the given (anonymous) traversals returns a result.
+//	g.V().Or(Underscore().HasLabel("user"), Underscore().HasLabel("admin"))
+func (v *vertex) Or(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	return v.Add(NewSimpleQB(".or(%s)", joinFilterTraversals("or", traversals)))
+}
+
+// joinFilterTraversals returns the comma separated traversals of the given filter step, it panics in case no or a nil traversal is given
+func joinFilterTraversals(step string, traversals []interfaces.QueryBuilder) string {
+	if len(traversals) == 0 {
+		panic(fmt.Errorf("%s needs at least one traversal", step))
+	}
+
+	traversalStrs := make([]string, 0, len(traversals))
+	for i, traversal := range traversals {
+		if traversal == nil {
+			panic(fmt.Errorf("traversal %d of %s is nil", i, step))
+		}
+		traversalStrs = append(traversalStrs, traversal.String())
+	}
+	return strings.Join(traversalStrs, ",")
+}
+
+// Choose adds a conditional branching step to the query. Two forms are supported:
+// With one traversal .choose(<pick traversal>), e.g. .choose(values("type")), is added. The result of the pick traversal
+// is used to select the branch to continue with. The branches are specified by adding one Option per branch.
+//	g.V().Choose(NewSimpleQB("values(\"type\")")).Option("a", NewSimpleQB("out()")).Option("b", NewSimpleQB("in()"))
+// With three traversals .choose(<predicate>,<true traversal>,<false traversal>) (if/else) is added. In case the predicate
+// traversal returns a result the true traversal is used, otherwise the false traversal.
+//	g.V().Choose(Underscore().HasLabel("admin"), Underscore().OutE("manages").InV(), Underscore().OutE("knows").InV())
+// Hint: Both forms are supported by the CosmosDB. It panics in case another number of traversals is given.
+func (v *vertex) Choose(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	switch len(traversals) {
+	case 1:
+		if traversals[0] == nil {
+			panic(fmt.Errorf("the pick traversal of choose is nil"))
+		}
+		return v.Add(NewSimpleQB(".choose(%s)", traversals[0]))
+	case 3:
+		names := []string{"predicate", "true", "false"}
+		for i, traversal := range traversals {
+			if traversal == nil {
+				panic(fmt.Errorf("the %s traversal of choose is nil", names[i]))
+			}
+		}
+		return v.Add(NewSimpleQB(".choose(%s,%s,%s)", traversals[0], traversals[1], traversals[2]))
+	default:
+		panic(fmt.Errorf("choose expects either one (pick) or three (predicate, true, false) traversals but %d were given", len(traversals)))
+	}
+}
+
+// Option adds .option(<match>,<then traversal>), e.g. .option("a",out()), to the query. It modulates the previous Choose step.
+// Depending on the given type the quotes for the match value are omitted, e.g. .option(1,out()).
+func (v *vertex) Option(match interface{}, thenTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	if thenTraversal == nil {
+		panic(fmt.Errorf("the traversal of option '%v' is nil", match))
+	}
+
+	matchStr, err := toValueString(match)
+	if err != nil {
+		panic(errors.Wrapf(err, "cast option match value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", match))
+	}
+	return v.Add(NewSimpleQB(".option(%s,%s)", matchStr, thenTraversal))
+}
+
+// OnCreate adds .option(Merge.onCreate,<properties>), e.g. .option(Merge.onCreate,["created":true]), to the query.
+// It modulates the previous MergeV step, the given properties are set in case the vertex is created.
+// Hint: The mergeV step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) OnCreate(properties map[interface{}]interface{}) interfaces.Vertex {
+	return v.Add(mergeOptionQuery("onCreate", properties))
+}
+
+// OnMatch adds .option(Merge.onMatch,<properties>), e.g. .option(Merge.onMatch,["updated":true]), to the query.
+// It modulates the previous MergeV step, the given properties are set in case the vertex already exists.
+// Hint: The mergeV step is not supported by the CosmosDB, hence it panics if QueryLanguageCosmosDB is in use.
+func (v *vertex) OnMatch(properties map[interface{}]interface{}) interfaces.Vertex {
+	return v.Add(mergeOptionQuery("onMatch", properties))
+}
+
+// PropertyWithCardinality adds .property(<cardinality>,"<key>","<value>"), e.g. .property(single,"name","hans"), to the query.
+// Depending on the given type the quotes for the value are omitted, e.g. .property(list,"temperature",23.02).
+// Property is the same as calling this method without cardinality (the server default is used) and
+// PropertyList is the same as calling it with CardinalityList for string values.
+// Hint: CardinalitySet is not supported by the CosmosDB and thus can only be used with QueryLanguageTinkerpopGremlin.
+func (v *vertex) PropertyWithCardinality(cardinality interfaces.Cardinality, key string, value interface{}) interfaces.Vertex {
+	if cardinality == interfaces.CardinalitySet && gUSE_COSMOS_DB_QUERY_LANGUAGE {
+		panic(fmt.Errorf("cardinality '%s' is not supported by the CosmosDB (use %s or %s instead)", cardinality, interfaces.CardinalitySingle, interfaces.CardinalityList))
+	}
+
+	if value == nil {
+		panic(fmt.Errorf("property value for key '%s' is nil, null values are not supported (use .properties(\"%s\").drop() to remove this property)", key, key))
+	}
+
+	valueStr, err := toValueString(value)
 	if err != nil {
 		panic(errors.Wrapf(err, "cast property value %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", value))
 	}
 
-	return v.Add(NewSimpleQB(".property%s", keyVal))
+	return v.Add(NewSimpleQB(".property(%s,\"%s\",%s)", cardinality, key, valueStr))
 }
 
 // toKeyValueString creates a string based on the given key and value as a key/value pair using the following format
@@ -187,23 +722,209 @@ func (v *vertex) Property(key, value interface{}) interfaces.Vertex {
 // Depending on the given type of the value the quotes for the value are omitted.
 // e.g. ("temperature",23.02) or ("available",true)
 func toKeyValueString(key, value interface{}) (string, error) {
+	valueStr, err := toValueString(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(\"%s\",%s)", key, valueStr), nil
+}
+
+// formatFloat renders the given float using the shortest representation that round-trips
+// (e.g. 0.000001, 1e-12 or 3.14159265358979).
+// Whole numbers keep a trailing .0 to ensure they are still interpreted as floating point numbers.
+// An error is returned for NaN and +/-Inf since they can't be expressed as literal in a query.
+func formatFloat(value float64, bitSize int) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("float value %v is not supported", value)
+	}
+
+	formatted := strconv.FormatFloat(value, 'g', -1, bitSize)
+	if strings.ContainsAny(formatted, ".eE") {
+		return formatted, nil
+	}
+	return formatted + ".0", nil
+}
+
+// formatTime renders the given time as ISO-8601 (RFC3339), e.g. 2018-07-01T13:37:45-05:00.
+// In case the time has sub-second precision RFC3339Nano is used instead.
+func formatTime(value time.Time) string {
+	if value.Nanosecond() != 0 {
+		return value.Format(time.RFC3339Nano)
+	}
+	return value.Format(time.RFC3339)
+}
+
+// isJSONStruct returns true in case the given value is a struct (or a pointer to a struct) that shall be stored as json.
+// Types implementing the Stringer interface and time.Time are rendered as string instead. Structs without exported fields
+// are not supported, since they would be marshalled to an empty json object.
+func isJSONStruct(value interface{}) bool {
+	if _, ok := value.(fmt.Stringer); ok {
+		return false
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() != reflect.Struct || valueType == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+
+	for i := 0; i < valueType.NumField(); i++ {
+		if valueType.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// toValueString creates a string based on the given value that can be used as parameter in a query.
+// Depending on the given type of the value the quotes for the value are omitted.
+// e.g. "hans", 23.02 or true
+// Structs are marshalled to json and stored as (escaped) string, see TypedValue.AsJSON for decoding them.
+func toValueString(value interface{}) (string, error) {
 	switch casted := value.(type) {
+	case nil:
+		return "", fmt.Errorf("value is nil, null values are not supported")
 	case string:
-		return fmt.Sprintf("(\"%s\",\"%s\")", key, Escape(casted)), nil
+		return fmt.Sprintf("\"%s\"", Escape(casted)), nil
 	case bool:
-		return fmt.Sprintf("(\"%s\",%t)", key, casted), nil
-	case int, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return fmt.Sprintf("(\"%s\",%d)", key, casted), nil
+		return fmt.Sprintf("%t", casted), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", casted), nil
+	case json.Number:
+		// ensure that only valid numbers are rendered unquoted
+		if _, err := casted.Float64(); err != nil {
+			return "", errors.Wrapf(err, "json.Number '%s' is not a valid number", casted)
+		}
+		return casted.String(), nil
+	case []byte:
+		// binary data is stored as base64 encoded string
+		return fmt.Sprintf("\"%s\"", base64.StdEncoding.EncodeToString(casted)), nil
+	case float32:
+		return formatFloat(float64(casted), 32)
 	case float64:
-		return fmt.Sprintf("(\"%s\",%f)", key, casted), nil
+		return formatFloat(casted, 64)
 	case time.Time:
-		return fmt.Sprintf("(\"%s\",\"%s\")", key, casted.String()), nil
+		return fmt.Sprintf("\"%s\"", formatTime(casted)), nil
+	case *predicate:
+		return casted.String(), nil
 	default:
-		fmt.Printf("Type %T is not supported in v.toKeyValueString() will try to cast to string", casted)
+		// structs are stored as (escaped) json string, they can be decoded using TypedValue.AsJSON
+		if isJSONStruct(casted) {
+			asJSON, err := json.Marshal(casted)
+			if err != nil {
+				return "", errors.Wrapf(err, "marshalling %T to json failed", casted)
+			}
+			return fmt.Sprintf("\"%s\"", Escape(string(asJSON))), nil
+		}
+
+		// try to cast all other types to string
 		asStr, err := cast.ToStringE(casted)
 		if err != nil {
 			return "", errors.Wrapf(err, "cast %T to string failed (You could either implement the Stringer interface for this type or cast it to string beforehand)", casted)
 		}
-		return fmt.Sprintf("(\"%s\",\"%s\")", key, Escape(asStr)), nil
+		return fmt.Sprintf("\"%s\"", Escape(asStr)), nil
+	}
+}
+
+// loopStep is a step of a repeat loop, i.e. repeat itself or one of its modulators (until, emit and times).
+// The steps are kept in the order they were added, since the semantics of the loop depend on whether
+// the modulators are placed before or after the repeat step.
+type loopStep struct {
+	interfaces.QueryBuilder
+}
+
+// followsLoop returns true in case the last step of the query belongs to a repeat loop
+func (v *vertex) followsLoop() bool {
+	if len(v.builders) == 0 {
+		return false
+	}
+	_, ok := v.builders[len(v.builders)-1].(loopStep)
+	return ok
+}
+
+// Repeat adds .repeat(<traversal>), e.g. .repeat(out()), to the query.
+func (v *vertex) Repeat(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of repeat is nil"))
+	}
+	return v.Add(loopStep{NewSimpleQB(".repeat(%s)", traversal)})
+}
+
+// Times adds .times(<num>), e.g. .times(3), to the query.
+// It panics in case the previous step is no repeat step (or one of its modulators), since an orphaned times does not limit any loop.
+// Use TimesRepeat to place times in front of the repeat step (while-do semantics), e.g.
+//	g.V().Repeat(NewSimpleQB("out()")).Times(3) // valid
+//	g.V().TimesRepeat(3, NewSimpleQB("out()"))  // valid
+//	g.V().HasLabel("user").Times(3)             // panics
+func (v *vertex) Times(maxLoops int) interfaces.Vertex {
+	if !v.followsLoop() {
+		panic(fmt.Errorf("times has to follow a repeat step (e.g. Repeat(..).Times(%d) or TimesRepeat(%d,..)), otherwise the loop is not limited", maxLoops, maxLoops))
+	}
+	return v.Add(loopStep{NewSimpleQB(".times(%d)", maxLoops)})
+}
+
+// TimesRepeat adds .times(<num>).repeat(<traversal>) to the query (while-do semantics).
+//	g.V().TimesRepeat(3, NewSimpleQB("out()"))
+func (v *vertex) TimesRepeat(maxLoops int, traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of repeat is nil"))
+	}
+	v.Add(loopStep{NewSimpleQB(".times(%d)", maxLoops)})
+	return v.Repeat(traversal)
+}
+
+// until adds .until(<until traversal>) to the query. It is only used together with repeat (see RepeatUntil and UntilRepeat),
+// hence no orphaned until can be created.
+func (v *vertex) until(untilTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	if untilTraversal == nil {
+		panic(fmt.Errorf("the until traversal is nil"))
+	}
+	return v.Add(loopStep{NewSimpleQB(".until(%s)", untilTraversal)})
+}
+
+// emit adds .emit() or .emit(<emit traversal>) to the query. It is only used together with repeat (see RepeatEmit and EmitRepeat).
+func (v *vertex) emit(emitTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	if emitTraversal == nil {
+		return v.Add(loopStep{NewSimpleQB(".emit()")})
+	}
+	return v.Add(loopStep{NewSimpleQB(".emit(%s)", emitTraversal)})
+}
+
+// RepeatUntil adds .repeat(<traversal>).until(<until traversal>) to the query (do-while semantics).
+//	g.V().RepeatUntil(NewSimpleQB("out()"), NewSimpleQB("hasLabel(\"root\")"))
+func (v *vertex) RepeatUntil(traversal interfaces.QueryBuilder, untilTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	if untilTraversal == nil {
+		panic(fmt.Errorf("the until traversal is nil"))
+	}
+	v.Repeat(traversal)
+	return v.until(untilTraversal)
+}
+
+// UntilRepeat adds .until(<until traversal>).repeat(<traversal>) to the query (while-do semantics).
+//	g.V().UntilRepeat(NewSimpleQB("hasLabel(\"root\")"), NewSimpleQB("out()"))
+func (v *vertex) UntilRepeat(untilTraversal interfaces.QueryBuilder, traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of repeat is nil"))
+	}
+	v.until(untilTraversal)
+	return v.Repeat(traversal)
+}
+
+// RepeatEmit adds .repeat(<traversal>).emit(<emit traversal>) to the query (emit after each loop).
+//	g.V().RepeatEmit(NewSimpleQB("out()"), nil)
+func (v *vertex) RepeatEmit(traversal interfaces.QueryBuilder, emitTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	v.Repeat(traversal)
+	return v.emit(emitTraversal)
+}
+
+// EmitRepeat adds .emit(<emit traversal>).repeat(<traversal>) to the query (emit before each loop).
+//	g.V().EmitRepeat(nil, NewSimpleQB("out()"))
+func (v *vertex) EmitRepeat(emitTraversal interfaces.QueryBuilder, traversal interfaces.QueryBuilder) interfaces.Vertex {
+	if traversal == nil {
+		panic(fmt.Errorf("the traversal of repeat is nil"))
 	}
+	v.emit(emitTraversal)
+	return v.Repeat(traversal)
 }
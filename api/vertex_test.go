@@ -1,12 +1,16 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
 )
 
 func TestNewVertexG(t *testing.T) {
@@ -125,7 +129,7 @@ func TestHasFloat(t *testing.T) {
 
 	// THEN
 	assert.NotNil(t, v)
-	assert.Equal(t, fmt.Sprintf("%s.V().has(\"%s\",%f)", graphName, key, value), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().has(\"%s\",12.34)", graphName, key), v.String())
 }
 
 func TestHasTime(t *testing.T) {
@@ -143,7 +147,7 @@ func TestHasTime(t *testing.T) {
 
 	// THEN
 	assert.NotNil(t, v)
-	assert.Equal(t, fmt.Sprintf("%s.V().has(\"%s\",\"%s\")", graphName, key, value), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().has(\"%s\",\"%s\")", graphName, key, value.Format(time.RFC3339Nano)), v.String())
 }
 
 func TestHasMisc(t *testing.T) {
@@ -182,6 +186,109 @@ func TestHasMiscFail(t *testing.T) {
 	assert.Panics(t, func() { v.Has(key, value) }, "The code did not panic")
 }
 
+func TestHasE(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "key"
+	type myStruct struct {
+		field1 string
+		field2 int
+	}
+
+	// WHEN
+	v, err := g.V().HasE(key, "value")
+	vUnsupported, errUnsupported := g.V().HasE(key, myStruct{field1: "hello", field2: 12345})
+	vNil, errNil := g.V().HasE(key, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%s.V().has(\"%s\",\"value\")", graphName, key), v.String())
+	assert.Error(t, errUnsupported)
+	assert.Contains(t, errUnsupported.Error(), "cast has value api.myStruct to string failed")
+	assert.Nil(t, vUnsupported)
+	assert.Error(t, errNil)
+	assert.Nil(t, vNil)
+}
+
+func TestHasNotValue(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	vString := g.V().HasNotValue("name", "hans \"the\" user")
+	vInt := g.V().HasLabel("user").HasNotValue("age", 21)
+	vBool := g.V().HasNotValue("available", false)
+
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	vTinkerpopStr := g.V().HasNotValue("age", 21).String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().not(__.has(\"name\",\"hans+%%22the%%22+user\"))", graphName), vString.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").not(__.has(\"age\",21))", graphName), vInt.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().not(__.has(\"available\",false))", graphName), vBool.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().not(has(\"age\",21))", graphName), vTinkerpopStr)
+	assert.Panics(t, func() { g.V().HasNotValue("name", nil) }, "The code did not panic")
+}
+
+func TestInject(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	vMixed := g.V().HasLabel("user").Inject("hans \"the\" user", 21, true)
+	vNumbers := g.V().Inject(1, 2.5)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").inject(\"hans+%%22the%%22+user\",21,true)", graphName), vMixed.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().inject(1,2.5)", graphName), vNumbers.String())
+	assert.Panics(t, func() { g.V().Inject("a", nil) }, "The code did not panic")
+}
+
+func TestConstant(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	qbString := g.V().Constant("unknown")
+	qbInt := g.V().HasLabel("user").Constant(42)
+	qbUnion := g.V().Union(NewSimpleQB("values(\"name\")"), Constant("unknown"))
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().constant(\"unknown\")", graphName), qbString.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").constant(42)", graphName), qbInt.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().union(values(\"name\"),__.constant(\"unknown\"))", graphName), qbUnion.String())
+	assert.Panics(t, func() { g.V().Constant(nil) }, "The code did not panic")
+}
+
+func TestHasNil(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := g.V()
+	require.NotNil(t, v)
+
+	// WHEN
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		v.Has("key", nil)
+	}()
+
+	// THEN
+	require.NotNil(t, recovered, "The code did not panic")
+	assert.EqualError(t, recovered.(error), `has value for key 'key' is nil, null values are not supported (use .not(has("key")) to query for vertices without this property)`)
+}
+
 func TestHasLabel(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
@@ -217,6 +324,38 @@ func TestHasLabelMulti(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"%s\",\"%s\")", graphName, l1, l2), v.String())
 }
 
+func TestHasLabelPredicate(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := g.V()
+	require.NotNil(t, v)
+
+	// WHEN
+	v = v.HasLabelPredicate(Within("label1", "label2"))
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(within(\"label1\",\"label2\"))", graphName), v.String())
+}
+
+func TestLabel(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := g.V()
+	require.NotNil(t, v)
+
+	// WHEN
+	qb := v.HasId("123").Label()
+
+	// THEN
+	assert.NotNil(t, qb)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasId(\"123\").label()", graphName), qb.String())
+}
+
 func TestValuesBy(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
@@ -266,6 +405,96 @@ func TestValueMap(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.V().valueMap()", graphName), qb.String())
 }
 
+func TestValueMapWithTokens(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	qbCosmos := g.V().HasLabel("EmployeeBulkData").ValueMapWithTokens()
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	qbTinkerpop := g.V().HasLabel("EmployeeBulkData").ValueMapWithTokens()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"EmployeeBulkData\").valueMap(true)", graphName), qbCosmos.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"EmployeeBulkData\").valueMap().with(WithOptions.tokens)", graphName), qbTinkerpop.String())
+}
+
+func TestElementMap(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	qbNoKeys := g.V().ElementMap()
+	qbKeys := g.V().ElementMap("name", "age")
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().elementMap()", graphName), qbNoKeys.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().elementMap(\"name\",\"age\")", graphName), qbKeys.String())
+}
+
+func TestElementMapFailOnCosmos(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := g.V()
+
+	// WHEN + THEN
+	assert.Panics(t, func() { v.ElementMap() }, "The code did not panic")
+	assert.Panics(t, func() { v.ElementMap("name") }, "The code did not panic")
+}
+
+func TestIncrementProperty(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	qbIncrement := g.V().HasLabel("page").IncrementProperty("views", 1)
+	qbDecrement := g.V().IncrementProperty("stock", -2)
+	qbIncrementE, errTinkerpop := g.V().IncrementPropertyE("views", 1)
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+	qbCosmos, errCosmos := g.V().IncrementPropertyE("views", 1)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"page\").sideEffect(property(\"views\",union(values(\"views\"),constant(1)).sum()))", graphName), qbIncrement.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().sideEffect(property(\"stock\",union(values(\"stock\"),constant(-2)).sum()))", graphName), qbDecrement.String())
+	require.NoError(t, errTinkerpop)
+	assert.Equal(t, fmt.Sprintf("%s.V().sideEffect(property(\"views\",union(values(\"views\"),constant(1)).sum()))", graphName), qbIncrementE.String())
+	assert.Error(t, errCosmos)
+	assert.Nil(t, qbCosmos)
+	assert.Panics(t, func() { g.V().IncrementProperty("views", 1) }, "The code did not panic")
+}
+
+func TestMath(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	qbSum := g.V().HasLabel("team").Project("score").ByTraversal(Underscore().Math("a + b").By("wins").By("draws")).String()
+	qbDivision := g.V().HasLabel("team").Math("_ / 2").String()
+	panicsOnInvalidExpression := assert.Panics(t, func() { g.V().Math("a + \"b\"") }, "The code did not panic")
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"team\").project(\"score\").by(math(\"a + b\").by(\"wins\").by(\"draws\"))", graphName), qbSum)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"team\").math(\"_ / 2\")", graphName), qbDivision)
+	assert.True(t, panicsOnInvalidExpression)
+	assert.Panics(t, func() { g.V().Math("a + b") }, "The code did not panic")
+}
+
 func TestProperties(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
@@ -349,7 +578,96 @@ func TestPropertyFloat(t *testing.T) {
 
 	// THEN
 	assert.NotNil(t, v)
-	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",%f)", graphName, key, value), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",23.02)", graphName, key), v.String())
+}
+
+func TestPropertyFloatPrecision(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "ratio"
+
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{1e-12, "1e-12"},
+		{1e20, "1e+20"},
+		{3.14159265358979, "3.14159265358979"},
+		{0.000001, "1e-06"},
+		{float64(3), "3.0"},
+		{float32(0.1), "0.1"},
+		{float32(2.5e-8), "2.5e-08"},
+	}
+
+	for _, test := range tests {
+		// WHEN
+		v := g.V().Property(key, test.value)
+
+		// THEN
+		assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",%s)", graphName, key, test.expected), v.String())
+		if f, ok := test.value.(float64); ok {
+			parsed, err := strconv.ParseFloat(test.expected, 64)
+			require.NoError(t, err)
+			assert.Equal(t, f, parsed)
+		}
+	}
+}
+
+func TestToValueStringNonFiniteFloats(t *testing.T) {
+	values := []interface{}{math.NaN(), math.Inf(1), math.Inf(-1), float32(math.Inf(1))}
+	for _, value := range values {
+		// WHEN
+		valueStr, err := toValueString(value)
+
+		// THEN
+		assert.Error(t, err, "%v", value)
+		assert.Empty(t, valueStr)
+	}
+	assert.Panics(t, func() { NewGraph("g").V().Property("ratio", math.NaN()) }, "The code did not panic")
+}
+
+func TestPropertyMiscTypes(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "key"
+
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{int8(-12), "-12"},
+		{json.Number("42"), "42"},
+		{json.Number("1.5e3"), "1.5e3"},
+		{[]byte("hello"), `"aGVsbG8="`},
+	}
+
+	for _, test := range tests {
+		// WHEN
+		v := g.V().Property(key, test.value)
+
+		// THEN
+		assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",%s)", graphName, key, test.expected), v.String())
+	}
+}
+
+func TestPropertyMiscTypesFail(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "key"
+
+	// WHEN
+	_, errInvalidNumber := g.V().PropertyE(key, json.Number("1).drop()"))
+	_, errNil := g.V().PropertyE(key, nil)
+
+	// THEN
+	assert.Error(t, errInvalidNumber)
+	assert.Error(t, errNil)
 }
 
 func TestPropertyBool(t *testing.T) {
@@ -385,7 +703,25 @@ func TestPropertyTime(t *testing.T) {
 
 	// THEN
 	assert.NotNil(t, v)
-	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",\"%s\")", graphName, key, value), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",\"%s\")", graphName, key, value.Format(time.RFC3339Nano)), v.String())
+}
+
+func TestPropertyTimeISO8601(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "timestamp"
+	value := time.Date(2018, 7, 1, 13, 37, 45, 0, time.FixedZone("CDT", -5*60*60))
+	valueNano := time.Date(2018, 7, 1, 13, 37, 45, 123000000, time.UTC)
+
+	// WHEN
+	v := g.V().Property(key, value)
+	vNano := g.V().Property(key, valueNano)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",\"2018-07-01T13:37:45-05:00\")", graphName, key), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",\"2018-07-01T13:37:45.123Z\")", graphName, key), vNano.String())
 }
 
 func TestPropertyMiscFail(t *testing.T) {
@@ -406,6 +742,76 @@ func TestPropertyMiscFail(t *testing.T) {
 	assert.Panics(t, func() { v.Property(key, value) }, "The code did not panic")
 }
 
+func TestPropertyStruct(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "address"
+	type address struct {
+		City   string `json:"city"`
+		Zip    int    `json:"zip"`
+		street string
+	}
+	value := address{City: "Berlin", Zip: 10115, street: "ignored"}
+
+	// WHEN
+	v := g.V().Property(key, value)
+	vPtr := g.V().Property(key, &value)
+
+	// THEN
+	expected := fmt.Sprintf("%s.V().property(\"%s\",\"%s\")", graphName, key, Escape(`{"city":"Berlin","zip":10115}`))
+	assert.Equal(t, expected, v.String())
+	assert.Equal(t, expected, vPtr.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",\"%%7B%%22city%%22%%3A%%22Berlin%%22%%2C%%22zip%%22%%3A10115%%7D\")", graphName, key), v.String())
+}
+
+func TestPropertyE(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	key := "key"
+	type myStruct struct {
+		field1 string
+		field2 int
+	}
+
+	// WHEN
+	v, err := g.V().PropertyE(key, 12)
+	vUnsupported, errUnsupported := g.V().PropertyE(key, myStruct{field1: "hello", field2: 12345})
+	vNil, errNil := g.V().PropertyE(key, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%s.V().property(\"%s\",12)", graphName, key), v.String())
+	assert.Error(t, errUnsupported)
+	assert.Contains(t, errUnsupported.Error(), "cast property value api.myStruct to string failed")
+	assert.Nil(t, vUnsupported)
+	assert.Error(t, errNil)
+	assert.Nil(t, vNil)
+}
+
+func TestPropertyNil(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := g.V()
+	require.NotNil(t, v)
+
+	// WHEN
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		v.Property("key", nil)
+	}()
+
+	// THEN
+	require.NotNil(t, recovered, "The code did not panic")
+	assert.EqualError(t, recovered.(error), `property value for key 'key' is nil, null values are not supported (use .properties("key").drop() to remove this property)`)
+}
+
 type myStructWithStringer struct {
 	field1 string
 	field2 int
@@ -554,24 +960,22 @@ func TestVertexCount(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.count()", graphName), qb.String())
 }
 
-func TestOutE(t *testing.T) {
+func TestVertexCountLocal(t *testing.T) {
 
 	// GIVEN
 	graphName := "mygraph"
 	g := NewGraph(graphName)
 	require.NotNil(t, g)
-	v := NewVertexG(g)
-	require.NotNil(t, v)
 
 	// WHEN
-	e := v.OutE()
+	qb := g.V().HasLabel("user").CountLocal()
 
 	// THEN
-	assert.NotNil(t, e)
-	assert.Equal(t, fmt.Sprintf("%s.outE()", graphName), e.String())
+	assert.NotNil(t, qb)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").count(local)", graphName), qb.String())
 }
 
-func TestOutEMulti(t *testing.T) {
+func TestLimitLocal(t *testing.T) {
 
 	// GIVEN
 	graphName := "mygraph"
@@ -579,44 +983,110 @@ func TestOutEMulti(t *testing.T) {
 	require.NotNil(t, g)
 	v := NewVertexG(g)
 	require.NotNil(t, v)
-	l1 := "label1"
-	l2 := "label2"
 
 	// WHEN
-	e := v.OutE(l1, l2)
+	qb := v.HasLabel("user").LimitLocal(2)
 
 	// THEN
-	assert.NotNil(t, e)
-	assert.Equal(t, fmt.Sprintf("%s.outE(\"label1\",\"label2\")", graphName), e.String())
+	assert.NotNil(t, qb)
+	assert.Equal(t, fmt.Sprintf("%s.hasLabel(\"user\").limit(local,2)", graphName), qb.String())
 }
 
-func TestInE(t *testing.T) {
-
+func TestSample(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
 	g := NewGraph(graphName)
 	require.NotNil(t, g)
-	v := NewVertexG(g)
-	require.NotNil(t, v)
 
 	// WHEN
-	e := v.InE()
+	v := g.V().HasLabel("event").Sample(1000)
+	qbValues := g.V().HasLabel("event").Sample(1000).ValuesBy("value")
 
 	// THEN
-	assert.NotNil(t, e)
-	assert.Equal(t, fmt.Sprintf("%s.inE()", graphName), e.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"event\").sample(1000)", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"event\").sample(1000).values(\"value\")", graphName), qbValues.String())
 }
 
-func TestInEMulti(t *testing.T) {
-
+func TestDedup(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
 	g := NewGraph(graphName)
 	require.NotNil(t, g)
-	v := NewVertexG(g)
-	require.NotNil(t, v)
-	l1 := "label1"
-	l2 := "label2"
+
+	// WHEN
+	v := g.V().OutE("knows").InV().Dedup()
+	vBy := g.V().OutE("knows").InV().DedupBy("name")
+	vById := g.V().OutE("knows").InV().DedupById().Limit(10)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().outE(\"knows\").inV().dedup()", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().outE(\"knows\").inV().dedup().by(\"name\")", graphName), vBy.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().outE(\"knows\").inV().dedup().by(id).limit(10)", graphName), vById.String())
+}
+
+func TestOutE(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := NewVertexG(g)
+	require.NotNil(t, v)
+
+	// WHEN
+	e := v.OutE()
+
+	// THEN
+	assert.NotNil(t, e)
+	assert.Equal(t, fmt.Sprintf("%s.outE()", graphName), e.String())
+}
+
+func TestOutEMulti(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := NewVertexG(g)
+	require.NotNil(t, v)
+	l1 := "label1"
+	l2 := "label2"
+
+	// WHEN
+	e := v.OutE(l1, l2)
+
+	// THEN
+	assert.NotNil(t, e)
+	assert.Equal(t, fmt.Sprintf("%s.outE(\"label1\",\"label2\")", graphName), e.String())
+}
+
+func TestInE(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := NewVertexG(g)
+	require.NotNil(t, v)
+
+	// WHEN
+	e := v.InE()
+
+	// THEN
+	assert.NotNil(t, e)
+	assert.Equal(t, fmt.Sprintf("%s.inE()", graphName), e.String())
+}
+
+func TestInEMulti(t *testing.T) {
+
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := NewVertexG(g)
+	require.NotNil(t, v)
+	l1 := "label1"
+	l2 := "label2"
 
 	// WHEN
 	e := v.InE(l1, l2)
@@ -645,6 +1115,54 @@ func TestPropertyList(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%s.property(list,\"%s\",\"%s\")", graphName, key, value), v.String())
 }
 
+func TestPropertyWithCardinality(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	vSingle := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalitySingle, "name", "hans")
+	vList := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalityList, "name", "hans")
+	vInt := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalitySingle, "age", 42)
+	vFloat := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalityList, "temperature", 23.02)
+	vBool := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalitySingle, "available", true)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.property(single,\"name\",\"hans\")", graphName), vSingle.String())
+	assert.Equal(t, fmt.Sprintf("%s.property(list,\"name\",\"hans\")", graphName), vList.String())
+	assert.Equal(t, fmt.Sprintf("%s.property(single,\"age\",42)", graphName), vInt.String())
+	assert.Equal(t, fmt.Sprintf("%s.property(list,\"temperature\",23.02)", graphName), vFloat.String())
+	assert.Equal(t, fmt.Sprintf("%s.property(single,\"available\",true)", graphName), vBool.String())
+}
+
+func TestPropertyWithCardinalitySet(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	v := NewVertexG(g).PropertyWithCardinality(interfaces.CardinalitySet, "name", "hans")
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.property(set,\"name\",\"hans\")", graphName), v.String())
+}
+
+func TestPropertyWithCardinalitySetFailOnCosmos(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	v := NewVertexG(g)
+
+	// WHEN + THEN
+	assert.Panics(t, func() { v.PropertyWithCardinality(interfaces.CardinalitySet, "name", "hans") }, "The code did not panic")
+	assert.Panics(t, func() { v.PropertyWithCardinality(interfaces.CardinalitySingle, "name", nil) }, "The code did not panic")
+}
+
 func TestHasId(t *testing.T) {
 	// GIVEN
 	graphName := "mygraph"
@@ -717,3 +1235,463 @@ func TestVertexAsMulti(t *testing.T) {
 	assert.NotNil(t, v)
 	assert.Equal(t, fmt.Sprintf("%s.V().as(\"%s\",\"%s\")", graphName, l1, l2), v.String())
 }
+
+func TestAggregateStoreCap(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	qbAggregate := g.V().HasLabel("user").Aggregate("users").Cap("users")
+	qbStore := g.V().HasLabel("user").Store("x").OutE("knows").InV().Store("y").Cap("x", "y")
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").aggregate(\"users\").cap(\"users\")", graphName), qbAggregate.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").store(\"x\").outE(\"knows\").inV().store(\"y\").cap(\"x\",\"y\")", graphName), qbStore.String())
+	assert.Panics(t, func() { g.V().Cap() }, "The code did not panic")
+}
+
+func TestStoreCapInRepeat(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	qb := g.V().HasId("1").Repeat(Underscore().OutE("knows").InV().Store("x")).Times(2).Cap("x")
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasId(\"1\").repeat(__.outE(\"knows\").inV().store(\"x\")).times(2).cap(\"x\")", graphName), qb.String())
+}
+
+func TestCollectInto(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	qb := g.V().HasLabel("user").OutE("knows").InV().CollectInto("friends")
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").outE(\"knows\").inV().aggregate(\"friends\").cap(\"friends\")", graphName), qb.String())
+}
+
+func TestUpsertV(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	match := NewSimpleQB(".has(\"name\",\"hans\")")
+	create := NewSimpleQB("addV(\"user\").property(\"name\",\"hans\")")
+
+	// WHEN
+	v := g.V().HasLabel("user").UpsertV(match, create)
+	vNoMatch := g.V().Has("name", "hans").UpsertV(nil, create)
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	vTinkerpopStr := g.V().UpsertV(match, create).String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").has(\"name\",\"hans\").fold().coalesce(__.unfold(),addV(\"user\").property(\"name\",\"hans\"))", graphName), v.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().has(\"name\",\"hans\").fold().coalesce(__.unfold(),addV(\"user\").property(\"name\",\"hans\"))", graphName), vNoMatch.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().has(\"name\",\"hans\").fold().coalesce(unfold(),addV(\"user\").property(\"name\",\"hans\"))", graphName), vTinkerpopStr)
+}
+
+func TestUpsertVFail(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().UpsertV(NewSimpleQB(".has(\"name\",\"hans\")"), nil) })
+}
+
+func TestProjectBy(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().HasLabel("user").Project("name", "age").By("name").By("age")
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").project(\"name\",\"age\").by(\"name\").by(\"age\")", graphName), v.String())
+}
+
+func TestOrderBy(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	vKeyOrder := g.V().HasLabel("user").Order().By("age", interfaces.OrderDesc)
+	vTraversalOrder := g.V().Order().ByTraversal(Underscore().OutE("knows").Count(), interfaces.OrderAsc)
+	vTraversal := g.V().Project("name", "friends").By("name").ByTraversal(Underscore().OutE("knows").Count())
+	vShuffle := g.V().Order().By("name", interfaces.OrderShuffle)
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	vKeyOrderTinkerpopStr := g.V().Order().By("age", interfaces.OrderDesc).ByTraversal(Underscore().OutE("knows").Count(), interfaces.OrderAsc).String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").order().by(\"age\",decr)", graphName), vKeyOrder.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().order().by(__.outE(\"knows\").count(),incr)", graphName), vTraversalOrder.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().project(\"name\",\"friends\").by(\"name\").by(__.outE(\"knows\").count())", graphName), vTraversal.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().order().by(\"name\",shuffle)", graphName), vShuffle.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().order().by(\"age\",desc).by(outE(\"knows\").count(),asc)", graphName), vKeyOrderTinkerpopStr)
+	assert.Panics(t, func() { g.V().Order().ByTraversal(nil) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().Order().By("age", interfaces.OrderAsc, interfaces.OrderDesc) }, "The code did not panic")
+}
+
+func TestProjectFail(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().Project() })
+}
+
+func TestChooseOption(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().Choose(NewSimpleQB("values(\"x\")")).Option("a", NewSimpleQB("out()")).Option(2, NewSimpleQB("in()"))
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.V().choose(values(\"x\")).option(\"a\",out()).option(2,in())", graphName), v.String())
+}
+
+func TestChooseOptionFail(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().Choose(nil) })
+	assert.Panics(t, func() { g.V().Choose(NewSimpleQB("values(\"x\")")).Option("a", nil) })
+	assert.Panics(t, func() { g.V().Choose(NewSimpleQB("values(\"x\")")).Option(nil, NewSimpleQB("out()")) })
+}
+
+func TestChooseIfElse(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().Choose(Underscore().HasLabel("admin"), Underscore().OutE("manages").InV(), Underscore().OutE("knows").InV())
+
+	// THEN
+	assert.NotNil(t, v)
+	assert.Equal(t, fmt.Sprintf("%s.V().choose(__.hasLabel(\"admin\"),__.outE(\"manages\").inV(),__.outE(\"knows\").inV())", graphName), v.String())
+}
+
+func TestChooseIfElseFail(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().Choose() })
+	assert.Panics(t, func() { g.V().Choose(Underscore().HasLabel("admin"), Underscore().OutE("manages").InV()) })
+	assert.Panics(t, func() { g.V().Choose(nil, Underscore().OutE("manages").InV(), Underscore().OutE("knows").InV()) })
+	assert.Panics(t, func() { g.V().Choose(Underscore().HasLabel("admin"), Underscore().OutE("manages").InV(), nil) })
+}
+
+func TestUnion(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	in := NewSimpleQB("in(\"knows\")")
+	out := NewSimpleQB("out(\"knows\")")
+	both := NewSimpleQB("both(\"likes\")")
+
+	// WHEN
+	vTwo := g.V().Union(in, out)
+	vThree := g.V().Union(in, out, both)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().union(in(\"knows\"),out(\"knows\"))", graphName), vTwo.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().union(in(\"knows\"),out(\"knows\"),both(\"likes\"))", graphName), vThree.String())
+}
+
+func TestUnionEmpty(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		g.V().Union()
+	}()
+
+	// THEN
+	require.NotNil(t, recovered, "The code did not panic")
+	assert.EqualError(t, recovered.(error), "union needs at least one traversal")
+	assert.Panics(t, func() { g.V().Union(NewSimpleQB("out()"), nil) })
+}
+
+func TestLocal(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	vSimple := g.V().Local(NewSimpleQB("out(\"knows\").limit(1)"))
+	vAnonymous := g.V().HasLabel("user").Local(Underscore().OutE("knows").InV().Limit(1))
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().local(out(\"knows\").limit(1))", graphName), vSimple.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").local(__.outE(\"knows\").inV().limit(1))", graphName), vAnonymous.String())
+	assert.Panics(t, func() { g.V().Local(nil) }, "The code did not panic")
+}
+
+func TestBarrier(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	unsized := g.V().HasLabel("user").Barrier().Count().String()
+	sized := g.V().OutE("knows").InV().Barrier(2500).Local(Underscore().OutE("knows").Limit(1)).String()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").barrier().count()", graphName), unsized)
+	assert.Equal(t, fmt.Sprintf("%s.V().outE(\"knows\").inV().barrier(2500).local(__.outE(\"knows\").limit(1))", graphName), sized)
+	assert.Panics(t, func() { g.V().Barrier(0) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().Barrier(1, 2) }, "The code did not panic")
+}
+
+func TestSideEffect(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	qb := g.V().HasLabel("user").SideEffect(Underscore().Properties("x").Drop()).Count().String()
+	panicsOnNil := assert.Panics(t, func() { g.V().SideEffect(nil) }, "The code did not panic")
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").sideEffect(properties(\"x\").drop()).count()", graphName), qb)
+	assert.True(t, panicsOnNil)
+	assert.Panics(t, func() { g.V().SideEffect(NewSimpleQB("drop()")) }, "The code did not panic")
+}
+
+func TestNotAndOr(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	SetQueryLanguageTo(QueryLanguageTinkerpopGremlin)
+	not := g.V().Not(Underscore().Has("x")).String()
+	andSingle := g.V().And(Underscore().Has("a")).String()
+	andMultiple := g.V().And(Underscore().Has("a"), Underscore().Has("b")).String()
+	orSingle := g.V().Or(Underscore().Has("a")).String()
+	orMultiple := g.V().HasLabel("user").Or(Underscore().Has("a"), Underscore().Not(Underscore().Has("b")), Underscore().OutE("knows")).String()
+	SetQueryLanguageTo(QueryLanguageCosmosDB)
+	notCosmos := g.V().Not(Underscore().Has("x")).String()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().not(has(\"x\"))", graphName), not)
+	assert.Equal(t, fmt.Sprintf("%s.V().and(has(\"a\"))", graphName), andSingle)
+	assert.Equal(t, fmt.Sprintf("%s.V().and(has(\"a\"),has(\"b\"))", graphName), andMultiple)
+	assert.Equal(t, fmt.Sprintf("%s.V().or(has(\"a\"))", graphName), orSingle)
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"user\").or(has(\"a\"),not(has(\"b\")),outE(\"knows\"))", graphName), orMultiple)
+	assert.Equal(t, fmt.Sprintf("%s.V().not(__.has(\"x\"))", graphName), notCosmos)
+	assert.Panics(t, func() { g.V().Not(nil) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().And() }, "The code did not panic")
+	assert.Panics(t, func() { g.V().Or(Underscore().Has("a"), nil) }, "The code did not panic")
+}
+
+func TestNumericAggregation(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	values := NewSimpleQB(".values(\"price\")")
+
+	// WHEN
+	sum := g.V().HasLabel("product").Add(values).Sum()
+	max := g.V().HasLabel("product").Add(values).Max()
+	min := g.V().HasLabel("product").Add(values).Min()
+	mean := g.V().HasLabel("product").Add(values).Mean()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"product\").values(\"price\").sum()", graphName), sum.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"product\").values(\"price\").max()", graphName), max.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"product\").values(\"price\").min()", graphName), min.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().hasLabel(\"product\").values(\"price\").mean()", graphName), mean.String())
+}
+
+func TestRepeat(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	out := NewSimpleQB("out(\"parent\")")
+
+	// WHEN
+	v := g.V().Repeat(out).Times(3)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).times(3)", graphName), v.String())
+}
+
+func TestRepeatUntilAndUntilRepeat(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	out := NewSimpleQB("out(\"parent\")")
+	isRoot := NewSimpleQB("hasLabel(\"root\")")
+
+	// WHEN
+	doWhile := g.V().RepeatUntil(out, isRoot)
+	whileDo := g.V().UntilRepeat(isRoot, out)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).until(hasLabel(\"root\"))", graphName), doWhile.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().until(hasLabel(\"root\")).repeat(out(\"parent\"))", graphName), whileDo.String())
+}
+
+func TestRepeatEmitAndEmitRepeat(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	out := NewSimpleQB("out(\"parent\")")
+	isUser := NewSimpleQB("hasLabel(\"user\")")
+
+	// WHEN
+	emitAfter := g.V().RepeatEmit(out, nil).Times(2)
+	emitAfterFiltered := g.V().RepeatEmit(out, isUser)
+	emitBefore := g.V().EmitRepeat(nil, out).Times(2)
+	emitBeforeFiltered := g.V().EmitRepeat(isUser, out)
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).emit().times(2)", graphName), emitAfter.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).emit(hasLabel(\"user\"))", graphName), emitAfterFiltered.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().emit().repeat(out(\"parent\")).times(2)", graphName), emitBefore.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().emit(hasLabel(\"user\")).repeat(out(\"parent\"))", graphName), emitBeforeFiltered.String())
+}
+
+func TestRepeatWithAnonymousTraversal(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().RepeatUntil(Underscore().Add(NewSimpleQB(".out()")), Underscore().HasLabel("root"))
+
+	// THEN
+	assert.Equal(t, "g.V().repeat(__.out()).until(__.hasLabel(\"root\"))", v.String())
+}
+
+func TestRepeatNilTraversal(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+	out := NewSimpleQB("out()")
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().Repeat(nil) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().RepeatUntil(nil, out) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().RepeatUntil(out, nil) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().UntilRepeat(out, nil) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().UntilRepeat(nil, out) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().RepeatEmit(nil, out) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().EmitRepeat(out, nil) }, "The code did not panic")
+}
+
+func TestRepeatLoopOrdering(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+	out := NewSimpleQB("out(\"parent\")")
+	isRoot := NewSimpleQB("hasLabel(\"root\")")
+
+	// WHEN
+	repeatTimes := g.V().Repeat(out).Times(3).Count().String()
+	whileDoTimes := g.V().UntilRepeat(isRoot, out).Times(5).String()
+	doWhilePath := g.V().RepeatUntil(out, isRoot).Path().String()
+	emitBeforeTimes := g.V().EmitRepeat(nil, out).Times(2).Dedup().String()
+	whileDoLeadingTimes := g.V().TimesRepeat(2, out).Path().String()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).times(3).count()", graphName), repeatTimes)
+	assert.Equal(t, fmt.Sprintf("%s.V().until(hasLabel(\"root\")).repeat(out(\"parent\")).times(5)", graphName), whileDoTimes)
+	assert.Equal(t, fmt.Sprintf("%s.V().repeat(out(\"parent\")).until(hasLabel(\"root\")).path()", graphName), doWhilePath)
+	assert.Equal(t, fmt.Sprintf("%s.V().emit().repeat(out(\"parent\")).times(2).dedup()", graphName), emitBeforeTimes)
+	assert.Equal(t, fmt.Sprintf("%s.V().times(2).repeat(out(\"parent\")).path()", graphName), whileDoLeadingTimes)
+}
+
+func TestOrphanedLoopModulator(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+	out := NewSimpleQB("out()")
+
+	// WHEN + THEN
+	assert.Panics(t, func() { g.V().Times(3) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().HasLabel("user").Times(3) }, "trailing times without repeat")
+	assert.Panics(t, func() { g.V().Repeat(out).Dedup().Times(3) }, "The code did not panic")
+	assert.Panics(t, func() { Underscore().Times(3) }, "The code did not panic")
+	assert.Panics(t, func() { g.V().TimesRepeat(3, nil) }, "The code did not panic")
+	assert.NotPanics(t, func() { g.V().RepeatUntil(out, NewSimpleQB("hasLabel(\"root\")")).Times(3) }, "The code did panic")
+	assert.NotPanics(t, func() { g.V().HasLabel("user").TimesRepeat(3, out).Dedup() }, "The code did panic")
+}
+
+func TestPath(t *testing.T) {
+	// GIVEN
+	graphName := "mygraph"
+	g := NewGraph(graphName)
+	require.NotNil(t, g)
+
+	// WHEN
+	path := g.V().Path()
+	simplePath := g.V().SimplePath()
+	cyclicPath := g.V().CyclicPath()
+
+	// THEN
+	assert.Equal(t, fmt.Sprintf("%s.V().path()", graphName), path.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().simplePath()", graphName), simplePath.String())
+	assert.Equal(t, fmt.Sprintf("%s.V().cyclicPath()", graphName), cyclicPath.String())
+}
+
+func TestPathBy(t *testing.T) {
+	// GIVEN
+	g := NewGraph("g")
+	require.NotNil(t, g)
+
+	// WHEN
+	v := g.V().HasId("1").RepeatUntil(Underscore().OutE("knows").InV().SimplePath(), Underscore().HasId("2")).Path().By("name")
+
+	// THEN
+	assert.Equal(t, "g.V().hasId(\"1\").repeat(__.outE(\"knows\").inV().simplePath()).until(__.hasId(\"2\")).path().by(\"name\")", v.String())
+}
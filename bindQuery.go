@@ -0,0 +1,75 @@
+package gremcos
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/supplyon/gremcos/api"
+)
+
+// regexpBindingName matches the names that are allowed to be used as binding (variable) names
+var regexpBindingName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedBindingNames are names that would shadow the variables/ keywords of the gremlin server
+var reservedBindingNames = map[string]bool{"g": true, "graph": true, "__": true}
+
+// BindQuery creates a query and the according bindings based on the given template and arguments. The result can be used
+// for ExecuteWithBindings. Instead of interpolating values into raw query strings (which is prone to injections) the values
+// are referenced via placeholders {{<name>}} and passed to the server as bindings.
+//	query, bindings, err := BindQuery(`g.V().has("name",{{name}}).has("age",gt({{age}}))`, map[string]interface{}{"name": "hans", "age": 42})
+//	==> query: g.V().has("name",name).has("age",gt(age)) bindings: map[age:42 name:hans]
+// An error is returned in case a placeholder has no matching argument, an argument is not referenced by the template,
+// a placeholder is located inside of a string literal (see api.ScanPlaceholders) or its name is not a valid binding name.
+func BindQuery(template string, args map[string]interface{}) (query string, bindings map[string]interface{}, err error) {
+	// a placeholder inside of a string literal would be part of the literal instead of a binding
+	placeholders, err := api.ScanPlaceholders(template)
+	if err != nil {
+		return "", nil, err
+	}
+
+	bindings = make(map[string]interface{})
+	var missing []string
+	queryBuilder := strings.Builder{}
+	last := 0
+	for _, placeholder := range placeholders {
+		name := placeholder.Name
+
+		if !regexpBindingName.MatchString(name) || reservedBindingNames[name] {
+			return "", nil, fmt.Errorf("Invalid placeholder '%s', the name has to match %s and must not be one of g, graph or __", name, regexpBindingName.String())
+		}
+
+		queryBuilder.WriteString(template[last:placeholder.Start])
+		queryBuilder.WriteString(name)
+		last = placeholder.End
+
+		value, ok := args[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if value == nil {
+			return "", nil, fmt.Errorf("Argument '%s' is nil, null values are not supported", name)
+		}
+		bindings[name] = value
+	}
+
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("Missing arguments for the placeholders %s", strings.Join(missing, ","))
+	}
+
+	var unused []string
+	for name := range args {
+		if _, ok := bindings[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("Arguments %s are not referenced by the template", strings.Join(unused, ","))
+	}
+
+	queryBuilder.WriteString(template[last:])
+	return queryBuilder.String(), bindings, nil
+}
@@ -0,0 +1,85 @@
+package gremcos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindQuery(t *testing.T) {
+	// GIVEN
+	template := `g.V().has("name",{{name}}).has("age",gt({{ age }})).has("nickname",{{name}})`
+	args := map[string]interface{}{"name": "hans\").drop()", "age": 42}
+
+	// WHEN
+	query, bindings, err := BindQuery(template, args)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `g.V().has("name",name).has("age",gt(age)).has("nickname",name)`, query)
+	assert.Equal(t, args, bindings)
+}
+
+func TestBindQueryMissingArgs(t *testing.T) {
+	// GIVEN
+	template := `g.V().has("name",{{name}}).has("age",{{age}})`
+	args := map[string]interface{}{"name": "hans"}
+
+	// WHEN
+	query, bindings, err := BindQuery(template, args)
+
+	// THEN
+	assert.EqualError(t, err, "Missing arguments for the placeholders age")
+	assert.Empty(t, query)
+	assert.Nil(t, bindings)
+}
+
+func TestBindQueryExtraArgs(t *testing.T) {
+	// GIVEN
+	template := `g.V().has("name",{{name}})`
+	args := map[string]interface{}{"name": "hans", "age": 42, "city": "berlin"}
+
+	// WHEN
+	query, bindings, err := BindQuery(template, args)
+
+	// THEN
+	assert.EqualError(t, err, "Arguments age,city are not referenced by the template")
+	assert.Empty(t, query)
+	assert.Nil(t, bindings)
+}
+
+func TestBindQueryInvalidPlaceholders(t *testing.T) {
+	// WHEN + THEN
+	_, _, err := BindQuery(`g.V().has("name","{{name}}")`, map[string]interface{}{"name": "hans"})
+	assert.Error(t, err, "quoted placeholder")
+
+	_, _, err = BindQuery(`g.V().has("name",{{name).drop()}})`, map[string]interface{}{"name": "hans"})
+	assert.Error(t, err, "invalid name")
+
+	_, _, err = BindQuery(`{{g}}.V()`, map[string]interface{}{"g": "hans"})
+	assert.Error(t, err, "reserved name")
+
+	_, _, err = BindQuery(`g.V().has("name",{{name}})`, map[string]interface{}{"name": nil})
+	assert.Error(t, err, "nil value")
+}
+
+func TestBindQueryPlaceholderInsideLiteral(t *testing.T) {
+	// WHEN
+	query, bindings, err := BindQuery(`g.V().has("name","user_{{id}}")`, map[string]interface{}{"id": "1"})
+
+	// THEN
+	assert.EqualError(t, err, "placeholder '{{id}}' must not be quoted")
+	assert.Empty(t, query)
+	assert.Nil(t, bindings)
+}
+
+func TestBindQueryNoPlaceholders(t *testing.T) {
+	// WHEN
+	query, bindings, err := BindQuery(`g.V()`, nil)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, `g.V()`, query)
+	assert.Empty(t, bindings)
+}
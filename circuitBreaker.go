@@ -0,0 +1,143 @@
+package gremcos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned (without contacting the CosmosDB) in case the circuit breaker is open, see WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("Circuit breaker is open, the CosmosDB is regarded as unreachable")
+
+// CircuitState is the state of the circuit breaker, see WithCircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed means the queries are executed as usual
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means the queries fail immediately with ErrCircuitOpen until the cooldown elapsed
+	CircuitOpen
+	// CircuitHalfOpen means the cooldown elapsed and a single probe query is executed to test the recovery
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker keeps track of the consecutive failures to reach the CosmosDB and rejects queries
+// while the CosmosDB is regarded as unreachable. A nil circuitBreaker is disabled and allows all queries.
+type circuitBreaker struct {
+	mux              sync.Mutex
+	failureThreshold int
+	cooldown         time.Duration
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// probing is true while the probe query of the half-open state is executed
+	probing bool
+
+	// now returns the current time, can be replaced for testing
+	now func() time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// allow returns ErrCircuitOpen in case the query must not be executed. If probe is true the query is the probe
+// of the half-open state and endProbe has to be called as soon as it is completed.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.state == CircuitClosed {
+		return false, nil
+	}
+
+	if b.state == CircuitOpen {
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false, ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+	}
+
+	// half-open, only one probe at a time
+	if b.probing {
+		return false, ErrCircuitOpen
+	}
+	b.probing = true
+	return true, nil
+}
+
+// endProbe marks the probe as completed. In case the probe did not record a result (e.g. it was rejected before the
+// CosmosDB was contacted) the breaker stays half-open and the next query is used as probe.
+func (b *circuitBreaker) endProbe() {
+	if b == nil {
+		return
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.probing = false
+}
+
+// record updates the breaker based on whether the CosmosDB could be reached. The breaker is closed on success
+// and opened after failureThreshold consecutive failures or a failed probe.
+func (b *circuitBreaker) record(reached bool) {
+	if b == nil {
+		return
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if reached {
+		b.failures = 0
+		b.state = CircuitClosed
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// currentState returns the state of the breaker, the open state turns into half-open as soon as the cooldown elapsed
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// CircuitBreakerState returns the state of the circuit breaker (see WithCircuitBreaker), CircuitClosed if it is disabled.
+func (c *cosmosImpl) CircuitBreakerState() CircuitState {
+	return c.breaker.currentState()
+}
@@ -0,0 +1,163 @@
+package gremcos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+func TestCircuitBreakerFailFastAndRecovery(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithCircuitBreaker(2, time.Minute))
+	now := time.Now()
+	toCosmosImpl(t, cosmos).breaker.now = func() time.Time { return now }
+	success := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	dialErr := dialError{err: fmt.Errorf("dial tcp: connection refused")}
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, dialErr).Times(2),
+		mockedQueryExecutor.EXPECT().Execute("g.V()").Return(success, nil),
+	)
+
+	// WHEN
+	_, err1 := cosmos.Execute("g.V()")
+	stateAfterFirstFailure := cosmos.CircuitBreakerState()
+	_, err2 := cosmos.Execute("g.V()")
+	stateTripped := cosmos.CircuitBreakerState()
+	// fails fast without using the pool
+	_, errFailFast := cosmos.Execute("g.V()")
+	errStreamFailFast := cosmos.ExecuteStream("g.V()", func(interfaces.Response) error { return nil })
+	now = now.Add(time.Minute)
+	stateAfterCooldown := cosmos.CircuitBreakerState()
+	_, errProbe := cosmos.Execute("g.V()")
+	stateRecovered := cosmos.CircuitBreakerState()
+
+	// THEN
+	assert.Equal(t, dialErr, err1)
+	assert.Equal(t, CircuitClosed, stateAfterFirstFailure)
+	assert.Equal(t, dialErr, err2)
+	assert.Equal(t, CircuitOpen, stateTripped)
+	assert.Equal(t, ErrCircuitOpen, errFailFast)
+	assert.Equal(t, ErrCircuitOpen, errStreamFailFast)
+	assert.Equal(t, CircuitHalfOpen, stateAfterCooldown)
+	assert.NoError(t, errProbe)
+	assert.Equal(t, CircuitClosed, stateRecovered)
+}
+
+func TestCircuitBreakerFailedProbe(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithCircuitBreaker(1, time.Minute))
+	now := time.Now()
+	toCosmosImpl(t, cosmos).breaker.now = func() time.Time { return now }
+	dialErr := dialError{err: fmt.Errorf("dial tcp: connection refused")}
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, dialErr).Times(2)
+
+	// WHEN
+	_, errTrip := cosmos.Execute("g.V()")
+	now = now.Add(time.Minute)
+	_, errProbe := cosmos.Execute("g.V()")
+	stateAfterProbe := cosmos.CircuitBreakerState()
+	_, errFailFast := cosmos.Execute("g.V()")
+
+	// THEN
+	assert.Equal(t, dialErr, errTrip)
+	assert.Equal(t, dialErr, errProbe)
+	assert.Equal(t, CircuitOpen, stateAfterProbe, "a failed probe has to open the breaker again")
+	assert.Equal(t, ErrCircuitOpen, errFailFast)
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	disconnects := 0
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithCircuitBreaker(1, time.Minute), WithOnDisconnect(func(err error) { disconnects++ }))
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, fmt.Errorf("serialization failed"))
+	mockedQueryExecutor.EXPECT().IsConnected().Return(false).AnyTimes()
+
+	// WHEN
+	_, errInvalidID := cosmos.ExecuteWithID("no-uuid", "g.V()")
+	errInvalidAsyncID := cosmos.ExecuteAsyncWithID("no-uuid", "g.V()", make(chan interfaces.AsyncResponse))
+	_, errClient := cosmos.Execute("g.V()")
+
+	// THEN
+	assert.Error(t, errInvalidID)
+	assert.Error(t, errInvalidAsyncID)
+	assert.Error(t, errClient)
+	assert.Equal(t, CircuitClosed, cosmos.CircuitBreakerState(), "client side errors must not trip the breaker")
+	assert.Equal(t, 0, disconnects)
+}
+
+func TestIsTransportError(t *testing.T) {
+	assert.True(t, isTransportError(errNoConnection))
+	assert.True(t, isTransportError(errors.Wrap(errNoConnection, "query: g.V()")))
+	assert.True(t, isTransportError(dialError{err: fmt.Errorf("connection refused")}))
+	assert.True(t, isTransportError(errors.Wrap(socketClosedByServerError{}, "query: g.V()")))
+	assert.True(t, isTransportError(QueryTimeoutError{}))
+	assert.False(t, isTransportError(fmt.Errorf("serialization failed")))
+	assert.False(t, isTransportError(nil))
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	// GIVEN
+	breaker := newCircuitBreaker(1, time.Minute)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	breaker.record(false)
+	now = now.Add(time.Minute)
+
+	// WHEN
+	probe, errProbe := breaker.allow()
+	_, errSecond := breaker.allow()
+	breaker.endProbe()
+	probeAgain, errAgain := breaker.allow()
+
+	// THEN
+	assert.True(t, probe)
+	assert.NoError(t, errProbe)
+	assert.Equal(t, ErrCircuitOpen, errSecond, "only one probe at a time is allowed")
+	assert.True(t, probeAgain, "the next query has to be used as probe if the previous one recorded no result")
+	assert.NoError(t, errAgain)
+}
+
+func TestCircuitBreakerIgnoresErroneousResponses(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithCircuitBreaker(1, time.Minute))
+	scriptError := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusScriptEvaluationError}}}
+	mockedQueryExecutor.EXPECT().Execute("g.V().invalid()").Return(scriptError, nil)
+
+	// WHEN
+	_, err := cosmos.Execute("g.V().invalid()")
+
+	// THEN
+	assert.Error(t, err)
+	assert.Equal(t, CircuitClosed, cosmos.CircuitBreakerState(), "a received response proves that the CosmosDB is reachable")
+}
+
+func TestNewWithInvalidCircuitBreaker(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+
+	// WHEN
+	cosmosInvalidThreshold, errThreshold := New("ws://host", WithCircuitBreaker(0, time.Minute), withMetrics(metrics))
+	cosmosInvalidCooldown, errCooldown := New("ws://host", WithCircuitBreaker(3, 0), withMetrics(metrics))
+
+	// THEN
+	require.Error(t, errThreshold)
+	assert.Nil(t, cosmosInvalidThreshold)
+	require.Error(t, errCooldown)
+	assert.Nil(t, cosmosInvalidCooldown)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -27,6 +28,51 @@ func (socketClosedErr socketClosedByServerError) Error() string {
 	return fmt.Sprintf("received msgType == -1 this is no frame, closing the readworker %s", detailErrMsg)
 }
 
+// errNoConnection is returned in case a request can't be sent since the client is not connected.
+// The request was not written to the socket, hence it is safe to retry it on another connection.
+var errNoConnection = errors.New("Can't write - no connection")
+
+// dialError is returned in case a new connection can't be established
+type dialError struct {
+	err error
+}
+
+func (dialErr dialError) Error() string {
+	return dialErr.err.Error()
+}
+
+// isTransportError returns true in case the given error (or one of its causes) tells that the CosmosDB could not be reached,
+// i.e. the connection could not be established, the request could not be written, the socket was closed or no response was
+// received in time. Other errors, e.g. an invalid request id or a failed serialization, are caused by the client.
+func isTransportError(err error) bool {
+	for err != nil {
+		if err == errNoConnection {
+			return true
+		}
+		switch err.(type) {
+		case dialError, socketClosedByServerError, QueryTimeoutError:
+			return true
+		}
+
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = causer.Cause()
+	}
+	return false
+}
+
+// QueryTimeoutError is returned in case the response of a query was not received within the configured query timeout.
+type QueryTimeoutError struct {
+	RequestID string
+	Timeout   time.Duration
+}
+
+func (queryTimeoutErr QueryTimeoutError) Error() string {
+	return fmt.Sprintf("no response for request with id %s received within the query timeout of %s", queryTimeoutErr.RequestID, queryTimeoutErr.Timeout)
+}
+
 // client is a container for the gremcos client.
 type client struct {
 
@@ -62,6 +108,17 @@ type client struct {
 	// is still alive. The interval to send the ping frame to the peer.
 	pingInterval time.Duration
 
+	// queryTimeout is the maximum time to wait for the response(s) of a query.
+	// If this timeout is set to 0, the timeout is unlimited.
+	queryTimeout time.Duration
+
+	// serializer is used to serialize the requests and to deserialize the responses
+	serializer Serializer
+
+	// stripScriptComments specifies whether comment lines and trailing whitespace are removed
+	// from the scripts of ExecuteFile and ExecuteFileWithBindings
+	stripScriptComments bool
+
 	wg  sync.WaitGroup
 	mux sync.RWMutex
 
@@ -90,6 +147,31 @@ func PingInterval(interval time.Duration) clientOption {
 	}
 }
 
+// QueryTimeout sets the maximum time to wait for all responses of a query.
+// In case the timeout expires the query fails with a QueryTimeoutError and the client is closed,
+// since the order of the responses on the underlying websocket can't be ensured any more.
+func QueryTimeout(timeout time.Duration) clientOption {
+	return func(c *client) {
+		c.queryTimeout = timeout
+	}
+}
+
+// SetSerializer sets the serializer that is used for the requests and responses
+func SetSerializer(serializer Serializer) clientOption {
+	return func(c *client) {
+		c.serializer = serializer
+	}
+}
+
+// StripScriptComments specifies whether the lines only containing a // comment and the trailing whitespace of each line
+// are removed from the scripts that are sent using ExecuteFile and ExecuteFileWithBindings. This is needed for servers
+// that don't support comments. Per default the scripts are sent as they are (apart from the normalization of the line endings).
+func StripScriptComments(enabled bool) clientOption {
+	return func(c *client) {
+		c.stripScriptComments = enabled
+	}
+}
+
 func newClient(dialer interfaces.Dialer, options ...clientOption) *client {
 	client := &client{
 		conn:                   dialer,
@@ -100,6 +182,7 @@ func newClient(dialer interfaces.Dialer, options ...clientOption) *client {
 		pingInterval:           60 * time.Second,
 		quitChannel:            make(chan struct{}),
 		credentialProvider:     noCredentials{},
+		serializer:             GraphSONv2Serializer{},
 	}
 
 	for _, opt := range options {
@@ -175,7 +258,7 @@ func (c *client) IsConnected() bool {
 }
 
 func (c *client) executeRequest(query string, bindings, rebindings *map[string]interface{}) ([]interfaces.Response, error) {
-	var req request
+	var req Request
 	var id string
 	var err error
 
@@ -188,8 +271,12 @@ func (c *client) executeRequest(query string, bindings, rebindings *map[string]i
 	if err != nil {
 		return nil, err
 	}
+	return c.executePreparedRequest(req, id, query)
+}
 
-	msg, err := packageRequest(req)
+// executePreparedRequest sends the given (prepared) request and waits for the according responses.
+func (c *client) executePreparedRequest(req Request, id string, query string) ([]interfaces.Response, error) {
+	msg, err := packageRequest(req, c.serializer)
 	if err != nil {
 		return nil, err
 	}
@@ -208,7 +295,7 @@ func (c *client) executeRequest(query string, bindings, rebindings *map[string]i
 }
 
 func (c *client) executeAsync(query string, bindings, rebindings *map[string]interface{}, responseChannel chan interfaces.AsyncResponse) (err error) {
-	var req request
+	var req Request
 	var id string
 	if bindings != nil && rebindings != nil {
 		req, id, err = prepareRequestWithBindings(query, *bindings, *rebindings)
@@ -218,8 +305,12 @@ func (c *client) executeAsync(query string, bindings, rebindings *map[string]int
 	if err != nil {
 		return
 	}
+	return c.executePreparedAsync(req, id, responseChannel)
+}
 
-	msg, err := packageRequest(req)
+// executePreparedAsync sends the given (prepared) request, the responses are streamed to the given channel.
+func (c *client) executePreparedAsync(req Request, id string, responseChannel chan interfaces.AsyncResponse) (err error) {
+	msg, err := packageRequest(req, c.serializer)
 	if err != nil {
 		log.Println(err)
 		return
@@ -259,7 +350,7 @@ func (c *client) authenticate(requestID string) error {
 
 	req := prepareAuthRequest(requestID, username, password)
 
-	msg, err := packageRequest(req)
+	msg, err := packageRequest(req, c.serializer)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -272,7 +363,7 @@ func (c *client) authenticate(requestID string) error {
 // ExecuteWithBindings formats a raw Gremlin query, sends it to Gremlin Server, and returns the result.
 func (c *client) ExecuteWithBindings(query string, bindings, rebindings map[string]interface{}) (resp []interfaces.Response, err error) {
 	if !c.conn.IsConnected() {
-		return resp, fmt.Errorf("Can't write - no connection")
+		return resp, errNoConnection
 	}
 	resp, err = c.executeRequest(query, &bindings, &rebindings)
 	return
@@ -281,32 +372,166 @@ func (c *client) ExecuteWithBindings(query string, bindings, rebindings map[stri
 // Execute formats a raw Gremlin query, sends it to Gremlin Server, and returns the result.
 func (c *client) Execute(query string) (resp []interfaces.Response, err error) {
 	if !c.conn.IsConnected() {
-		return resp, fmt.Errorf("Can't write - no connection")
+		return resp, errNoConnection
 	}
 	resp, err = c.executeRequest(query, nil, nil)
 	return
 }
 
+// ExecuteWithID formats a raw Gremlin query, sends it to Gremlin Server using the given request id (has to be a UUID), and returns the result.
+func (c *client) ExecuteWithID(requestID, query string) (resp []interfaces.Response, err error) {
+	if !c.conn.IsConnected() {
+		return resp, errNoConnection
+	}
+	req, id, err := prepareRequestWithID(requestID, query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err = c.executePreparedRequest(req, id, query)
+	return
+}
+
 // Execute formats a raw Gremlin query, sends it to Gremlin Server, and the results are streamed to channel provided in method paramater.
 func (c *client) ExecuteAsync(query string, responseChannel chan interfaces.AsyncResponse) (err error) {
 	if !c.conn.IsConnected() {
-		return fmt.Errorf("Can't write - no connection")
+		return errNoConnection
 	}
 	err = c.executeAsync(query, nil, nil, responseChannel)
 	return
 }
 
+// ExecuteAsyncWithID is the same as ExecuteAsync but the given request id (has to be a UUID) is used for the request.
+func (c *client) ExecuteAsyncWithID(requestID, query string, responseChannel chan interfaces.AsyncResponse) (err error) {
+	if !c.conn.IsConnected() {
+		return errNoConnection
+	}
+	req, id, err := prepareRequestWithID(requestID, query)
+	if err != nil {
+		return err
+	}
+	err = c.executePreparedAsync(req, id, responseChannel)
+	return
+}
+
+// ExecuteBatch executes the given queries pipelined over this connection. This means all requests are sent
+// without waiting for the responses of the previous ones. Afterwards the responses are collected in the order of the queries.
+// The responses are returned per query, i.e. resp[i] contains all responses (chunks) of queries[i].
+// In case a query fails the error of the first failing query is returned.
+func (c *client) ExecuteBatch(queries []string) (resp [][]interfaces.Response, err error) {
+	if !c.conn.IsConnected() {
+		return resp, errNoConnection
+	}
+
+	ids := make([]string, 0, len(queries))
+	msgs := make([][]byte, 0, len(queries))
+	for _, query := range queries {
+		req, id, err := prepareRequest(query)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := packageRequest(req, c.serializer)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		msgs = append(msgs, msg)
+	}
+
+	resp = make([][]interfaces.Response, len(queries))
+
+	// send all requests without waiting for the responses
+	for i, id := range ids {
+		c.responseNotifier.Store(id, newSafeCloseErrorChannel(1))
+		c.responseStatusNotifier.Store(id, newSafeCloseIntChannel(1))
+		c.dispatchRequest(msgs[i])
+	}
+
+	// collect the responses in the order of the queries
+	for i, id := range ids {
+		// this call blocks until the response has been retrieved from the server
+		responses, errRetrieve := c.retrieveResponse(id)
+		resp[i] = responses
+
+		if errRetrieve != nil && err == nil {
+			err = errors.Wrapf(errRetrieve, "query %d: %s", i, queries[i])
+		}
+
+		// the connection was closed due to a timeout, there is no need to wait for the remaining responses
+		if _, ok := errors.Cause(errRetrieve).(QueryTimeoutError); ok {
+			for _, remainingID := range ids[i+1:] {
+				c.discardResponse(remainingID)
+			}
+			break
+		}
+	}
+	return resp, err
+}
+
+// flattenResponses returns the responses of all queries of a batch (see ExecuteBatch) as one list in the order of the queries
+func flattenResponses(batch [][]interfaces.Response) []interfaces.Response {
+	var responses []interfaces.Response
+	for _, queryResponses := range batch {
+		responses = append(responses, queryResponses...)
+	}
+	return responses
+}
+
+// discardResponse removes all notifiers and results of the response with the given id since it is not needed any more.
+func (c *client) discardResponse(id string) {
+	if notifier, ok := c.responseNotifier.Load(id); ok {
+		notifier.(*safeCloseErrorChannel).Close()
+	}
+	if notifier, ok := c.responseStatusNotifier.Load(id); ok {
+		notifier.(*safeCloseIntChannel).Close()
+	}
+	c.responseNotifier.Delete(id)
+	c.responseStatusNotifier.Delete(id)
+	c.deleteResponse(id)
+}
+
+// readScriptFile reads the Gremlin script from the given file and normalizes it: A leading UTF-8 BOM is stripped and
+// the line endings are converted to \n. With stripComments the lines only containing a // comment are removed and
+// the trailing whitespace of each line is trimmed (not all servers support comments).
+// An error is returned in case the script is empty.
+func readScriptFile(path string, stripComments bool) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	script := strings.TrimPrefix(string(data), "\ufeff")
+	script = strings.Replace(script, "\r\n", "\n", -1)
+	script = strings.Replace(script, "\r", "\n", -1)
+
+	if stripComments {
+		lines := strings.Split(script, "\n")
+		statements := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "//") {
+				continue
+			}
+			statements = append(statements, strings.TrimRight(line, " \t"))
+		}
+		script = strings.TrimSpace(strings.Join(statements, "\n"))
+	}
+
+	if len(strings.TrimSpace(script)) == 0 {
+		return "", fmt.Errorf("The script file '%s' is empty", path)
+	}
+	return script, nil
+}
+
 // ExecuteFileWithBindings takes a file path to a Gremlin script, sends it to Gremlin Server with bindings, and returns the result.
 func (c *client) ExecuteFileWithBindings(path string, bindings, rebindings map[string]interface{}) (resp []interfaces.Response, err error) {
 	if !c.conn.IsConnected() {
-		return resp, fmt.Errorf("Can't write - no connection")
+		return resp, errNoConnection
 	}
-	d, err := ioutil.ReadFile(path) // Read script from file
+	query, err := readScriptFile(path, c.stripScriptComments)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	query := string(d)
 	resp, err = c.executeRequest(query, &bindings, &rebindings)
 	return
 }
@@ -314,14 +539,13 @@ func (c *client) ExecuteFileWithBindings(path string, bindings, rebindings map[s
 // ExecuteFile takes a file path to a Gremlin script, sends it to Gremlin Server, and returns the result.
 func (c *client) ExecuteFile(path string) (resp []interfaces.Response, err error) {
 	if !c.conn.IsConnected() {
-		return resp, fmt.Errorf("Can't write - no connection")
+		return resp, errNoConnection
 	}
-	d, err := ioutil.ReadFile(path) // Read script from file
+	query, err := readScriptFile(path, c.stripScriptComments)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	query := string(d)
 	resp, err = c.executeRequest(query, nil, nil)
 	return
 }
@@ -459,6 +683,27 @@ func (c *client) workerSaveExit(name string, errs chan<- error) {
 	}
 }
 
+// startQueryTimer returns a channel that fires as soon as the query timeout is exceeded and
+// a function to stop the timer. In case no query timeout is configured the channel never fires.
+func (c *client) startQueryTimer() (<-chan time.Time, func()) {
+	if c.queryTimeout <= 0 {
+		return nil, func() {}
+	}
+
+	timer := time.NewTimer(c.queryTimeout)
+	return timer.C, func() { timer.Stop() }
+}
+
+// onQueryTimeout handles an exceeded query timeout for the given request.
+// The client is closed since responses for the abandoned request might still arrive
+// which makes the connection unusable for further requests.
+func (c *client) onQueryTimeout(id string) error {
+	err := QueryTimeoutError{RequestID: id, Timeout: c.queryTimeout}
+	c.setLastErr(err)
+	c.Close()
+	return err
+}
+
 // Ping send a ping over the socket to the peer
 func (c *client) Ping() error {
 	return c.conn.Ping()
@@ -3,18 +3,21 @@ package gremcos
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supplyon/gremcos/interfaces"
 	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
 )
 
-func packedRequest2Request(packedRequest []byte) (request, error) {
+func packedRequest2Request(packedRequest []byte) (Request, error) {
 
 	// the actual request is prepended by the mimetype and its length
 	lenMimeType := len(MimeType)
@@ -25,9 +28,9 @@ func packedRequest2Request(packedRequest []byte) (request, error) {
 
 	// now we have only the bytes of the request
 	// --> unmarshal it
-	result := request{}
+	result := Request{}
 	if err := json.Unmarshal(requestData, &result); err != nil {
-		return request{}, err
+		return Request{}, err
 	}
 	return result, nil
 }
@@ -74,6 +77,48 @@ func TestExecuteAsyncRequest(t *testing.T) {
 	wg.Wait()
 }
 
+func TestExecuteAsyncRequestChunkOrder(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer)
+	mockedDialer.EXPECT().IsConnected().Return(true)
+	responseChannel := make(chan interfaces.AsyncResponse)
+	numChunks := 50
+
+	err := client.ExecuteAsync("g.V().order().by('name')", responseChannel)
+	require.NoError(t, err)
+	requestToSend := <-client.requests
+	req, err := packedRequest2Request(requestToSend)
+	require.NoError(t, err)
+
+	// WHEN
+	// the server sends multiple partial responses followed by the final one
+	go func() {
+		for i := 0; i < numChunks; i++ {
+			code := interfaces.StatusPartialContent
+			if i == numChunks-1 {
+				code = interfaces.StatusSuccess
+			}
+			response := interfaces.Response{RequestID: req.RequestID, Status: interfaces.Status{Code: code, Message: fmt.Sprintf("%d", i)}}
+			packet, err := json.Marshal(response)
+			require.NoError(t, err)
+			require.NoError(t, client.handleResponse(packet))
+		}
+	}()
+
+	// THEN
+	received := 0
+	for response := range responseChannel {
+		assert.Empty(t, response.ErrorMessage)
+		assert.Equal(t, received, response.ChunkIndex)
+		assert.Equal(t, fmt.Sprintf("%d", received), response.Response.Status.Message)
+		received++
+	}
+	assert.Equal(t, numChunks, received)
+}
+
 func TestExecuteRequest(t *testing.T) {
 	// GIVEN
 	mockCtrl := gomock.NewController(t)
@@ -125,6 +170,200 @@ func TestExecuteRequestFail(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestExecuteWithID(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer)
+	requestID := "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21"
+
+	mockedDialer.EXPECT().IsConnected().Return(true)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, err := client.ExecuteWithID(requestID, "g.V()")
+		require.NoError(t, err)
+		require.Len(t, resp, 1)
+		assert.Equal(t, requestID, resp[0].RequestID)
+	}()
+
+	// WHEN
+	requestToSend := <-client.requests
+	req, err := packedRequest2Request(requestToSend)
+	require.NoError(t, err)
+	response := interfaces.Response{RequestID: req.RequestID, Status: interfaces.Status{Code: interfaces.StatusSuccess}}
+	packet, err := json.Marshal(response)
+	require.NoError(t, err)
+	err = client.handleResponse(packet)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// THEN
+	assert.Equal(t, requestID, req.RequestID, "the outgoing request has to carry the supplied id")
+}
+
+func TestExecuteWithIDFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer)
+	mockedDialer.EXPECT().IsConnected().Return(true).Times(2)
+
+	// WHEN
+	resp, err := client.ExecuteWithID("my-trace-id", "g.V()")
+	errAsync := client.ExecuteAsyncWithID("", "g.V()", make(chan interfaces.AsyncResponse))
+
+	// THEN
+	assert.Empty(t, resp)
+	assert.Error(t, err)
+	assert.Error(t, errAsync)
+	assert.Empty(t, client.requests, "no request must be sent")
+}
+
+func TestExecuteBatch(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer)
+	queries := []string{`g.addV("a")`, `g.addV("b")`, `g.addV("c")`, `g.addV("d")`, `g.addV("e")`}
+
+	mockedDialer.EXPECT().IsConnected().Return(true)
+
+	var resp [][]interfaces.Response
+	var errBatch error
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, errBatch = client.ExecuteBatch(queries)
+	}()
+
+	// catch all requests that should be send over the wire (without answering one of them)
+	requestIDs := make([]string, 0, len(queries))
+	for range queries {
+		requestToSend := <-client.requests
+		req, err := packedRequest2Request(requestToSend)
+		require.NoError(t, err)
+		requestIDs = append(requestIDs, req.RequestID)
+	}
+
+	// now answer the requests in reverse order, the first query spans two chunks
+	partial := interfaces.Response{RequestID: requestIDs[0], Status: interfaces.Status{Code: interfaces.StatusPartialContent}}
+	packet, err := json.Marshal(partial)
+	require.NoError(t, err)
+	require.NoError(t, client.handleResponse(packet))
+	for i := len(requestIDs) - 1; i >= 0; i-- {
+		response := interfaces.Response{RequestID: requestIDs[i], Status: interfaces.Status{Code: interfaces.StatusSuccess}}
+		packet, err := json.Marshal(response)
+		require.NoError(t, err)
+		err = client.handleResponse(packet)
+		require.NoError(t, err)
+	}
+
+	// wait until the execution has been completed
+	wg.Wait()
+
+	// THEN
+	require.NoError(t, errBatch)
+	require.Len(t, resp, len(queries))
+	require.Len(t, resp[0], 2, "the responses of a query that spans multiple chunks have to be kept together")
+	for i, queryResponses := range resp {
+		for _, response := range queryResponses {
+			assert.Equal(t, requestIDs[i], response.RequestID)
+		}
+	}
+	for _, queryResponses := range resp[1:] {
+		assert.Len(t, queryResponses, 1)
+	}
+}
+
+func TestExecuteBatchFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer)
+
+	mockedDialer.EXPECT().IsConnected().Return(false)
+
+	// WHEN
+	resp, err := client.ExecuteBatch([]string{"g.V()"})
+
+	// THEN
+	assert.Empty(t, resp)
+	assert.Error(t, err)
+}
+
+func TestExecuteRequestQueryTimeout(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	queryTimeout := time.Millisecond * 50
+	client := newClient(mockedDialer, QueryTimeout(queryTimeout))
+
+	mockedDialer.EXPECT().IsConnected().Return(true)
+	// the connection is closed after the timeout since it can't be reused any more
+	mockedDialer.EXPECT().Close().Return(nil)
+
+	// WHEN
+	// the peer never answers
+	start := time.Now()
+	resp, err := client.Execute("g.V()")
+
+	// THEN
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.True(t, time.Since(start) >= queryTimeout)
+	timeoutErr, ok := errors.Cause(err).(QueryTimeoutError)
+	require.True(t, ok, "expected a QueryTimeoutError but got %T", errors.Cause(err))
+	assert.Equal(t, queryTimeout, timeoutErr.Timeout)
+	assert.NotEmpty(t, timeoutErr.RequestID)
+	assert.Error(t, client.LastError())
+}
+
+func TestExecuteAsyncRequestQueryTimeout(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
+	client := newClient(mockedDialer, QueryTimeout(time.Millisecond*50))
+
+	mockedDialer.EXPECT().IsConnected().Return(true)
+	mockedDialer.EXPECT().Close().Return(nil)
+	responseChannel := make(chan interfaces.AsyncResponse)
+
+	// WHEN
+	err := client.ExecuteAsync("g.V()", responseChannel)
+	require.NoError(t, err)
+	requestToSend := <-client.requests
+	req, err := packedRequest2Request(requestToSend)
+	require.NoError(t, err)
+
+	// send one partial response but never the final one
+	partialResponse := interfaces.Response{RequestID: req.RequestID, Status: interfaces.Status{Code: interfaces.StatusPartialContent}}
+	packet, err := json.Marshal(partialResponse)
+	require.NoError(t, err)
+	err = client.handleResponse(packet)
+	require.NoError(t, err)
+
+	// THEN
+	responses := make([]interfaces.AsyncResponse, 0)
+	for response := range responseChannel {
+		responses = append(responses, response)
+	}
+	require.NotEmpty(t, responses)
+	last := responses[len(responses)-1]
+	assert.Equal(t, req.RequestID, last.Response.RequestID)
+	assert.Contains(t, last.ErrorMessage, "query timeout")
+	assert.Error(t, client.LastError())
+}
+
 func TestValidateCredentials(t *testing.T) {
 	assert.Error(t, validateCredentials("", ""))
 	assert.Error(t, validateCredentials("Hans", ""))
@@ -473,3 +712,63 @@ func TestAuthenticate_Fail(t *testing.T) {
 	err = client.authenticate("reqID")
 	assert.Error(t, err)
 }
+
+func writeScriptFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "gremcos-*.groovy")
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	return file.Name()
+}
+
+func TestReadScriptFile(t *testing.T) {
+	// GIVEN
+	crlfFile := writeScriptFile(t, "g.addV(\"user\")\r\n  .property(\"name\",\"hans\")\r\n")
+	defer os.Remove(crlfFile)
+	bomFile := writeScriptFile(t, "\ufeff// create the user\ng.V().has(\"url\",\"http://host\")  \n")
+	defer os.Remove(bomFile)
+
+	// WHEN
+	crlfScript, errCRLF := readScriptFile(crlfFile, false)
+	bomScript, errBOM := readScriptFile(bomFile, false)
+
+	// THEN
+	require.NoError(t, errCRLF)
+	assert.Equal(t, "g.addV(\"user\")\n  .property(\"name\",\"hans\")\n", crlfScript)
+	require.NoError(t, errBOM)
+	assert.Equal(t, "// create the user\ng.V().has(\"url\",\"http://host\")  \n", bomScript, "comments and whitespace are kept per default")
+}
+
+func TestReadScriptFileStripComments(t *testing.T) {
+	// GIVEN
+	bomFile := writeScriptFile(t, "\ufeff// create the user\r\ng.V().has(\"url\",\"http://host\")  \r\n")
+	defer os.Remove(bomFile)
+
+	// WHEN
+	script, err := readScriptFile(bomFile, true)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "g.V().has(\"url\",\"http://host\")", script)
+}
+
+func TestReadScriptFileFail(t *testing.T) {
+	// GIVEN
+	emptyFile := writeScriptFile(t, "\ufeff\r\n  \r\n")
+	defer os.Remove(emptyFile)
+	commentFile := writeScriptFile(t, "\ufeff\r\n  // nothing to do\r\n")
+	defer os.Remove(commentFile)
+
+	// WHEN
+	_, errEmpty := readScriptFile(emptyFile, false)
+	_, errComment := readScriptFile(commentFile, true)
+	_, errMissing := readScriptFile(emptyFile+".missing", false)
+
+	// THEN
+	require.Error(t, errEmpty)
+	assert.Contains(t, errEmpty.Error(), "is empty")
+	require.Error(t, errComment)
+	assert.Contains(t, errComment.Error(), "is empty")
+	assert.Error(t, errMissing)
+}
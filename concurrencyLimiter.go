@@ -0,0 +1,78 @@
+package gremcos
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrTooManyConcurrentRequests is returned (without contacting the CosmosDB) in case the maximum number of concurrent
+// requests is reached and ConcurrencyLimitFail is used, see WithMaxConcurrentRequests.
+var ErrTooManyConcurrentRequests = errors.New("Maximum number of concurrent requests reached")
+
+// ConcurrencyLimitMode specifies how requests exceeding the limit of WithMaxConcurrentRequests are handled
+type ConcurrencyLimitMode int
+
+const (
+	// ConcurrencyLimitBlock means the request waits until one of the in-flight requests is completed
+	ConcurrencyLimitBlock ConcurrencyLimitMode = iota
+	// ConcurrencyLimitFail means the request fails immediately with ErrTooManyConcurrentRequests
+	ConcurrencyLimitFail
+)
+
+func (m ConcurrencyLimitMode) String() string {
+	switch m {
+	case ConcurrencyLimitBlock:
+		return "block"
+	case ConcurrencyLimitFail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// concurrencyLimiter bounds the number of requests that are in-flight at the same time.
+// A nil concurrencyLimiter is disabled and allows any number of requests.
+type concurrencyLimiter struct {
+	maxRequests int
+	mode        ConcurrencyLimitMode
+	// slots contains one element per in-flight request
+	slots chan struct{}
+}
+
+func newConcurrencyLimiter(maxRequests int, mode ConcurrencyLimitMode) *concurrencyLimiter {
+	limiter := &concurrencyLimiter{
+		maxRequests: maxRequests,
+		mode:        mode,
+	}
+	if maxRequests > 0 {
+		limiter.slots = make(chan struct{}, maxRequests)
+	}
+	return limiter
+}
+
+// acquire occupies a slot for a request, release has to be called as soon as the request is completed.
+// Depending on the mode acquire blocks until a slot is free or returns ErrTooManyConcurrentRequests.
+func (l *concurrencyLimiter) acquire() error {
+	if l == nil {
+		return nil
+	}
+
+	if l.mode == ConcurrencyLimitBlock {
+		l.slots <- struct{}{}
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+		return ErrTooManyConcurrentRequests
+	}
+}
+
+// release frees the slot occupied by acquire
+func (l *concurrencyLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
@@ -0,0 +1,104 @@
+package gremcos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+func TestMaxConcurrentRequestsFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	maxRequests := 2
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithMaxConcurrentRequests(maxRequests, ConcurrencyLimitFail))
+	// the streams are kept open until they are closed by the test
+	var forwardChannels []chan interfaces.AsyncResponse
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.V()", gomock.Any()).DoAndReturn(func(query string, forwardChannel chan interfaces.AsyncResponse) error {
+		forwardChannels = append(forwardChannels, forwardChannel)
+		return nil
+	}).Times(maxRequests + 1)
+
+	// WHEN
+	responseChannels := make([]chan interfaces.AsyncResponse, 0, maxRequests+1)
+	errs := make([]error, 0, maxRequests+1)
+	for i := 0; i < maxRequests+1; i++ {
+		responseChannel := make(chan interfaces.AsyncResponse)
+		responseChannels = append(responseChannels, responseChannel)
+		errs = append(errs, cosmos.ExecuteAsync("g.V()", responseChannel))
+	}
+	_, errSync := cosmos.Execute("g.V()")
+	// completing one stream frees a slot
+	close(forwardChannels[0])
+	for range responseChannels[0] {
+	}
+	errAfterCompletion := cosmos.ExecuteAsync("g.V()", make(chan interfaces.AsyncResponse))
+
+	// THEN
+	for i := 0; i < maxRequests; i++ {
+		assert.NoError(t, errs[i])
+	}
+	assert.Equal(t, ErrTooManyConcurrentRequests, errs[maxRequests])
+	assert.Equal(t, ErrTooManyConcurrentRequests, errSync, "the limit applies to sync requests as well")
+	assert.NoError(t, errAfterCompletion)
+}
+
+func TestMaxConcurrentRequestsBlock(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithMaxConcurrentRequests(1, ConcurrencyLimitBlock))
+	forwardChannels := make(chan chan interfaces.AsyncResponse, 2)
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.V()", gomock.Any()).DoAndReturn(func(query string, forwardChannel chan interfaces.AsyncResponse) error {
+		forwardChannels <- forwardChannel
+		return nil
+	}).Times(2)
+	firstResponses := make(chan interfaces.AsyncResponse)
+	require.NoError(t, cosmos.ExecuteAsync("g.V()", firstResponses))
+
+	// WHEN
+	blockedDone := make(chan error)
+	go func() {
+		blockedDone <- cosmos.ExecuteAsync("g.V()", make(chan interfaces.AsyncResponse))
+	}()
+	var blockedWhileInFlight bool
+	select {
+	case <-blockedDone:
+	case <-time.After(50 * time.Millisecond):
+		blockedWhileInFlight = true
+	}
+	close(<-forwardChannels)
+	for range firstResponses {
+	}
+	var errBlocked error
+	select {
+	case errBlocked = <-blockedDone:
+	case <-time.After(time.Second):
+		require.Fail(t, "the blocked request was not executed after the slot was freed")
+	}
+
+	// THEN
+	assert.True(t, blockedWhileInFlight, "the request has to wait until the in-flight request is completed")
+	assert.NoError(t, errBlocked)
+}
+
+func TestNewWithInvalidMaxConcurrentRequests(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+
+	// WHEN
+	cosmosInvalidMax, errMax := New("ws://host", WithMaxConcurrentRequests(0, ConcurrencyLimitBlock), withMetrics(metrics))
+	cosmosInvalidMode, errMode := New("ws://host", WithMaxConcurrentRequests(3, ConcurrencyLimitMode(42)), withMetrics(metrics))
+
+	// THEN
+	require.Error(t, errMax)
+	assert.Nil(t, cosmosInvalidMax)
+	require.Error(t, errMode)
+	assert.Nil(t, cosmosInvalidMode)
+}
@@ -1,9 +1,11 @@
 package gremcos
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -44,6 +46,14 @@ type websocket struct {
 	readBufSize  int
 	writeBufSize int
 
+	// tlsConfig is the tls configuration used for wss connections.
+	// If nil the default configuration is used.
+	tlsConfig *tls.Config
+
+	// proxyURL is the url of the (http or socks5) proxy the connection is established through.
+	// If nil the connection is established directly.
+	proxyURL *url.URL
+
 	mux sync.RWMutex
 
 	// wsDialerFactory is a factory that creates
@@ -93,7 +103,7 @@ func NewWebsocket(host string, options ...optionWebsocket) (interfaces.Dialer, e
 func (ws *websocket) Connect() error {
 
 	// create the function that shall be used for dialing
-	dial := ws.wsDialerFactory(ws.writeBufSize, ws.readBufSize, ws.timeout)
+	dial := ws.wsDialerFactory(ws.writeBufSize, ws.readBufSize, ws.timeout, ws.tlsConfig, ws.proxyURL)
 
 	conn, response, err := dial(ws.host, http.Header{})
 	if err != nil {
@@ -2,9 +2,11 @@ package gremcos
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -118,6 +120,58 @@ func TestConnectReconnect(t *testing.T) {
 	assert.False(t, websocket.IsConnected())
 }
 
+func TestConnectWithTLSConfig(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedWebsocketConnection := mock_interfaces.NewMockWebsocketConnection(mockCtrl)
+	mockedDialerFactory := newMockedDialerFactory(mockedWebsocketConnection, false)
+	tlsConfig := &tls.Config{ServerName: "myhost"}
+	var usedTLSConfig *tls.Config
+	capturingDialerFactory := func(wBufSize, rBifSize int, timeout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer {
+		usedTLSConfig = tlsConfig
+		return mockedDialerFactory(wBufSize, rBifSize, timeout, tlsConfig, proxyURL)
+	}
+
+	websocket, err := NewWebsocket("wss://localhost", websocketDialerFactoryFun(capturingDialerFactory), SetTLSConfig(tlsConfig))
+	require.NoError(t, err)
+	require.NotNil(t, websocket)
+
+	// WHEN
+	mockedWebsocketConnection.EXPECT().SetPongHandler(gomock.Any())
+	err = websocket.Connect()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, tlsConfig, usedTLSConfig)
+}
+
+func TestConnectWithProxy(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedWebsocketConnection := mock_interfaces.NewMockWebsocketConnection(mockCtrl)
+	mockedDialerFactory := newMockedDialerFactory(mockedWebsocketConnection, false)
+	proxyURL := &url.URL{Scheme: "http", Host: "proxy.corp:3128"}
+	var usedProxyURL *url.URL
+	capturingDialerFactory := func(wBufSize, rBifSize int, timeout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer {
+		usedProxyURL = proxyURL
+		return mockedDialerFactory(wBufSize, rBifSize, timeout, tlsConfig, proxyURL)
+	}
+
+	websocket, err := NewWebsocket("wss://localhost", websocketDialerFactoryFun(capturingDialerFactory), SetProxy(proxyURL))
+	require.NoError(t, err)
+	require.NotNil(t, websocket)
+
+	// WHEN
+	mockedWebsocketConnection.EXPECT().SetPongHandler(gomock.Any())
+	err = websocket.Connect()
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, proxyURL, usedProxyURL)
+}
+
 func TestConnectClose(t *testing.T) {
 	// GIVEN
 	mockCtrl := gomock.NewController(t)
@@ -324,12 +378,12 @@ func newMockedDialerFactory(websocketConnection interfaces.WebsocketConnection,
 
 	// if needed return a websocket that can't create a connection successfully
 	if fail {
-		return func(wBufSize, rBifSize int, timeout time.Duration) websocketDialer {
+		return func(wBufSize, rBifSize int, timeout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer {
 			return websocketFuncError
 		}
 	}
 
-	return func(wBufSize, rBifSize int, timeout time.Duration) websocketDialer {
+	return func(wBufSize, rBifSize int, timeout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer {
 		return websocketFuncSuccess
 	}
 }
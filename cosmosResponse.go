@@ -1,7 +1,9 @@
 package gremcos
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -66,7 +68,7 @@ func extractFirstError(responses []interfaces.Response) error {
 			// if we can't parse/ interpret the attribute map then we return the full/ unparsed error information
 			return fmt.Errorf("Failed parsing attributes of response: '%s'. Unparsed error: %d - %s", err.Error(), response.Status.Code, response.Status.Message)
 		}
-		return fmt.Errorf("%d (%d) - %s", responseInfo.statusCode, responseInfo.subStatusCode, responseInfo.statusDescription)
+		return newCosmosError(response, responseInfo)
 
 	}
 
@@ -74,6 +76,69 @@ func extractFirstError(responses []interfaces.Response) error {
 	return nil
 }
 
+// CosmosError is returned in case the CosmosDB answered with a CosmosDB specific error (status code 500 along with the
+// CosmosDB headers). Use errors.As to get access to the details of the error.
+type CosmosError struct {
+	// Code is the CosmosDB specific status code (x-ms-status-code), e.g. 429 in case the request rate is too large
+	Code int
+	// SubStatus is the CosmosDB specific sub status code (x-ms-substatus-code), e.g. 3200
+	SubStatus int
+	// Message is the error message sent by the CosmosDB, for json error bodies only the contained message is kept
+	Message string
+	// RetryAfterMs is the time in milliseconds until the request should be retried (x-ms-retry-after-ms), 0 if not given
+	RetryAfterMs int64
+	// ActivityID is the id of the request on the CosmosDB side (x-ms-activity-id)
+	ActivityID string
+
+	description string
+}
+
+func (e CosmosError) Error() string {
+	if len(e.Message) == 0 {
+		return fmt.Sprintf("%d (%d) - %s", e.Code, e.SubStatus, e.description)
+	}
+	return fmt.Sprintf("%d (%d) - %s: %s", e.Code, e.SubStatus, e.description, e.Message)
+}
+
+func newCosmosError(response interfaces.Response, responseInfo responseInformation) CosmosError {
+	return CosmosError{
+		Code:         responseInfo.statusCode,
+		SubStatus:    responseInfo.subStatusCode,
+		Message:      parseCosmosErrorMessage(response.Status.Message),
+		RetryAfterMs: int64(responseInfo.retryAfter / time.Millisecond),
+		ActivityID:   responseInfo.activityID,
+		description:  responseInfo.statusDescription,
+	}
+}
+
+// parseCosmosErrorMessage extracts the message of the json error body that is usually embedded into the status message
+// of the CosmosDB (e.g. '... Message: {"code":"TooManyRequests","message":"Request rate is large"} ...').
+// In case there is no such body the trimmed status message is returned.
+func parseCosmosErrorMessage(statusMessage string) string {
+	statusMessage = strings.TrimSpace(statusMessage)
+	start := strings.Index(statusMessage, "{")
+	end := strings.LastIndex(statusMessage, "}")
+	if start < 0 || end < start {
+		return statusMessage
+	}
+
+	body := struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"Errors"`
+	}{}
+	if err := json.Unmarshal([]byte(statusMessage[start:end+1]), &body); err != nil {
+		return statusMessage
+	}
+
+	if len(body.Message) > 0 {
+		return strings.TrimSpace(body.Message)
+	}
+	if len(body.Errors) > 0 {
+		return strings.Join(body.Errors, "; ")
+	}
+	return statusMessage
+}
+
 // parseAttributeMap parses the given attribute map assuming that it contains CosmosDB specific headers.
 func parseAttributeMap(attributes map[string]interface{}) (responseInformation, error) {
 	responseInfo := responseInformation{}
@@ -140,6 +205,37 @@ func statusCodeToDescription(code int) string {
 	return desc
 }
 
+// RequestCharge returns the request charge (RU) of the given response as provided by the CosmosDB via the
+// x-ms-total-request-charge attribute. For requests that are answered with multiple responses (chunks) the value
+// contains the accumulated charge of all responses up to and including the given one.
+// False is returned in case the response does not contain the request charge (e.g. for non CosmosDB backends).
+func RequestCharge(resp interfaces.Response) (float64, bool) {
+	value, ok := resp.Status.Attributes[string(headerRequestChargeTotal)]
+	if !ok {
+		return 0, false
+	}
+
+	charge, err := cast.ToFloat64E(value)
+	if err != nil {
+		return 0, false
+	}
+	return charge, true
+}
+
+// TotalRequestCharge returns the overall request charge (RU) of a request that was answered with the given responses.
+// Since the CosmosDB already accumulates the charge over all responses (chunks), the largest charge is returned.
+// Responses without request charge are ignored.
+func TotalRequestCharge(responses []interfaces.Response) float64 {
+	var total float64
+	for _, response := range responses {
+		charge, ok := RequestCharge(response)
+		if ok && charge > total {
+			total = charge
+		}
+	}
+	return total
+}
+
 type responseInformation struct {
 	statusCode         int
 	subStatusCode      int
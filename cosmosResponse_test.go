@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supplyon/gremcos/interfaces"
@@ -35,6 +36,50 @@ func TestExtractFirstError(t *testing.T) {
 	assert.Contains(t, err.Error(), "429")
 }
 
+func TestExtractFirstErrorCosmosError(t *testing.T) {
+	// GIVEN
+	tooManyRequests := interfaces.Response{
+		Status: interfaces.Status{
+			Code:    interfaces.StatusServerError,
+			Message: `ActivityId : 7f5e, Host : 10.0.0.1 Message: {"code":"TooManyRequests","message":"Request rate is large."}`,
+			Attributes: map[string]interface{}{
+				"x-ms-status-code":    429,
+				"x-ms-substatus-code": 3200,
+				"x-ms-retry-after-ms": "00:00:02.345",
+				"x-ms-activity-id":    "7f5e",
+			},
+		},
+	}
+	plainMessage := interfaces.Response{
+		Status: interfaces.Status{
+			Code:       interfaces.StatusServerError,
+			Message:    " Resource not found ",
+			Attributes: map[string]interface{}{"x-ms-status-code": 404},
+		},
+	}
+
+	// WHEN
+	err := extractFirstError([]interfaces.Response{tooManyRequests})
+	errPlain := extractFirstError([]interfaces.Response{plainMessage})
+
+	// THEN
+	var cosmosErr CosmosError
+	require.True(t, errors.As(err, &cosmosErr))
+	assert.Equal(t, 429, cosmosErr.Code)
+	assert.Equal(t, 3200, cosmosErr.SubStatus)
+	assert.Equal(t, "Request rate is large.", cosmosErr.Message)
+	assert.Equal(t, int64(2345), cosmosErr.RetryAfterMs)
+	assert.Equal(t, "7f5e", cosmosErr.ActivityID)
+	assert.Contains(t, err.Error(), "429 (3200)")
+	assert.Contains(t, err.Error(), "Request rate is large.")
+
+	var cosmosErrPlain CosmosError
+	require.True(t, errors.As(errPlain, &cosmosErrPlain))
+	assert.Equal(t, 404, cosmosErrPlain.Code)
+	assert.Equal(t, "Resource not found", cosmosErrPlain.Message)
+	assert.Equal(t, int64(0), cosmosErrPlain.RetryAfterMs)
+}
+
 func TestExtractFirstErrorNoError(t *testing.T) {
 	// GIVEN
 	noError := interfaces.Response{
@@ -197,3 +242,42 @@ func TestStatusCodeToDescription(t *testing.T) {
 	// THEN
 	assert.Contains(t, desc, "unknown")
 }
+
+func TestRequestCharge(t *testing.T) {
+	// GIVEN
+	withCharge := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-total-request-charge": 12.5}}}
+	withChargeAsString := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-total-request-charge": "3.25"}}}
+	withoutCharge := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-status-code": 200}}}
+	withInvalidCharge := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-total-request-charge": "abc"}}}
+
+	// WHEN
+	charge, ok := RequestCharge(withCharge)
+	chargeAsString, okAsString := RequestCharge(withChargeAsString)
+	_, okWithout := RequestCharge(withoutCharge)
+	_, okInvalid := RequestCharge(withInvalidCharge)
+	_, okEmpty := RequestCharge(interfaces.Response{})
+
+	// THEN
+	assert.True(t, ok)
+	assert.Equal(t, 12.5, charge)
+	assert.True(t, okAsString)
+	assert.Equal(t, 3.25, chargeAsString)
+	assert.False(t, okWithout)
+	assert.False(t, okInvalid)
+	assert.False(t, okEmpty)
+}
+
+func TestTotalRequestCharge(t *testing.T) {
+	// GIVEN
+	chunk1 := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-total-request-charge": 10.0}}}
+	chunk2 := interfaces.Response{Status: interfaces.Status{Attributes: map[string]interface{}{"x-ms-total-request-charge": 22.5}}}
+	withoutCharge := interfaces.Response{}
+
+	// WHEN
+	total := TotalRequestCharge([]interfaces.Response{chunk1, chunk2, withoutCharge})
+	totalNone := TotalRequestCharge([]interfaces.Response{withoutCharge})
+
+	// THEN
+	assert.Equal(t, 22.5, total)
+	assert.Equal(t, float64(0), totalNone)
+}
@@ -1,12 +1,20 @@
 package gremcos
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -108,6 +116,7 @@ func TestNew(t *testing.T) {
 	cImpl := toCosmosImpl(t, cosmos)
 	assert.Equal(t, idleTimeout, cImpl.connectionIdleTimeout)
 	assert.Equal(t, maxActiveConnections, cImpl.numMaxActiveConnections)
+	assert.True(t, cImpl.autoReconnect)
 	require.NotNil(t, cImpl.credentialProvider)
 
 	uname, err := cImpl.credentialProvider.Username()
@@ -118,6 +127,431 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, password, pwd)
 }
 
+func TestNewWithConnMaxLifetime(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+
+	// WHEN
+	cosmos, err := New("ws://host", WithConnMaxLifetime(time.Hour), withMetrics(metrics))
+
+	// THEN
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	assert.Equal(t, time.Hour, cImpl.connMaxLifetime)
+	pool, ok := cImpl.pool.(*pool)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, pool.maxLifetime)
+}
+
+// countingDialerMock is a dialerMock that counts the established connections
+type countingDialerMock struct {
+	dialerMock
+	connects *int32
+}
+
+func (d *countingDialerMock) Connect() error {
+	atomic.AddInt32(d.connects, 1)
+	return nil
+}
+
+func TestNewWithMinActiveConnections(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	metricMocks.connectionsCreatedTotal.EXPECT().Inc().Times(3)
+	metricMocks.connectionsIdle.EXPECT().Set(gomock.Any()).AnyTimes()
+	var connects int32
+	countingWebsocketGenerator := func(host string, options ...optionWebsocket) (interfaces.Dialer, error) {
+		return &countingDialerMock{connects: &connects}, nil
+	}
+
+	// WHEN
+	cosmos, err := New("ws://host",
+		NumMinActiveConnections(3),
+		NumMaxActiveConnections(5),
+		withMetrics(metrics),
+		wsGenerator(countingWebsocketGenerator),
+	)
+
+	// THEN
+	require.NoError(t, err)
+	defer cosmos.Stop()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&connects))
+	stats := cosmos.Stats()
+	assert.Equal(t, 3, stats.Active)
+	assert.Equal(t, 3, stats.Idle)
+}
+
+func TestNewWithMinActiveConnectionsFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	metricMocks.connectionsIdle.EXPECT().Set(gomock.Any()).AnyTimes()
+	failingWebsocketGenerator := func(host string, options ...optionWebsocket) (interfaces.Dialer, error) {
+		return nil, fmt.Errorf("dial failed")
+	}
+
+	// WHEN
+	cosmosDialFails, errDialFails := New("ws://host", NumMinActiveConnections(2), withMetrics(metrics), wsGenerator(failingWebsocketGenerator))
+	cosmosTooMany, errTooMany := New("ws://host", NumMinActiveConnections(3), NumMaxActiveConnections(2), withMetrics(metrics), wsGenerator(websocketGenerator))
+
+	// THEN
+	assert.Error(t, errDialFails)
+	assert.Nil(t, cosmosDialFails)
+	assert.Error(t, errTooMany)
+	assert.Nil(t, cosmosTooMany)
+}
+
+func TestNewWithQueryTimeout(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+	queryTimeout := time.Second * 3
+
+	cosmos, err := New("ws://host",
+		WithQueryTimeout(queryTimeout),
+		withMetrics(metrics),
+		wsGenerator(websocketGenerator),
+	)
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+
+	// WHEN
+	queryExecutor, err := cImpl.dial()
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, queryTimeout, cImpl.queryTimeout)
+	client := queryExecutor.(*client)
+	assert.Equal(t, queryTimeout, client.queryTimeout)
+}
+
+// silentDialerMock is a connection whose peer never answers, it counts the written messages
+type silentDialerMock struct {
+	writes *int32
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (d *silentDialerMock) Connect() error { return nil }
+func (d *silentDialerMock) IsConnected() bool {
+	select {
+	case <-d.closed:
+		return false
+	default:
+		return true
+	}
+}
+func (d *silentDialerMock) Write([]byte) error {
+	atomic.AddInt32(d.writes, 1)
+	return nil
+}
+func (d *silentDialerMock) Read() (int, []byte, error) {
+	<-d.closed
+	return -1, nil, fmt.Errorf("closed")
+}
+func (d *silentDialerMock) Close() error {
+	d.once.Do(func() { close(d.closed) })
+	return nil
+}
+func (d *silentDialerMock) Ping() error { return nil }
+
+func TestQueryTimeoutIsNotRetried(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	var writes int32
+	silentWebsocketGenerator := func(host string, options ...optionWebsocket) (interfaces.Dialer, error) {
+		return &silentDialerMock{writes: &writes, closed: make(chan struct{})}, nil
+	}
+	queryTimeout := time.Millisecond * 50
+	// auto reconnect is enabled per default
+	cosmos, err := New("ws://host", WithQueryTimeout(queryTimeout), withMetrics(metrics), wsGenerator(silentWebsocketGenerator))
+	require.NoError(t, err)
+	defer cosmos.Stop()
+
+	// WHEN
+	start := time.Now()
+	_, err = cosmos.Execute("g.addV('user')")
+	elapsed := time.Since(start)
+
+	// THEN
+	require.Error(t, err)
+	_, isTimeout := errors.Cause(err).(QueryTimeoutError)
+	assert.True(t, isTimeout, "expected a QueryTimeoutError but got %v", err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&writes), "the timed out query must be sent exactly once")
+	assert.True(t, elapsed < 2*queryTimeout, "the caller must not wait for a second attempt")
+}
+
+func TestExecuteWithContextCancelled(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, _ := newCosmosWithMockedPool(t, mockCtrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// WHEN
+	// no query is expected to be sent
+	_, err := cosmos.ExecuteWithContext(ctx, "g.V()")
+	errAsync := cosmos.ExecuteAsyncWithContext(ctx, "g.V()", make(chan interfaces.AsyncResponse))
+
+	// THEN
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, context.Canceled, errAsync)
+}
+
+func TestExecuteWithContextDeadline(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	release := make(chan struct{})
+	completed := make(chan struct{})
+	mockedQueryExecutor.EXPECT().Execute("g.V()").DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		defer close(completed)
+		<-release
+		return []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// WHEN
+	_, err := cosmos.ExecuteWithContext(ctx, "g.V()")
+	close(release)
+	<-completed
+
+	// THEN
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestExecuteAsyncWithContextDeadline(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	forwardChannels := make(chan chan interfaces.AsyncResponse, 1)
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.V()", gomock.Any()).DoAndReturn(func(query string, forwardChannel chan interfaces.AsyncResponse) error {
+		forwardChannels <- forwardChannel
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	responseChannel := make(chan interfaces.AsyncResponse)
+
+	// WHEN
+	err := cosmos.ExecuteAsyncWithContext(ctx, "g.V()", responseChannel)
+	require.NoError(t, err)
+	forwardChannel := <-forwardChannels
+	forwardChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "1", Status: interfaces.Status{Code: interfaces.StatusPartialContent}}}
+	firstChunk := <-responseChannel
+	cancel()
+	var remaining []interfaces.AsyncResponse
+	for response := range responseChannel {
+		remaining = append(remaining, response)
+	}
+	// the responses received after the cancellation are dropped
+	forwardChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "1", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	close(forwardChannel)
+
+	// THEN
+	assert.Equal(t, "1", firstChunk.Response.RequestID)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, context.Canceled.Error(), remaining[0].ErrorMessage)
+}
+
+func TestNewWithDialTimeout(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+	dialTimeout := time.Millisecond * 200
+
+	// a server that accepts tcp connections but never answers the websocket handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	cosmos, err := New(fmt.Sprintf("ws://%s/gremlin", listener.Addr().String()),
+		WithDialTimeout(dialTimeout),
+		withMetrics(metrics),
+	)
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+
+	// WHEN
+	start := time.Now()
+	queryExecutor, errFirst := cImpl.dial()
+	// reconnect attempts use the same timeout
+	_, errSecond := cImpl.dial()
+	elapsed := time.Since(start)
+
+	// THEN
+	assert.Error(t, errFirst)
+	assert.Error(t, errSecond)
+	assert.Nil(t, queryExecutor)
+	assert.Equal(t, dialTimeout, cImpl.dialTimeout)
+	assert.True(t, elapsed < time.Second*2, "dialing took %v, expected to fail after the dial timeout of %v", elapsed, dialTimeout)
+	assert.True(t, elapsed >= dialTimeout, "dialing failed after %v, before the dial timeout of %v", elapsed, dialTimeout)
+}
+
+func TestNewWithKeepAlive(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	metricMocks.connectionsIdle.EXPECT().Set(gomock.Any()).AnyTimes()
+
+	// WHEN
+	cosmos, err := New("ws://host", WithKeepAlive(time.Minute), withMetrics(metrics), wsGenerator(websocketGenerator))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	pool, ok := cImpl.pool.(*pool)
+	require.True(t, ok)
+	keepAliveRunning := pool.keepAliveQuit != nil
+	errStop := cosmos.Stop()
+
+	// THEN
+	assert.NoError(t, errStop)
+	assert.Equal(t, time.Minute, cImpl.keepAliveInterval)
+	assert.True(t, keepAliveRunning)
+	assert.Nil(t, pool.keepAliveQuit, "the keepalive has to be stopped on Stop")
+}
+
+func TestNewWithTLSConfig(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+	tlsConfig := &tls.Config{ServerName: "myhost"}
+	ws := &websocket{}
+	capturingGenerator := func(host string, options ...optionWebsocket) (interfaces.Dialer, error) {
+		for _, opt := range options {
+			opt(ws)
+		}
+		return nil, fmt.Errorf("not connected")
+	}
+
+	// WHEN
+	cosmos, err := New("wss://host", WithTLSConfig(tlsConfig), wsGenerator(capturingGenerator), withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	_, errDial := cImpl.dial()
+
+	// THEN
+	assert.Error(t, errDial)
+	assert.Equal(t, tlsConfig, cImpl.tlsConfig)
+	assert.Equal(t, tlsConfig, ws.tlsConfig)
+}
+
+func TestNewWithProxy(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+	ws := &websocket{}
+	capturingGenerator := func(host string, options ...optionWebsocket) (interfaces.Dialer, error) {
+		for _, opt := range options {
+			opt(ws)
+		}
+		return nil, fmt.Errorf("not connected")
+	}
+
+	// WHEN
+	cosmos, err := New("wss://host", WithProxy("http://proxy.corp:3128"), wsGenerator(capturingGenerator), withMetrics(metrics))
+	require.NoError(t, err)
+	_, errDial := toCosmosImpl(t, cosmos).dial()
+	cosmosInvalidScheme, errInvalidScheme := New("wss://host", WithProxy("ftp://proxy.corp"), withMetrics(metrics))
+	cosmosNoHost, errNoHost := New("wss://host", WithProxy("http://"), withMetrics(metrics))
+
+	// THEN
+	assert.Error(t, errDial)
+	require.NotNil(t, ws.proxyURL)
+	assert.Equal(t, "http://proxy.corp:3128", ws.proxyURL.String())
+	assert.Error(t, errInvalidScheme)
+	assert.Nil(t, cosmosInvalidScheme)
+	assert.Error(t, errNoHost)
+	assert.Nil(t, cosmosNoHost)
+}
+
+func TestNewWithHost(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+
+	// WHEN
+	cosmosWSS, errWSS := New("wss://host:443/", withMetrics(metrics))
+	cosmosHTTPS, errHTTPS := New("https://host:443/", withMetrics(metrics))
+	cosmosHTTP, errHTTP := New(" HTTP://host:8182/gremlin", withMetrics(metrics))
+	cosmosNoScheme, errNoScheme := New("host:443", withMetrics(metrics))
+	cosmosInvalidScheme, errInvalidScheme := New("ftp://host", withMetrics(metrics))
+	cosmosNoHost, errNoHost := New("wss://", withMetrics(metrics))
+	cosmosMalformed, errMalformed := New("wss://ho st:abc", withMetrics(metrics))
+
+	// THEN
+	require.NoError(t, errWSS)
+	assert.Equal(t, "wss://host:443/", toCosmosImpl(t, cosmosWSS).host)
+	require.NoError(t, errHTTPS)
+	assert.Equal(t, "wss://host:443/", toCosmosImpl(t, cosmosHTTPS).host)
+	require.NoError(t, errHTTP)
+	assert.Equal(t, "ws://host:8182/gremlin", toCosmosImpl(t, cosmosHTTP).host)
+	assert.Error(t, errNoScheme)
+	assert.Nil(t, cosmosNoScheme)
+	require.Error(t, errInvalidScheme)
+	assert.Contains(t, errInvalidScheme.Error(), "expected scheme 'ws://' or 'wss://'")
+	assert.Nil(t, cosmosInvalidScheme)
+	assert.Error(t, errNoHost)
+	assert.Nil(t, cosmosNoHost)
+	assert.Error(t, errMalformed)
+	assert.Nil(t, cosmosMalformed)
+}
+
+func TestNewWithRootCAsAndInsecureSkipVerify(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, _ := NewMockedMetrics(mockCtrl)
+	rootCAs := x509.NewCertPool()
+	logBuffer := &bytes.Buffer{}
+	logger := zerolog.New(logBuffer)
+
+	// WHEN
+	cosmosRootCAs, errRootCAs := New("wss://host", WithRootCAs(rootCAs), WithLogger(logger), wsGenerator(websocketGenerator), withMetrics(metrics))
+	logRootCAs := logBuffer.String()
+	cosmosSkipVerify, errSkipVerify := New("wss://host", WithInsecureSkipVerify(true), WithRootCAs(rootCAs), WithLogger(logger), wsGenerator(websocketGenerator), withMetrics(metrics))
+
+	// THEN
+	require.NoError(t, errRootCAs)
+	require.NoError(t, errSkipVerify)
+	cImplRootCAs := toCosmosImpl(t, cosmosRootCAs)
+	require.NotNil(t, cImplRootCAs.tlsConfig)
+	assert.Equal(t, rootCAs, cImplRootCAs.tlsConfig.RootCAs)
+	assert.False(t, cImplRootCAs.tlsConfig.InsecureSkipVerify)
+	assert.Empty(t, logRootCAs)
+
+	cImplSkipVerify := toCosmosImpl(t, cosmosSkipVerify)
+	require.NotNil(t, cImplSkipVerify.tlsConfig)
+	assert.Equal(t, rootCAs, cImplSkipVerify.tlsConfig.RootCAs)
+	assert.True(t, cImplSkipVerify.tlsConfig.InsecureSkipVerify)
+	assert.Contains(t, logBuffer.String(), "InsecureSkipVerify")
+}
+
 func TestStop(t *testing.T) {
 	// GIVEN
 	mockCtrl := gomock.NewController(t)
@@ -226,6 +660,7 @@ func TestUpdateMetricsZero(t *testing.T) {
 	metricMocks.requestChargePerQuery.EXPECT().Set(float64(0))
 	metricMocks.requestChargeTotal.EXPECT().Add(float64(0))
 	metricMocks.retryAfterMS.EXPECT().Set(float64(0))
+	metricMocks.requestUnitsTotal.EXPECT().Add(float64(0))
 	updateRequestMetrics(responses, metrics)
 
 	// THEN
@@ -263,6 +698,7 @@ func TestUpdateMetricsFull(t *testing.T) {
 	metricMocks.requestChargePerQuery.EXPECT().Set(float64(11))
 	metricMocks.requestChargeTotal.EXPECT().Add(float64(11))
 	metricMocks.retryAfterMS.EXPECT().Set(float64(33))
+	metricMocks.requestUnitsTotal.EXPECT().Add(float64(11))
 	updateRequestMetrics(responses, metrics)
 
 	// THEN
@@ -343,3 +779,244 @@ func TestWithLogger(t *testing.T) {
 	assert.NotEqual(t, zerolog.Nop(), cImpl.logger)
 	assert.Equal(t, zerolog.DebugLevel, cImpl.logger.GetLevel())
 }
+
+func TestVerticesInBoundingBox(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, err := New("ws://host", withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+	expectedQuery := `g.V().hasLabel("shop").has("lat",between(47.1,47.9)).has("lon",between(8.2,8.9))`
+	mockedQueryExecutor.EXPECT().Execute(expectedQuery).Return([]interfaces.Response{{RequestID: "1", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+
+	// WHEN
+	responses, err := cosmos.VerticesInBoundingBox("shop", "lat", "lon", 47.1, 47.9, 8.2, 8.9)
+	_, errLat := cosmos.VerticesInBoundingBox("shop", "lat", "lon", 47.9, 47.1, 8.2, 8.9)
+	_, errLon := cosmos.VerticesInBoundingBox("shop", "lat", "lon", 47.1, 47.9, 8.9, 8.2)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Len(t, responses, 1)
+	assert.Error(t, errLat)
+	assert.Error(t, errLon)
+}
+
+func TestGroupCount(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	queryCosmos := `g.V().groupCount().by(label)`
+	queryTinkerpop := `g.V().groupCount().by("type")`
+	dataCosmos := `[{"user":2,"device":{"@type":"g:Int64","@value":3}}]`
+	dataTinkerpop := `{"@type":"g:List","@value":[{"@type":"g:Map","@value":["a",{"@type":"g:Int64","@value":5},"b",{"@type":"g:Int32","@value":1}]}]}`
+
+	mockedQueryExecutor.EXPECT().Execute(queryCosmos).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(dataCosmos)}}}, nil)
+	mockedQueryExecutor.EXPECT().Execute(queryTinkerpop).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(dataTinkerpop)}}}, nil)
+
+	// WHEN
+	countsCosmos, errCosmos := cosmos.GroupCount(queryCosmos)
+	countsTinkerpop, errTinkerpop := cosmos.GroupCount(queryTinkerpop)
+
+	// THEN
+	require.NoError(t, errCosmos)
+	assert.Equal(t, map[string]int64{"user": 2, "device": 3}, countsCosmos)
+	require.NoError(t, errTinkerpop)
+	assert.Equal(t, map[string]int64{"a": 5, "b": 1}, countsTinkerpop)
+}
+
+func TestGroupCountFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	queryNoMap := `g.V().count()`
+	queryFail := `g.V().groupCount()`
+
+	mockedQueryExecutor.EXPECT().Execute(queryNoMap).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(`[1,2]`)}}}, nil)
+	mockedQueryExecutor.EXPECT().Execute(queryFail).Return(nil, fmt.Errorf("connection lost"))
+
+	// WHEN
+	countsNoMap, errNoMap := cosmos.GroupCount(queryNoMap)
+	countsFail, errFail := cosmos.GroupCount(queryFail)
+
+	// THEN
+	assert.Error(t, errNoMap)
+	assert.Nil(t, countsNoMap)
+	assert.Error(t, errFail)
+	assert.Nil(t, countsFail)
+}
+
+func TestPageWithTotal(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	responses := [][]interfaces.Response{
+		{{RequestID: "count", Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(`[42]`)}}},
+		{
+			{RequestID: "page", Status: interfaces.Status{Code: interfaces.StatusPartialContent}, Result: interfaces.Result{Data: []byte(`[{"id":"1"}]`)}},
+			{RequestID: "page", Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(`[{"id":"2"}]`)}},
+		},
+	}
+	mockedQueryExecutor.EXPECT().ExecuteBatch([]string{`g.V().hasLabel("user").count()`, `g.V().hasLabel("user").range(20,30)`}).Return(responses, nil)
+
+	// WHEN
+	page, total, err := cosmos.PageWithTotal(` g.V().hasLabel("user");`, 10, 3)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+	assert.Equal(t, responses[1], page)
+}
+
+func TestPageWithTotalFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	invalidCount := [][]interfaces.Response{{{RequestID: "count", Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(`["a"]`)}}}, {}}
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().ExecuteBatch([]string{"g.V().count()", "g.V().range(0,5)"}).Return(nil, fmt.Errorf("connection lost")),
+		mockedQueryExecutor.EXPECT().ExecuteBatch([]string{"g.V().count()", "g.V().range(0,5)"}).Return(invalidCount, nil),
+	)
+
+	// WHEN
+	_, _, errPageSize := cosmos.PageWithTotal("g.V()", 0, 1)
+	_, _, errPageNum := cosmos.PageWithTotal("g.V()", 5, 0)
+	_, _, errFail := cosmos.PageWithTotal("g.V()", 5, 1)
+	_, _, errInvalidCount := cosmos.PageWithTotal("g.V()", 5, 1)
+
+	// THEN
+	assert.Error(t, errPageSize)
+	assert.Error(t, errPageNum)
+	assert.Error(t, errFail)
+	assert.Error(t, errInvalidCount)
+}
+
+func TestBulkAddVertices(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	rows := []map[string]interface{}{
+		{"name": "hans", "age": 21},
+		{"name": "peter"},
+		{},
+	}
+	idResponse := func(id string) []interfaces.Response {
+		return []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(fmt.Sprintf(`["%s"]`, id))}}}
+	}
+	mockedQueryExecutor.EXPECT().Execute(`g.addV("user").property("age",21).property("name","hans").id()`).Return(idResponse("1"), nil)
+	mockedQueryExecutor.EXPECT().Execute(`g.addV("user").property("name","peter").id()`).Return(idResponse("2"), nil)
+	mockedQueryExecutor.EXPECT().Execute(`g.addV("user").id()`).Return(idResponse("3"), nil)
+
+	// WHEN
+	ids, err := cosmos.BulkAddVertices("user", rows, 2)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestBulkAddVerticesFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	rows := []map[string]interface{}{{"name": "hans"}, {"name": "peter"}}
+	mockedQueryExecutor.EXPECT().Execute(`g.addV("user").property("name","hans").id()`).Return(nil, fmt.Errorf("connection lost"))
+
+	// WHEN
+	idsInvalidConcurrency, errInvalidConcurrency := cosmos.BulkAddVertices("user", rows, 0)
+	idsInvalidRow, errInvalidRow := cosmos.BulkAddVertices("user", []map[string]interface{}{{"name": nil}}, 1)
+	ids, err := cosmos.BulkAddVertices("user", rows, 1)
+
+	// THEN
+	assert.Error(t, errInvalidConcurrency)
+	assert.Nil(t, idsInvalidConcurrency)
+	assert.Error(t, errInvalidRow)
+	assert.Nil(t, idsInvalidRow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 0")
+	assert.Equal(t, []string{"", ""}, ids, "no further vertex is added after the first failure")
+}
+
+func TestCosmosStats(t *testing.T) {
+	// GIVEN
+	cImpl := &cosmosImpl{pool: &pool{active: 2, maxActive: 5, idleConnections: []*idleConnection{{}}}}
+
+	// WHEN
+	stats := cImpl.Stats()
+
+	// THEN
+	assert.Equal(t, PoolStats{Active: 3, Idle: 1, InUse: 2, Max: 5}, stats)
+}
+
+// expectAsyncResponses lets the mocked query executor deliver the responses with the given status codes for the given query
+func expectAsyncResponses(mockedQueryExecutor *mock_interfaces.MockQueryExecutor, query string, codes ...int) {
+	mockedQueryExecutor.EXPECT().ExecuteAsync(query, gomock.Any()).DoAndReturn(func(query string, responseChannel chan interfaces.AsyncResponse) error {
+		go func() {
+			for i, code := range codes {
+				response := interfaces.Response{RequestID: "abc", Status: interfaces.Status{Code: code, Message: fmt.Sprintf("%d", i)}}
+				responseChannel <- interfaces.AsyncResponse{Response: response, ChunkIndex: i}
+			}
+			close(responseChannel)
+		}()
+		return nil
+	})
+}
+
+func TestExecuteStream(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	expectAsyncResponses(mockedQueryExecutor, "g.V()", interfaces.StatusPartialContent, interfaces.StatusPartialContent, interfaces.StatusPartialContent, interfaces.StatusSuccess)
+	var chunks []string
+
+	// WHEN
+	err := cosmos.ExecuteStream("g.V()", func(response interfaces.Response) error {
+		chunks = append(chunks, response.Status.Message)
+		return nil
+	})
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, chunks, "the handler has to be called per chunk in order")
+}
+
+func TestExecuteStreamStopsEarly(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	expectAsyncResponses(mockedQueryExecutor, "g.V()", interfaces.StatusPartialContent, interfaces.StatusPartialContent, interfaces.StatusSuccess)
+	expectAsyncResponses(mockedQueryExecutor, "g.E()", interfaces.StatusPartialContent, interfaces.StatusServerError, interfaces.StatusSuccess)
+	handlerErr := fmt.Errorf("enough")
+	calls := 0
+	callsOnError := 0
+
+	// WHEN
+	err := cosmos.ExecuteStream("g.V()", func(response interfaces.Response) error {
+		calls++
+		return handlerErr
+	})
+	errResponse := cosmos.ExecuteStream("g.E()", func(response interfaces.Response) error {
+		callsOnError++
+		return nil
+	})
+	errNoHandler := cosmos.ExecuteStream("g.V()", nil)
+
+	// THEN
+	assert.Equal(t, handlerErr, err)
+	assert.Equal(t, 1, calls, "the handler must not be called after it returned an error")
+	assert.Error(t, errResponse)
+	assert.Equal(t, 1, callsOnError, "the handler must not be called for the erroneous and the following chunks")
+	assert.Error(t, errNoHandler)
+}
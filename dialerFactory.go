@@ -1,7 +1,9 @@
 package gremcos
 
 import (
+	"crypto/tls"
 	"net/http"
+	"net/url"
 	"time"
 
 	gorilla "github.com/gorilla/websocket"
@@ -11,17 +13,22 @@ import (
 // websocketDialer is a function type for dialing/ connecting to a websocket server and creating a WebsocketConnection
 type websocketDialer func(urlStr string, requestHeader http.Header) (interfaces.WebsocketConnection, *http.Response, error)
 
-// websocketDialerFactory is a function type that is able to create websocketDialer's
-type websocketDialerFactory func(writeBufferSize, readBufferSize int, handshakeTimout time.Duration) websocketDialer
+// websocketDialerFactory is a function type that is able to create websocketDialer's.
+// In case proxyURL is not nil the connections are established through this proxy.
+type websocketDialerFactory func(writeBufferSize, readBufferSize int, handshakeTimout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer
 
 // gorillaWebsocketDialerFactory is a function that is able to create websocketDialer's using the websocket implementation
 // of github.com/gorilla/websocket
-var gorillaWebsocketDialerFactory = func(writeBufferSize, readBufferSize int, handshakeTimout time.Duration) websocketDialer {
+var gorillaWebsocketDialerFactory = func(writeBufferSize, readBufferSize int, handshakeTimout time.Duration, tlsConfig *tls.Config, proxyURL *url.URL) websocketDialer {
 	// create the gorilla websocket dialer
 	dialer := gorilla.Dialer{
 		WriteBufferSize:  writeBufferSize,
 		ReadBufferSize:   readBufferSize,
 		HandshakeTimeout: handshakeTimout,
+		TLSClientConfig:  tlsConfig,
+	}
+	if proxyURL != nil {
+		dialer.Proxy = http.ProxyURL(proxyURL)
 	}
 
 	// return the websocketDialer, wrapping the gorilla websocket dial call
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -28,7 +29,11 @@ func main() {
 	go processLoop(cosmos, logger, exitChannel)
 
 	<-exitChannel
-	if err := cosmos.Stop(); err != nil {
+
+	// wait for the in-flight queries before the connections are closed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if err := cosmos.StopWithContext(ctx); err != nil {
 		logger.Error().Err(err).Msg("Failed to stop cosmos connector")
 	}
 	logger.Info().Msg("Teared down")
@@ -1,6 +1,7 @@
 package gremcos
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 
@@ -51,3 +52,40 @@ func BenchmarkPoolExecute20(b *testing.B)  { benchmarkPoolExecute(20, b) }
 func BenchmarkPoolExecute40(b *testing.B)  { benchmarkPoolExecute(40, b) }
 func BenchmarkPoolExecute80(b *testing.B)  { benchmarkPoolExecute(80, b) }
 func BenchmarkPoolExecute160(b *testing.B) { benchmarkPoolExecute(160, b) }
+
+func seedQueries(numQueries int) []string {
+	queries := make([]string, 0, numQueries)
+	for i := 0; i < numQueries; i++ {
+		queries = append(queries, fmt.Sprintf(`g.addV("BenchmarkBatchData").property("user_id","%d")`, i))
+	}
+	return queries
+}
+
+func benchmarkExecuteLoop(numQueries int, b *testing.B) {
+	once.Do(initBeforeBenchmark)
+	queries := seedQueries(numQueries)
+
+	for n := 0; n < b.N; n++ {
+		for _, query := range queries {
+			if _, err := benchmarkClient.Execute(query); err != nil {
+				b.Error(err)
+			}
+		}
+	}
+}
+
+func benchmarkExecuteBatch(numQueries int, b *testing.B) {
+	once.Do(initBeforeBenchmark)
+	queries := seedQueries(numQueries)
+
+	for n := 0; n < b.N; n++ {
+		if _, err := benchmarkClient.ExecuteBatch(queries); err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkExecuteLoop10(b *testing.B)   { benchmarkExecuteLoop(10, b) }
+func BenchmarkExecuteLoop100(b *testing.B)  { benchmarkExecuteLoop(100, b) }
+func BenchmarkExecuteBatch10(b *testing.B)  { benchmarkExecuteBatch(10, b) }
+func BenchmarkExecuteBatch100(b *testing.B) { benchmarkExecuteBatch(100, b) }
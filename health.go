@@ -0,0 +1,187 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// HealthStatus is a detailed diagnostic result about the health of the connection to the CosmosDB.
+// It can be used to distinguish a connection that is alive but throttled from a dead one.
+type HealthStatus struct {
+	// Alive is true in case the CosmosDB could be reached, either by a successful query within
+	// the freshness window or by a successful ping
+	Alive bool
+	// LastSuccessfulPing is the last time the CosmosDB could be reached (by a ping or a query that received a response)
+	LastSuccessfulPing time.Time
+	// PoolSize is the current number of (idle and active) connections managed by the pool
+	PoolSize int
+	// LastError is the last error that occurred while pinging or querying the CosmosDB (nil if there was none)
+	LastError error
+	// Throttled is true in case the status code of the last query indicated that the request was throttled (429)
+	Throttled bool
+}
+
+// healthState keeps track of the information needed to provide the HealthStatus.
+// The zero value is ready to use.
+type healthState struct {
+	mux              sync.Mutex
+	lastSuccessfulAt time.Time
+	lastError        error
+	throttled        bool
+	// disconnected is true as soon as the CosmosDB can't be reached anymore over any of the connections
+	disconnected bool
+}
+
+// connectivityTransition is the change of the connectivity to the CosmosDB
+type connectivityTransition int
+
+const (
+	connectivityUnchanged connectivityTransition = iota
+	connectivityLost
+	connectivityRestored
+)
+
+// updateConnectivity updates the connectivity state and returns the according transition. The CosmosDB
+// is regarded as disconnected in case it could not be reached and the pool has no connected connection left.
+func (h *healthState) updateConnectivity(reached bool, isConnected func() bool) connectivityTransition {
+	connected := reached || isConnected()
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if connected == !h.disconnected {
+		return connectivityUnchanged
+	}
+	h.disconnected = !connected
+	if connected {
+		return connectivityRestored
+	}
+	return connectivityLost
+}
+
+// recordResponses updates the health state based on the result of a query
+func (h *healthState) recordResponses(responses []interfaces.Response, err error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	// a received response (even an erroneous one) proves that the connection is alive
+	if len(responses) > 0 {
+		h.lastSuccessfulAt = time.Now()
+	}
+
+	if err != nil {
+		h.lastError = err
+	}
+
+	_, h.throttled = extractThrottling(responses)
+}
+
+// recordPing updates the health state based on the result of a ping
+func (h *healthState) recordPing(err error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if err != nil {
+		h.lastError = err
+		return
+	}
+	h.lastSuccessfulAt = time.Now()
+}
+
+// isFresh returns true in case the CosmosDB could be reached within the given window
+func (h *healthState) isFresh(window time.Duration) bool {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if window <= 0 || h.lastSuccessfulAt.IsZero() {
+		return false
+	}
+	return time.Since(h.lastSuccessfulAt) <= window
+}
+
+// recordQueryConnectivity records the connectivity based on the result of a query (see recordConnectivity). The CosmosDB
+// was reached in case responses were received. Errors without responses are only recorded as failure if they are transport
+// errors, since errors caused by the client (e.g. a failed serialization) don't tell anything about the connectivity.
+func (c *cosmosImpl) recordQueryConnectivity(responses []interfaces.Response, err error) {
+	reached := len(responses) > 0 || err == nil
+	if !reached && !isTransportError(err) {
+		return
+	}
+	c.recordConnectivity(reached, err)
+}
+
+// recordConnectivity updates the circuit breaker and the connectivity state based on the result of a query or ping and
+// invokes the according callbacks on a transition. The connectivity is only tracked in case one of the callbacks is set.
+func (c *cosmosImpl) recordConnectivity(reached bool, err error) {
+	c.breaker.record(reached)
+
+	if c.onDisconnect == nil && c.onReconnect == nil {
+		return
+	}
+
+	switch c.health.updateConnectivity(reached, c.pool.IsConnected) {
+	case connectivityLost:
+		c.logger.Warn().Err(err).Msg("Connection to the CosmosDB lost")
+		if c.onDisconnect != nil {
+			c.onDisconnect(err)
+		}
+	case connectivityRestored:
+		c.logger.Info().Msg("Connection to the CosmosDB restored")
+		if c.onReconnect != nil {
+			c.onReconnect()
+		}
+	}
+}
+
+// HealthStatus returns a detailed diagnostic result about the health of the connection to the CosmosDB.
+// In case the CosmosDB was reached successfully within the freshness window (see WithHealthCheckFreshness)
+// no ping is issued, hence no new connection is opened.
+func (c *cosmosImpl) HealthStatus() HealthStatus {
+	alive := c.health.isFresh(c.healthCheckFreshness)
+	if !alive {
+		err := c.pool.Ping()
+		c.health.recordPing(err)
+		c.recordConnectivity(err == nil, err)
+		alive = (err == nil)
+	}
+
+	c.health.mux.Lock()
+	status := HealthStatus{
+		Alive:              alive,
+		LastSuccessfulPing: c.health.lastSuccessfulAt,
+		LastError:          c.health.lastError,
+		Throttled:          c.health.throttled,
+	}
+	c.health.mux.Unlock()
+
+	status.PoolSize = c.Stats().Active
+	return status
+}
+
+// WaitUntilHealthy polls IsHealthy every interval until it succeeds or the given context is done.
+func (c *cosmosImpl) WaitUntilHealthy(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("Invalid interval %v, the interval has to be greater than 0", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := c.IsHealthy()
+		if err == nil {
+			return nil
+		}
+		c.logger.Debug().Err(err).Msg("Not healthy yet, waiting for the connection to the CosmosDB")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Waiting until healthy was aborted (%v): %v", ctx.Err(), err)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+func TestHealthStatusThrottledButAlive(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithHealthCheckFreshness(time.Minute))
+	query := "g.V()"
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledResponse("00:00:00.010")}, nil)
+	// no ping expected since the connection was used within the freshness window
+	mockedQueryExecutor.EXPECT().Ping().Times(0)
+
+	// WHEN
+	_, queryErr := cosmos.Execute(query)
+	status := cosmos.HealthStatus()
+
+	// THEN
+	assert.Error(t, queryErr)
+	assert.True(t, status.Alive)
+	assert.True(t, status.Throttled)
+	assert.False(t, status.LastSuccessfulPing.IsZero())
+	assert.Equal(t, queryErr, status.LastError)
+}
+
+func TestHealthStatusDead(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithHealthCheckFreshness(time.Minute))
+	pingErr := fmt.Errorf("connection refused")
+	mockedQueryExecutor.EXPECT().Ping().Return(pingErr)
+
+	// WHEN
+	status := cosmos.HealthStatus()
+
+	// THEN
+	assert.False(t, status.Alive)
+	assert.False(t, status.Throttled)
+	assert.True(t, status.LastSuccessfulPing.IsZero())
+	assert.Equal(t, pingErr, status.LastError)
+}
+
+func TestHealthStatusPingsOutsideFreshnessWindow(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V()"
+	success := []interfaces.Response{{RequestID: "ok", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	mockedQueryExecutor.EXPECT().Execute(query).Return(success, nil)
+	// per default there is no freshness window, hence a ping is issued
+	mockedQueryExecutor.EXPECT().Ping().Return(nil)
+
+	// WHEN
+	_, queryErr := cosmos.Execute(query)
+	status := cosmos.HealthStatus()
+
+	// THEN
+	assert.NoError(t, queryErr)
+	assert.True(t, status.Alive)
+	assert.False(t, status.Throttled)
+	assert.NoError(t, status.LastError)
+}
+
+func TestHealthStatePoolSize(t *testing.T) {
+	// GIVEN
+	cImpl := &cosmosImpl{pool: &pool{active: 2, idleConnections: []*idleConnection{{}}}, healthCheckFreshness: time.Minute}
+	cImpl.health.recordPing(nil)
+
+	// WHEN
+	status := cImpl.HealthStatus()
+
+	// THEN
+	assert.True(t, status.Alive)
+	assert.Equal(t, 3, status.PoolSize)
+}
+
+func TestWaitUntilHealthy(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Ping().Return(fmt.Errorf("connection refused")).Times(2),
+		mockedQueryExecutor.EXPECT().Ping().Return(nil),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// WHEN
+	err := cosmos.WaitUntilHealthy(ctx, time.Millisecond)
+
+	// THEN
+	assert.NoError(t, err)
+}
+
+func TestWaitUntilHealthyContextDone(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	mockedQueryExecutor.EXPECT().Ping().Return(fmt.Errorf("connection refused")).MinTimes(1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	// WHEN
+	err := cosmos.WaitUntilHealthy(ctx, time.Millisecond*5)
+
+	// THEN
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+}
+
+func TestWaitUntilHealthyInvalidInterval(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, _ := newCosmosWithMockedPool(t, mockCtrl)
+
+	// WHEN
+	err := cosmos.WaitUntilHealthy(context.Background(), 0)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func TestConnectivityCallbacks(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	var disconnectErrors []error
+	reconnects := 0
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl,
+		WithOnDisconnect(func(err error) { disconnectErrors = append(disconnectErrors, err) }),
+		WithOnReconnect(func() { reconnects++ }),
+	)
+	success := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	connectionLost := socketClosedByServerError{err: fmt.Errorf("connection lost")}
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Execute("g.V()").Return(success, nil),
+		mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, connectionLost),
+		mockedQueryExecutor.EXPECT().Ping().Return(fmt.Errorf("connection refused")),
+		mockedQueryExecutor.EXPECT().Ping().Return(nil),
+		mockedQueryExecutor.EXPECT().Execute("g.V()").Return(success, nil),
+	)
+	mockedQueryExecutor.EXPECT().IsConnected().Return(false).AnyTimes()
+
+	// WHEN
+	_, errHealthy := cosmos.Execute("g.V()")
+	_, errLost := cosmos.Execute("g.V()")
+	errStillLost := cosmos.IsHealthy()
+	errRestored := cosmos.IsHealthy()
+	_, errStillRestored := cosmos.Execute("g.V()")
+
+	// THEN
+	assert.NoError(t, errHealthy)
+	assert.Error(t, errLost)
+	assert.Error(t, errStillLost)
+	assert.NoError(t, errRestored)
+	assert.NoError(t, errStillRestored)
+	assert.Equal(t, []error{errLost}, disconnectErrors, "the disconnect has to be notified only once")
+	assert.Equal(t, 1, reconnects, "the reconnect has to be notified only once")
+}
+
+func TestConnectivityCallbacksOtherConnectionAlive(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	disconnects := 0
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithOnDisconnect(func(err error) { disconnects++ }))
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, socketClosedByServerError{err: fmt.Errorf("connection lost")})
+	mockedQueryExecutor.EXPECT().IsConnected().Return(true)
+
+	// WHEN
+	_, err := cosmos.Execute("g.V()")
+
+	// THEN
+	assert.Error(t, err)
+	assert.Equal(t, 0, disconnects, "the pool still has a connected connection")
+}
@@ -20,10 +20,27 @@ type Graph interface {
 	VByUUID(id uuid.UUID) Vertex
 	// VByStr adds .V(<id>), e.g. .V("123a"), to the query.  The query call returns the vertex with the given id.
 	VByStr(id string) Vertex
+	// VById adds .V(<id_1>,<id_2>,..,<id_n>), e.g. .V("1a",2), to the query. The query call returns the vertices with the given ids.
+	// String ids are quoted while numeric ids are not.
+	VById(ids ...interface{}) Vertex
 	// AddV adds .addV('<label>'), e.g. .addV('user'), to the query. The query call adds a vertex with the given label and returns that vertex.
 	AddV(label string) Vertex
 	// E adds .E() to the query. The query call returns all edges.
 	E() Edge
+	// EById adds .E(<id_1>,<id_2>,..,<id_n>), e.g. .E("1a",2), to the query. The query call returns the edges with the given ids.
+	// String ids are quoted while numeric ids are not.
+	EById(ids ...interface{}) Edge
+	// Inject adds .inject(<values>), e.g. .inject("a",1,true), to the query. The query call returns the given constant values.
+	// The values are quoted like for Has.
+	Inject(values ...interface{}) Vertex
+	// MergeV adds .mergeV(<search criteria>), e.g. .mergeV([(T.label):"person","name":"hans"]), to the query. The query call returns
+	// the vertex matching the search criteria, in case no such vertex exists it is created (upsert). The keys are property names or tokens (e.g. T.label).
+	// Hint: Not supported by the CosmosDB.
+	MergeV(searchCriteria map[interface{}]interface{}) Vertex
+	// MergeE adds .mergeE(<search criteria>), e.g. .mergeE([(T.label):"knows",(Direction.OUT):"1",(Direction.IN):"2"]), to the query. The query call
+	// returns the edge matching the search criteria, in case no such edge exists it is created (upsert). The keys are property names or tokens (e.g. T.label).
+	// Hint: Not supported by the CosmosDB.
+	MergeE(searchCriteria map[interface{}]interface{}) Edge
 }
 
 // Vertex represents a QueryBuilder that can be used to create
@@ -33,17 +50,34 @@ type Vertex interface {
 	Dropper
 	Profiler
 	Counter
+	NumericAggregator
 
 	// HasLabel adds .hasLabel([<label_1>,<label_2>,..,<label_n>]), e.g. .hasLabel('user','name'), to the query. The query call returns all vertices with the given label.
 	HasLabel(vertexLabel ...string) Vertex
 
+	// HasLabelPredicate adds .hasLabel(<predicate>), e.g. .hasLabel(within('user','admin')), to the query. The query call returns all vertices
+	// with a label matching the given predicate.
+	HasLabelPredicate(predicate Predicate) Vertex
+
+	// Label adds .label(), to the query. The query call returns the label of the vertex.
+	Label() QueryBuilder
+
 	// Property adds .property("<key>","<value>"), e.g. .property("name","hans") depending on the given type the quotes for the value are omitted.
 	// e.g. .property("temperature",23.02) or .property("available",true)
 	Property(key, value interface{}) Vertex
 
+	// PropertyE adds .property("<key>","<value>"), e.g. .property("name","hans") depending on the given type the quotes for the value are omitted.
+	// In contrast to Property an error is returned (instead of a panic) in case the given value is not supported.
+	PropertyE(key, value interface{}) (Vertex, error)
+
 	// PropertyList adds .property(list,'<key>','<value>'), e.g. .property(list, 'name','hans'), to the query. The query call will add the given property.
 	PropertyList(key, value string) Vertex
 
+	// PropertyWithCardinality adds .property(<cardinality>,"<key>","<value>"), e.g. .property(single,"name","hans"), to the query.
+	// Depending on the given type the quotes for the value are omitted, e.g. .property(list,"temperature",23.02).
+	// Hint: CardinalitySet is not supported by the CosmosDB.
+	PropertyWithCardinality(cardinality Cardinality, key string, value interface{}) Vertex
+
 	// Properties adds .properties(), to the query. The query call returns all properties of the vertex.
 	// The method can also be used to return only specific properties identified by their name.
 	// Then .properties("<prop1 name>","<prop2 name>",...) will be added to the query.
@@ -57,6 +91,22 @@ type Vertex interface {
 	//	v.Has("prop1")
 	Has(key string, value ...interface{}) Vertex
 
+	// HasE adds .has("<key>","<value>"), e.g. .has("name","hans") depending on the given type the quotes for the value are omitted.
+	// In contrast to Has an error is returned (instead of a panic) in case the given value is not supported.
+	HasE(key string, value interface{}) (Vertex, error)
+
+	// HasNotValue adds .not(__.has("<key>","<value>")), e.g. .not(__.has("name","hans")), to the query. The query call returns all vertices
+	// that don't have the given property value (including the ones without this property). The value is quoted like for Has.
+	HasNotValue(key string, value interface{}) Vertex
+
+	// Inject adds .inject(<values>), e.g. .inject("a",1,true), to the query. The query call adds the given constant values
+	// to the traversal. The values are quoted like for Has.
+	Inject(values ...interface{}) Vertex
+
+	// Constant adds .constant(<value>), e.g. .constant("unknown"), to the query. The query call replaces each element by
+	// the given constant value. The value is quoted like for Has.
+	Constant(value interface{}) QueryBuilder
+
 	// HasId adds .hasId('<id>'), e.g. .hasId('8aaaa410-dae1-4f33-8dd7-0217e69df10c'), to the query. The query call returns all vertices
 	// with the given id.
 	HasId(id string) Vertex
@@ -70,6 +120,30 @@ type Vertex interface {
 	// ValueMap adds .valueMap(), to the query. The query call returns all values as a map of the vertex.
 	ValueMap() QueryBuilder
 
+	// ValueMapWithTokens adds .valueMap(true) (QueryLanguageCosmosDB) or .valueMap().with(WithOptions.tokens) (QueryLanguageTinkerpopGremlin),
+	// to the query. The query call returns all values as a map of the vertex including its id and label.
+	ValueMapWithTokens() QueryBuilder
+
+	// ElementMap adds .elementMap() or .elementMap("<key_1>",...,"<key_n>"), e.g. .elementMap("name","age"), to the query.
+	// The query call returns the id, the label and the (given) properties of the vertex as flat map.
+	// Hint: This step is not supported by the CosmosDB and thus can only be used with QueryLanguageTinkerpopGremlin.
+	ElementMap(keys ...string) QueryBuilder
+
+	// Math adds .math("<expression>"), e.g. .math("a + b"), to the query. The query call evaluates the given arithmetic expression,
+	// the variables of the expression are specified by adding By modulators, e.g. .math("a / b").by("wins").by("games").
+	// Hint: This step is not supported by the CosmosDB and thus can only be used with QueryLanguageTinkerpopGremlin.
+	Math(expression string) Vertex
+
+	// IncrementProperty adds .sideEffect(property("<key>",union(values("<key>"),constant(<by>)).sum())), e.g. for key "views" and by 1, to the query.
+	// The query call increments the numeric property by the given value on server side, a missing property is set to the given value.
+	// Hint: This step is not supported by the CosmosDB and thus can only be used with QueryLanguageTinkerpopGremlin,
+	// with QueryLanguageCosmosDB (the default) it panics. Use IncrementPropertyE to get an error instead.
+	IncrementProperty(key string, by int) Vertex
+
+	// IncrementPropertyE adds the same steps as IncrementProperty to the query.
+	// In contrast to IncrementProperty an error is returned (instead of a panic) in case QueryLanguageCosmosDB is in use.
+	IncrementPropertyE(key string, by int) (Vertex, error)
+
 	// Add can be used to add a custom QueryBuilder
 	// e.g. g.V().Add(NewSimpleQB(".myCustomCall('%s')",label))
 	Add(builder QueryBuilder) Vertex
@@ -89,8 +163,145 @@ type Vertex interface {
 	// Limit adds .limit(<num>), to the query. The query call will limit the results of the query to the given number.
 	Limit(maxElements int) Vertex
 
+	// LimitLocal adds .limit(local,<num>), to the query. In contrast to Limit the number of elements within each
+	// collection (e.g. after fold or valueMap) is limited instead of the number of results.
+	LimitLocal(maxElements int) Vertex
+
+	// Sample adds .sample(<num>), e.g. .sample(1000), to the query. The query call returns the given number of randomly chosen elements.
+	Sample(n int) Vertex
+
+	// Dedup adds .dedup(), to the query. The query call removes the duplicated elements.
+	Dedup() Vertex
+	// DedupBy adds .dedup().by("<key>"), e.g. .dedup().by("name"), to the query. The query call removes the elements
+	// with a duplicated value of the given property.
+	DedupBy(key string) Vertex
+	// DedupById adds .dedup().by(id), to the query. The query call removes the elements with a duplicated id.
+	// Hint: On a partitioned CosmosDB the id is only unique per partition, hence in contrast to Dedup elements
+	// with the same id but different partition keys are merged.
+	DedupById() Vertex
+
 	// As adds .as([<label_1>,<label_2>,..,<label_n>]), to the query to label that query step for later access.
 	As(labels ...string) Vertex
+
+	// Aggregate adds .aggregate("<key>"), e.g. .aggregate("x"), to the query. The query call collects all elements of this step
+	// into the side-effect with the given key (eager, all elements are collected before the traversal continues).
+	Aggregate(key string) Vertex
+	// Store adds .store("<key>"), e.g. .store("x"), to the query. The query call collects the elements of this step into the
+	// side-effect with the given key (lazy, the elements are collected while the traversal continues).
+	Store(key string) Vertex
+	// Cap adds .cap("<key_1>",..,"<key_n>"), e.g. .cap("x","y"), to the query. The query call emits the side-effect(s)
+	// with the given key(s), for multiple keys a map of all side-effects is returned.
+	Cap(keys ...string) QueryBuilder
+	// CollectInto adds .aggregate("<key>").cap("<key>"), e.g. .aggregate("x").cap("x"), to the query. The query call returns
+	// the list of all elements that reached this step of the traversal.
+	CollectInto(key string) QueryBuilder
+
+	// UpsertV adds <match>.fold().coalesce(__.unfold(),<create>), to the query. The query call returns the vertex found by the
+	// match traversal or creates it using the create traversal in case it does not exist. The prefix __. is omitted for TinkerPop.
+	//	g.V().UpsertV(NewSimpleQB(".has(\"name\",\"hans\")"), NewSimpleQB("addV(\"user\").property(\"name\",\"hans\")"))
+	UpsertV(matchTraversal QueryBuilder, createTraversal QueryBuilder) Vertex
+
+	// Project adds .project([<key_1>,<key_2>,..,<key_n>]), e.g. .project("name","age"), to the query. The query call returns one map per vertex
+	// containing the given keys. The values are specified by adding one By modulator per key.
+	//	g.V().Project("name","age").By("name").By("age")
+	Project(keys ...string) Vertex
+
+	// By adds .by("<key>") or .by("<key>",<order>), e.g. .by("name") or .by("age",decr), to the query. It modulates the previous step
+	// (e.g. Project or Order). At most one order can be given.
+	By(key string, order ...Order) Vertex
+	// ByTraversal adds .by(<traversal>) or .by(<traversal>,<order>), e.g. .by(out("knows").count(),decr), to the query. It modulates
+	// the previous step (e.g. Project or Order) by the result of the given (anonymous) traversal. At most one order can be given.
+	ByTraversal(traversal QueryBuilder, order ...Order) Vertex
+
+	// Order adds .order(), to the query. The query call sorts the elements, the sort criteria are specified by adding By modulators.
+	//	g.V().Order().By("age", interfaces.OrderDesc)
+	Order() Vertex
+
+	// Path adds .path(), to the query. The query call returns the path (the visited elements) of each traverser.
+	// The elements of the path can be shaped by following By modulators, e.g. .path().by("name").
+	Path() Vertex
+
+	// SimplePath adds .simplePath(), to the query. It filters out traversers whose path contains an element more than once (cycles).
+	SimplePath() Vertex
+
+	// CyclicPath adds .cyclicPath(), to the query. It keeps only the traversers whose path contains an element more than once (cycles).
+	CyclicPath() Vertex
+
+	// Union adds .union(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .union(in("knows"),out("knows")), to the query.
+	// The query call returns the merged results of all given (anonymous) traversals.
+	Union(traversals ...QueryBuilder) Vertex
+
+	// Local adds .local(<traversal>), e.g. .local(out("knows").limit(1)), to the query.
+	// The query call applies the given (anonymous) traversal to each element separately instead of to the whole stream.
+	Local(traversal QueryBuilder) Vertex
+
+	// Barrier adds .barrier() or with a size .barrier(<maxBarrierSize>), e.g. .barrier(2500), to the query.
+	// The query call collects the elements of the previous steps before continuing, which allows to bulk them.
+	Barrier(maxBarrierSize ...int) Vertex
+
+	// SideEffect adds .sideEffect(<traversal>), e.g. .sideEffect(properties("x").drop()), to the query. The query call executes the
+	// given (anonymous) traversal for each element, while the main traversal continues with the unchanged elements.
+	// Hint: This step is not supported by the CosmosDB and thus can only be used with QueryLanguageTinkerpopGremlin.
+	SideEffect(traversal QueryBuilder) Vertex
+
+	// Not adds .not(<traversal>), e.g. .not(has("x")), to the query. The query call keeps only the elements
+	// for which the given (anonymous) traversal returns no result.
+	Not(traversal QueryBuilder) Vertex
+
+	// And adds .and(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .and(has("a"),has("b")), to the query.
+	// The query call keeps only the elements for which all given (anonymous) traversals return a result.
+	And(traversals ...QueryBuilder) Vertex
+
+	// Or adds .or(<traversal_1>,<traversal_2>,..,<traversal_n>), e.g. .or(has("a"),has("b")), to the query.
+	// The query call keeps only the elements for which at least one of the given (anonymous) traversals returns a result.
+	Or(traversals ...QueryBuilder) Vertex
+
+	// Choose adds .choose(<pick traversal>), e.g. .choose(values("type")), to the query. The result of the pick traversal
+	// is used to select the branch to continue with. The branches are specified by adding one Option per branch.
+	//	g.V().Choose(NewSimpleQB("values(\"type\")")).Option("a", NewSimpleQB("out()")).Option("b", NewSimpleQB("in()"))
+	// With three traversals .choose(<predicate>,<true traversal>,<false traversal>) (if/else) is added instead.
+	//	g.V().Choose(Underscore().HasLabel("admin"), Underscore().OutE("manages").InV(), Underscore().OutE("knows").InV())
+	Choose(traversals ...QueryBuilder) Vertex
+
+	// Option adds .option(<match>,<then traversal>), e.g. .option("a",out()), to the query. It modulates the previous Choose step.
+	// Depending on the given type the quotes for the match value are omitted.
+	Option(match interface{}, thenTraversal QueryBuilder) Vertex
+
+	// OnCreate adds .option(Merge.onCreate,<properties>), e.g. .option(Merge.onCreate,["created":true]), to the query. It modulates the
+	// previous MergeV step, the given properties are set in case the vertex is created. Hint: Not supported by the CosmosDB.
+	OnCreate(properties map[interface{}]interface{}) Vertex
+	// OnMatch adds .option(Merge.onMatch,<properties>), e.g. .option(Merge.onMatch,["updated":true]), to the query. It modulates the
+	// previous MergeV step, the given properties are set in case the vertex already exists. Hint: Not supported by the CosmosDB.
+	OnMatch(properties map[interface{}]interface{}) Vertex
+
+	// Repeat adds .repeat(<traversal>), e.g. .repeat(out()), to the query. The loop can be limited by adding Times.
+	Repeat(traversal QueryBuilder) Vertex
+
+	// Times adds .times(<num>), e.g. .times(3), to the query. It limits the number of loops of the previous Repeat step.
+	// It panics in case it does not follow a repeat step (or one of its modulators), since an orphaned times does not limit any loop.
+	Times(maxLoops int) Vertex
+
+	// TimesRepeat adds .times(<num>).repeat(<traversal>), e.g. .times(3).repeat(out()), to the query.
+	// The number of loops is checked before each loop (while-do semantics).
+	TimesRepeat(maxLoops int, traversal QueryBuilder) Vertex
+
+	// RepeatUntil adds .repeat(<traversal>).until(<until traversal>), e.g. .repeat(out()).until(hasLabel("root")), to the query.
+	// The until condition is checked after each loop (do-while semantics), hence the traversal is executed at least once.
+	RepeatUntil(traversal QueryBuilder, untilTraversal QueryBuilder) Vertex
+
+	// UntilRepeat adds .until(<until traversal>).repeat(<traversal>), e.g. .until(hasLabel("root")).repeat(out()), to the query.
+	// The until condition is checked before each loop (while-do semantics), hence the traversal might not be executed at all.
+	UntilRepeat(untilTraversal QueryBuilder, traversal QueryBuilder) Vertex
+
+	// RepeatEmit adds .repeat(<traversal>).emit() or .repeat(<traversal>).emit(<emit traversal>), e.g. .repeat(out()).emit(hasLabel("user")),
+	// to the query. The vertices are emitted after each loop, hence the start vertices are not part of the result.
+	// The emit traversal is optional, if it is nil all vertices are emitted.
+	RepeatEmit(traversal QueryBuilder, emitTraversal QueryBuilder) Vertex
+
+	// EmitRepeat adds .emit().repeat(<traversal>) or .emit(<emit traversal>).repeat(<traversal>), e.g. .emit(hasLabel("user")).repeat(out()),
+	// to the query. The vertices are emitted before each loop, hence the start vertices are part of the result as well.
+	// The emit traversal is optional, if it is nil all vertices are emitted.
+	EmitRepeat(emitTraversal QueryBuilder, traversal QueryBuilder) Vertex
 }
 
 type Edge interface {
@@ -99,10 +310,25 @@ type Edge interface {
 	Profiler
 	Counter
 
-	// To adds .to(<vertex>), to the query. The query call will be the second step to add an edge
-	To(v Vertex) Edge
-	// From adds .from(<vertex>), to the query. The query call will be the second step to add an edge
-	From(v Vertex) Edge
+	// To adds .to(<traversal>), e.g. .to(g.V().has("name","hans")), to the query. The query call will be the second step to add an edge
+	To(traversal QueryBuilder) Edge
+	// From adds .from(<traversal>), e.g. .from(g.V().has("name","hans")), to the query. The query call will be the second step to add an edge
+	From(traversal QueryBuilder) Edge
+	// ToId adds .to(g.V("<id>")), to the query. The created edge will point to the vertex with the given id.
+	ToId(id string) Edge
+	// FromId adds .from(g.V("<id>")), to the query. The created edge will start at the vertex with the given id.
+	FromId(id string) Edge
+	// ToLabel adds .to("<step label>"), e.g. .to("y"), to the query. The created edge will point to the vertex that was labeled using As.
+	ToLabel(stepLabel string) Edge
+	// FromLabel adds .from("<step label>"), e.g. .from("x"), to the query. The created edge will start at the vertex that was labeled using As.
+	FromLabel(stepLabel string) Edge
+
+	// OnCreate adds .option(Merge.onCreate,<properties>), e.g. .option(Merge.onCreate,["created":true]), to the query. It modulates the
+	// previous MergeE step, the given properties are set in case the edge is created. Hint: Not supported by the CosmosDB.
+	OnCreate(properties map[interface{}]interface{}) Edge
+	// OnMatch adds .option(Merge.onMatch,<properties>), e.g. .option(Merge.onMatch,["updated":true]), to the query. It modulates the
+	// previous MergeE step, the given properties are set in case the edge already exists. Hint: Not supported by the CosmosDB.
+	OnMatch(properties map[interface{}]interface{}) Edge
 
 	// OutV adds .outV(), to the query. The query call will return the vertices on the outgoing side of this edge
 	OutV() Vertex
@@ -146,6 +372,44 @@ type Property interface {
 	As(labels ...string) Property
 }
 
+// Cardinality defines how many values a property can hold.
+type Cardinality string
+
+const (
+	// CardinalitySingle the property holds exactly one value, setting it again replaces the value.
+	CardinalitySingle Cardinality = "single"
+	// CardinalityList the property holds a list of values, setting it again appends the value.
+	CardinalityList Cardinality = "list"
+	// CardinalitySet the property holds a set of values, setting it again adds the value if it is not yet present.
+	// Hint: This cardinality is not supported by the CosmosDB.
+	CardinalitySet Cardinality = "set"
+)
+
+// Order defines the sort order of a By modulator.
+type Order string
+
+const (
+	// OrderAsc sorts in ascending order, rendered as incr for the CosmosDB and as asc for TinkerPop.
+	OrderAsc Order = "asc"
+	// OrderDesc sorts in descending order, rendered as decr for the CosmosDB and as desc for TinkerPop.
+	OrderDesc Order = "desc"
+	// OrderShuffle sorts in random order.
+	OrderShuffle Order = "shuffle"
+)
+
+// Predicate represents a gremlin predicate, e.g. within('a','b'), that can be used
+// as argument for filtering steps like has or hasLabel.
+type Predicate interface {
+	QueryBuilder
+
+	// And combines the predicate with the given one, e.g. gt(10).and(lt(20)). It matches if both predicates match.
+	And(other Predicate) Predicate
+	// Or combines the predicate with the given one, e.g. lt(10).or(gt(20)). It matches if at least one of the predicates matches.
+	Or(other Predicate) Predicate
+	// Negate adds .negate() to the predicate, e.g. within("a","b").negate(). It matches if the predicate doesn't match.
+	Negate() Predicate
+}
+
 type Dropper interface {
 	// Drop adds .drop(), to the query. The query call will drop/ delete all referenced entities
 	Drop() QueryBuilder
@@ -159,4 +423,19 @@ type Profiler interface {
 type Counter interface {
 	// Count adds .count(), to the query. The query call will return the number of entities found in the query.
 	Count() QueryBuilder
+	// CountLocal adds .count(local), to the query. The query call will return the number of elements within each
+	// collection (e.g. the lists of a group step) instead of the number of traversers.
+	CountLocal() QueryBuilder
+}
+
+// NumericAggregator provides the steps to aggregate a stream of numeric values (e.g. the result of .values("price")) on server side
+type NumericAggregator interface {
+	// Sum adds .sum(), to the query. The query call will return the sum of the preceding numeric values.
+	Sum() QueryBuilder
+	// Max adds .max(), to the query. The query call will return the largest of the preceding numeric values.
+	Max() QueryBuilder
+	// Min adds .min(), to the query. The query call will return the smallest of the preceding numeric values.
+	Min() QueryBuilder
+	// Mean adds .mean(), to the query. The query call will return the average of the preceding numeric values.
+	Mean() QueryBuilder
 }
@@ -11,9 +11,12 @@ type QueryExecutor interface {
 	LastError() error
 	Execute(query string) (resp []Response, err error)
 	ExecuteAsync(query string, responseChannel chan AsyncResponse) (err error)
+	ExecuteWithID(requestID, query string) (resp []Response, err error)
+	ExecuteAsyncWithID(requestID, query string, responseChannel chan AsyncResponse) (err error)
 	ExecuteFileWithBindings(path string, bindings, rebindings map[string]interface{}) (resp []Response, err error)
 	ExecuteFile(path string) (resp []Response, err error)
 	ExecuteWithBindings(query string, bindings, rebindings map[string]interface{}) (resp []Response, err error)
+	ExecuteBatch(queries []string) (resp [][]Response, err error)
 	Ping() error
 }
 
@@ -53,9 +56,11 @@ type Result struct {
 }
 
 // AsyncResponse structs holds the entire response from requests to the gremlin server
+// The chunks (partial responses) of one request are delivered in the order they were sent by the server.
 type AsyncResponse struct {
 	Response     Response `json:"response"`     //Partial Response object
 	ErrorMessage string   `json:"errorMessage"` // Error message if there was an error
+	ChunkIndex   int      `json:"chunkIndex"`   // Index of the chunk (starting at 0) in the order the server sent them
 }
 
 // String returns a string representation of the Response struct
@@ -15,6 +15,13 @@ type Metrics struct {
 	requestChargePerQueryResponseAvg m.Gauge
 	serverTimePerQueryMS             m.Gauge
 	serverTimePerQueryResponseAvgMS  m.Gauge
+	requestUnitsTotal                m.Counter
+	queryDurationSeconds             m.Histogram
+	connectionsCreatedTotal          m.Counter
+	connectionsClosedIdleTotal       m.Counter
+	connectionsClosedErrorTotal      m.Counter
+	connectionsIdle                  m.Gauge
+	connectionsEvictedTotal          m.CounterVec
 }
 
 // NewMetrics returns the metrics collection
@@ -69,6 +76,54 @@ func NewMetrics(namespace string) *Metrics {
 		Help:      "The average time spent in ms for one query per response.",
 	})
 
+	requestUnitsTotal := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cosmos",
+		Name:      "request_units_total",
+		Help:      "The request units (RU) consumed by all queries issued so far. For each query the total request charge reported by cosmos is added.",
+	})
+
+	queryDurationSeconds := promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "query_duration_seconds",
+		Help:      "The time in seconds it took to execute a query, including the retrieval of all of its responses.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	connectionsCreatedTotal := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pool",
+		Name:      "connections_created_total",
+		Help:      "The number of connections to cosmos that have been established by the connection pool.",
+	})
+
+	connectionsClosedIdleTotal := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pool",
+		Name:      "connections_closed_idle_total",
+		Help:      "The number of connections that have been closed by the connection pool since they were idle for longer than the idle timeout.",
+	})
+
+	connectionsClosedErrorTotal := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "pool",
+		Name:      "connections_closed_error_total",
+		Help:      "The number of connections that have been removed from the connection pool due to an error or since they were not connected any more.",
+	})
+
+	connectionsIdle := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "pool",
+		Name:      "connections_idle",
+		Help:      "The number of idle connections currently kept by the connection pool.",
+	})
+
+	connectionsEvictedTotal := m.NewWrappedCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connections_evicted_total",
+		Help:      "The number of connections that have been evicted from the connection pool separated by reason (idle, lifetime, error, keepalive_failed).",
+	}, []string{"reason"})
+
 	return &Metrics{
 		statusCodeTotal:                  statusCodeTotal,
 		retryAfterMS:                     retryAfterMS,
@@ -77,5 +132,12 @@ func NewMetrics(namespace string) *Metrics {
 		requestChargePerQueryResponseAvg: requestChargePerQueryResponseAvg,
 		serverTimePerQueryMS:             serverTimePerQueryMS,
 		serverTimePerQueryResponseAvgMS:  serverTimePerQueryResponseAvgMS,
+		requestUnitsTotal:                requestUnitsTotal,
+		queryDurationSeconds:             queryDurationSeconds,
+		connectionsCreatedTotal:          connectionsCreatedTotal,
+		connectionsClosedIdleTotal:       connectionsClosedIdleTotal,
+		connectionsClosedErrorTotal:      connectionsClosedErrorTotal,
+		connectionsIdle:                  connectionsIdle,
+		connectionsEvictedTotal:          connectionsEvictedTotal,
 	}
 }
@@ -1,10 +1,15 @@
 package gremcos
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
 	mock_metrics "github.com/supplyon/gremcos/test/mocks/metrics"
 )
 
@@ -16,6 +21,13 @@ type MetricsMocks struct {
 	requestChargePerQueryResponseAvg *mock_metrics.MockGauge
 	serverTimePerQueryMS             *mock_metrics.MockGauge
 	serverTimePerQueryResponseAvgMS  *mock_metrics.MockGauge
+	requestUnitsTotal                *mock_metrics.MockCounter
+	queryDurationSeconds             *mock_metrics.MockHistogram
+	connectionsCreatedTotal          *mock_metrics.MockCounter
+	connectionsClosedIdleTotal       *mock_metrics.MockCounter
+	connectionsClosedErrorTotal      *mock_metrics.MockCounter
+	connectionsIdle                  *mock_metrics.MockGauge
+	connectionsEvictedTotal          *mock_metrics.MockCounterVec
 }
 
 // NewMockedMetrics creates and returns mocked metrics that can be used
@@ -34,6 +46,13 @@ func NewMockedMetrics(mockCtrl *gomock.Controller) (*Metrics, *MetricsMocks) {
 	mRequestChargePerQueryResponseAvg := mock_metrics.NewMockGauge(mockCtrl)
 	mServerTimePerQueryMS := mock_metrics.NewMockGauge(mockCtrl)
 	mServerTimePerQueryResponseAvgMS := mock_metrics.NewMockGauge(mockCtrl)
+	mRequestUnitsTotal := mock_metrics.NewMockCounter(mockCtrl)
+	mQueryDurationSeconds := mock_metrics.NewMockHistogram(mockCtrl)
+	mConnectionsCreatedTotal := mock_metrics.NewMockCounter(mockCtrl)
+	mConnectionsClosedIdleTotal := mock_metrics.NewMockCounter(mockCtrl)
+	mConnectionsClosedErrorTotal := mock_metrics.NewMockCounter(mockCtrl)
+	mConnectionsIdle := mock_metrics.NewMockGauge(mockCtrl)
+	mConnectionsEvictedTotal := mock_metrics.NewMockCounterVec(mockCtrl)
 
 	metrics := &Metrics{
 		statusCodeTotal:                  mStatusCodeTotal,
@@ -43,6 +62,13 @@ func NewMockedMetrics(mockCtrl *gomock.Controller) (*Metrics, *MetricsMocks) {
 		requestChargePerQueryResponseAvg: mRequestChargePerQueryResponseAvg,
 		serverTimePerQueryMS:             mServerTimePerQueryMS,
 		serverTimePerQueryResponseAvgMS:  mServerTimePerQueryResponseAvgMS,
+		requestUnitsTotal:                mRequestUnitsTotal,
+		queryDurationSeconds:             mQueryDurationSeconds,
+		connectionsCreatedTotal:          mConnectionsCreatedTotal,
+		connectionsClosedIdleTotal:       mConnectionsClosedIdleTotal,
+		connectionsClosedErrorTotal:      mConnectionsClosedErrorTotal,
+		connectionsIdle:                  mConnectionsIdle,
+		connectionsEvictedTotal:          mConnectionsEvictedTotal,
 	}
 
 	mocks := &MetricsMocks{
@@ -53,6 +79,13 @@ func NewMockedMetrics(mockCtrl *gomock.Controller) (*Metrics, *MetricsMocks) {
 		requestChargePerQueryResponseAvg: mRequestChargePerQueryResponseAvg,
 		serverTimePerQueryMS:             mServerTimePerQueryMS,
 		serverTimePerQueryResponseAvgMS:  mServerTimePerQueryResponseAvgMS,
+		requestUnitsTotal:                mRequestUnitsTotal,
+		queryDurationSeconds:             mQueryDurationSeconds,
+		connectionsCreatedTotal:          mConnectionsCreatedTotal,
+		connectionsClosedIdleTotal:       mConnectionsClosedIdleTotal,
+		connectionsClosedErrorTotal:      mConnectionsClosedErrorTotal,
+		connectionsIdle:                  mConnectionsIdle,
+		connectionsEvictedTotal:          mConnectionsEvictedTotal,
 	}
 
 	return metrics, mocks
@@ -67,4 +100,152 @@ func Test_NewMetrics(t *testing.T) {
 	assert.NotNil(t, metrics.requestChargePerQueryResponseAvg)
 	assert.NotNil(t, metrics.serverTimePerQueryMS)
 	assert.NotNil(t, metrics.serverTimePerQueryResponseAvgMS)
+	assert.NotNil(t, metrics.requestUnitsTotal)
+	assert.NotNil(t, metrics.queryDurationSeconds)
+	assert.NotNil(t, metrics.connectionsCreatedTotal)
+	assert.NotNil(t, metrics.connectionsClosedIdleTotal)
+	assert.NotNil(t, metrics.connectionsClosedErrorTotal)
+	assert.NotNil(t, metrics.connectionsIdle)
+	assert.NotNil(t, metrics.connectionsEvictedTotal)
+}
+
+// gatherCounter scrapes the default prometheus registry and returns the value of the counter with the given name
+func gatherCounter(t *testing.T, name string) float64 {
+	metricFamilies, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+		require.Len(t, metricFamily.GetMetric(), 1)
+		return metricFamily.GetMetric()[0].GetCounter().GetValue()
+	}
+	require.Fail(t, "Metric not found", name)
+	return 0
+}
+
+func TestRequestUnitsTotal(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, err := New("ws://host", MetricsPrefix("requestunitstest"))
+	require.NoError(t, err)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	toCosmosImpl(t, cosmos).pool = mockedQueryExecutor
+
+	query := "g.V()"
+	chunk := func(requestChargeTotal float64) interfaces.Response {
+		return interfaces.Response{Status: interfaces.Status{Code: interfaces.StatusSuccess, Attributes: map[string]interface{}{
+			"x-ms-status-code":          200,
+			"x-ms-total-request-charge": requestChargeTotal,
+		}}}
+	}
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{chunk(2.5), chunk(4.5)}, nil)
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{chunk(3)}, nil)
+	before := gatherCounter(t, "requestunitstest_cosmos_request_units_total")
+
+	// WHEN
+	_, err1 := cosmos.Execute(query)
+	_, err2 := cosmos.Execute(query)
+
+	// THEN
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	// the charge is accumulated by cosmos, hence only the largest value per query counts
+	assert.Equal(t, 7.5, gatherCounter(t, "requestunitstest_cosmos_request_units_total")-before)
+}
+
+// gatherHistogramSampleCount scrapes the default prometheus registry and returns the number of observations of the histogram with the given name
+func gatherHistogramSampleCount(t *testing.T, name string) uint64 {
+	metricFamilies, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+		require.Len(t, metricFamily.GetMetric(), 1)
+		return metricFamily.GetMetric()[0].GetHistogram().GetSampleCount()
+	}
+	require.Fail(t, "Metric not found", name)
+	return 0
+}
+
+func TestQueryDurationSeconds(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, err := New("ws://host", MetricsPrefix("querydurationtest"))
+	require.NoError(t, err)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	toCosmosImpl(t, cosmos).pool = mockedQueryExecutor
+
+	query := "g.V()"
+	success := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	mockedQueryExecutor.EXPECT().Execute(query).Return(success, nil)
+	before := gatherHistogramSampleCount(t, "querydurationtest_query_duration_seconds")
+
+	// WHEN
+	_, err = cosmos.Execute(query)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), gatherHistogramSampleCount(t, "querydurationtest_query_duration_seconds")-before)
+}
+
+func TestQueryDurationSecondsObservesErrors(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	cosmos, err := New("ws://host", withMetrics(metrics))
+	require.NoError(t, err)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	toCosmosImpl(t, cosmos).pool = mockedQueryExecutor
+
+	query := "g.V()"
+	mockedQueryExecutor.EXPECT().Execute(query).Return(nil, fmt.Errorf("connection lost"))
+	mockedQueryExecutor.EXPECT().ExecuteAsync(query, gomock.Any()).Return(fmt.Errorf("connection lost"))
+	metricMocks.queryDurationSeconds.EXPECT().Observe(gomock.Any()).Times(2)
+
+	// WHEN
+	_, errExecute := cosmos.Execute(query)
+	errExecuteAsync := cosmos.ExecuteAsync(query, make(chan interfaces.AsyncResponse))
+
+	// THEN
+	assert.Error(t, errExecute)
+	assert.Error(t, errExecuteAsync)
+}
+
+func TestQueryDurationSecondsAsync(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	cosmos, err := New("ws://host", withMetrics(metrics))
+	require.NoError(t, err)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	toCosmosImpl(t, cosmos).pool = mockedQueryExecutor
+
+	query := "g.V()"
+	mockedQueryExecutor.EXPECT().ExecuteAsync(query, gomock.Any()).DoAndReturn(func(query string, responseChannel chan interfaces.AsyncResponse) error {
+		go func() {
+			responseChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "1"}}
+			responseChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "2"}}
+			close(responseChannel)
+		}()
+		return nil
+	})
+	metricMocks.queryDurationSeconds.EXPECT().Observe(gomock.Any()).Times(1)
+	responseChannel := make(chan interfaces.AsyncResponse)
+
+	// WHEN
+	err = cosmos.ExecuteAsync(query, responseChannel)
+	var requestIDs []string
+	for response := range responseChannel {
+		requestIDs = append(requestIDs, response.Response.RequestID)
+	}
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, requestIDs)
 }
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/supplyon/gremcos/interfaces"
 )
@@ -21,18 +22,45 @@ type pool struct {
 	// maxActive is the maximum number of allowed active connections
 	maxActive int
 
+	// minActive is the minimum number of open connections (in use and idle ones) that are kept,
+	// idle connections are not removed due to an expired idleTimeout below this number.
+	minActive int
+
 	// idleTimeout is the maximum time a idle connection will be kept in the pool.
 	// If the timeout has been expired, the connection will be closed and removed
 	// from the pool.
 	// If this timeout is set to 0, the timeout is unlimited -> no expiration of connections.
 	idleTimeout time.Duration
 
+	// maxLifetime is the maximum time a connection is used after it was created, regardless whether it is idle or not.
+	// Connections in use are closed as soon as they are given back to the pool.
+	// If this lifetime is set to 0, the lifetime is unlimited.
+	maxLifetime time.Duration
+
 	// idleConnections list of idle connections
 	idleConnections []*idleConnection
 
 	// active is the number of currently active connections
 	active int
 
+	// waitCount is the number of calls to Get that had to wait for a free connection
+	waitCount int64
+	// waitDuration is the total time the calls to Get had to wait for a free connection
+	waitDuration time.Duration
+
+	// autoReconnect enables the transparent retry of a query on a fresh connection in case the used connection is broken
+	autoReconnect bool
+
+	// keepAliveQuit is closed to stop the keepalive go routine (nil if keepalive is not running)
+	keepAliveQuit chan struct{}
+	keepAliveWG   sync.WaitGroup
+
+	// metrics is used to report the lifecycle of the connections (optional)
+	metrics *Metrics
+
+	// now returns the current time, it can be replaced for testing (time.Now is used if nil)
+	now func() time.Time
+
 	closed bool
 	cond   *sync.Cond
 	mu     sync.RWMutex
@@ -42,6 +70,17 @@ type pool struct {
 type pooledConnection struct {
 	pool   *pool
 	client interfaces.QueryExecutor
+
+	// lastActivity is the time the connection was obtained from or given back to the pool the last time
+	lastActivity time.Time
+
+	// createdAt is the time the connection was created (dialed)
+	createdAt time.Time
+}
+
+// isExpired returns true in case the connection exceeded the given maximum lifetime (0 means unlimited)
+func (pc *pooledConnection) isExpired(maxLifetime time.Duration, now time.Time) bool {
+	return maxLifetime > 0 && !pc.createdAt.IsZero() && now.Sub(pc.createdAt) >= maxLifetime
 }
 
 // NewPool creates a new pool which is a QueryExecutor
@@ -70,10 +109,26 @@ func NewPool(createQueryExecutor QueryExecutorFactoryFunc, maxActiveConnections
 	}, nil
 }
 
+// PoolStats contains statistics about the connection pool, modeled after sql.DBStats.
+type PoolStats struct {
+	// Active is the number of open connections, the ones in use and the idle ones
+	Active int
+	// Idle is the number of idle connections
+	Idle int
+	// InUse is the number of connections currently in use
+	InUse int
+	// Max is the maximum number of active connections
+	Max int
+	// WaitCount is the total number of times a caller had to wait for a free connection
+	WaitCount int64
+	// WaitDuration is the total time callers had to wait for a free connection
+	WaitDuration time.Duration
+}
+
 type idleConnection struct {
 	pc *pooledConnection
 
-	// idleSince is the time the connection was idled
+	// idleSince is the time the connection was idled (the last activity of the connection)
 	idleSince time.Time
 }
 
@@ -108,6 +163,76 @@ func (p *pool) IsConnected() bool {
 	return false
 }
 
+// Stats returns the current statistics of the pool
+func (p *pool) Stats() PoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return PoolStats{
+		Active:       p.active + len(p.idleConnections),
+		Idle:         len(p.idleConnections),
+		InUse:        p.active,
+		Max:          p.maxActive,
+		WaitCount:    p.waitCount,
+		WaitDuration: p.waitDuration,
+	}
+}
+
+// timeNow returns the current time using the clock of the pool
+func (p *pool) timeNow() time.Time {
+	if p.now == nil {
+		return time.Now()
+	}
+	return p.now()
+}
+
+// reportConnectionsIdle updates the metric for the number of idle connections.
+// It is not threadsafe. The caller should manage locking the pool.
+func (p *pool) reportConnectionsIdle() {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.connectionsIdle.Set(float64(len(p.idleConnections)))
+}
+
+// evictionReason is the reason why a connection was removed from the pool
+type evictionReason string
+
+const (
+	// evictionReasonIdle the connection was idle for longer than the idle timeout
+	evictionReasonIdle evictionReason = "idle"
+	// evictionReasonLifetime the connection exceeded its maximum lifetime
+	evictionReasonLifetime evictionReason = "lifetime"
+	// evictionReasonError the connection had an error or was not connected any more
+	evictionReasonError evictionReason = "error"
+	// evictionReasonKeepaliveFailed the keepalive (ping) of the connection failed
+	evictionReasonKeepaliveFailed evictionReason = "keepalive_failed"
+)
+
+// reportConnectionCreated increments the counter of created connections in case metrics are available
+func (p *pool) reportConnectionCreated() {
+	if p.metrics == nil {
+		return
+	}
+	p.metrics.connectionsCreatedTotal.Inc()
+}
+
+// reportEviction logs the eviction of a connection and increments the according counters in case metrics are available
+func (p *pool) reportEviction(reason evictionReason) {
+	p.logger.Debug().Str("reason", string(reason)).Msg("Connection evicted from pool")
+	if p.metrics == nil {
+		return
+	}
+
+	p.metrics.connectionsEvictedTotal.WithLabelValues(string(reason)).Inc()
+	switch reason {
+	case evictionReasonIdle:
+		p.metrics.connectionsClosedIdleTotal.Inc()
+	case evictionReasonError, evictionReasonKeepaliveFailed:
+		p.metrics.connectionsClosedErrorTotal.Inc()
+	}
+}
+
 func (p *pool) LastError() error {
 	// TODO: Implement
 	return nil
@@ -123,6 +248,9 @@ func (p *pool) Get() (*pooledConnection, error) {
 	// Clean this place up.
 	p.purge()
 
+	// waitStart is the time the caller started to wait for a free connection (zero if it didn't wait)
+	var waitStart time.Time
+
 	// Wait loop
 	for {
 		p.logger.Debug().Int("active", p.active).Int("maxActive", p.maxActive).Int("idle", len(p.idleConnections)).Msg("Pool-Get")
@@ -134,8 +262,11 @@ func (p *pool) Get() (*pooledConnection, error) {
 			// Remove the connection from the idle slice
 			p.idleConnections = append(p.idleConnections[:0], p.idleConnections[1:]...)
 			p.active++
+			p.recordWait(waitStart)
+			p.reportConnectionsIdle()
+			now := p.timeNow()
 			p.mu.Unlock()
-			pc := &pooledConnection{pool: p, client: conn.pc.client}
+			pc := &pooledConnection{pool: p, client: conn.pc.client, lastActivity: now, createdAt: conn.pc.createdAt}
 			return pc, nil
 
 		}
@@ -143,6 +274,7 @@ func (p *pool) Get() (*pooledConnection, error) {
 		// No idle connections, try dialing a new one
 		if p.maxActive == 0 || p.active < p.maxActive {
 			p.active++
+			p.recordWait(waitStart)
 			createQueryExecutor := p.createQueryExecutor
 
 			// Unlock here so that any other connections that need to be
@@ -154,10 +286,12 @@ func (p *pool) Get() (*pooledConnection, error) {
 				p.mu.Lock()
 				p.release()
 				p.mu.Unlock()
-				return nil, err
+				return nil, dialError{err: err}
 			}
+			p.reportConnectionCreated()
 
-			pc := &pooledConnection{pool: p, client: dc}
+			now := p.timeNow()
+			pc := &pooledConnection{pool: p, client: dc, lastActivity: now, createdAt: now}
 			return pc, nil
 		}
 
@@ -166,11 +300,25 @@ func (p *pool) Get() (*pooledConnection, error) {
 			p.cond = sync.NewCond(&p.mu)
 		}
 
+		if waitStart.IsZero() {
+			waitStart = time.Now()
+		}
+
 		p.logger.Info().Int("active", p.active).Int("maxActive", p.maxActive).Int("idle", len(p.idleConnections)).Msg("Wait for new connections")
 		p.cond.Wait()
 	}
 }
 
+// recordWait updates the wait statistics in case the caller had to wait (waitStart is not zero).
+// It is not threadsafe. The caller should manage locking the pool.
+func (p *pool) recordWait(waitStart time.Time) {
+	if waitStart.IsZero() {
+		return
+	}
+	p.waitCount++
+	p.waitDuration += time.Since(waitStart)
+}
+
 // put pushes the supplied pooledConnection to the top of the idle slice to be reused.
 // It is not threadsafe. The caller should manage locking the pool.
 func (p *pool) put(pc *pooledConnection) {
@@ -178,24 +326,34 @@ func (p *pool) put(pc *pooledConnection) {
 		pc.client.Close()
 		return
 	}
-	idle := &idleConnection{pc: pc, idleSince: time.Now()}
+	// the connection exceeded its lifetime while it was in use --> close it now, after its queries are completed
+	if pc.isExpired(p.maxLifetime, p.timeNow()) {
+		p.logger.Info().Time("createdAt", pc.createdAt).Msg("Remove connection from pool which exceeded its lifetime")
+		pc.client.Close()
+		p.reportEviction(evictionReasonLifetime)
+		return
+	}
+	pc.lastActivity = p.timeNow()
+	idle := &idleConnection{pc: pc, idleSince: pc.lastActivity}
 	// Prepend the connection to the front of the slice
 	p.idleConnections = append([]*idleConnection{idle}, p.idleConnections...)
-
+	p.reportConnectionsIdle()
 }
 
-// purge removes expired idle connections from the pool.
+// purge removes expired idle connections and the ones that exceeded their lifetime from the pool.
 // It is not threadsafe. The caller should manage locking the pool.
 func (p *pool) purge() {
 	timeout := p.idleTimeout
 	// don't clean up in case there is no timeout specified
-	if timeout <= 0 {
+	if timeout <= 0 && p.maxLifetime <= 0 {
 		p.logger.Info().Msg("Don't purge connections, no timeout specified")
 		return
 	}
 
 	var idleConnectionsAfterPurge []*idleConnection
-	now := time.Now()
+	now := p.timeNow()
+	// open is the number of connections that are still open (in use and idle ones)
+	open := p.active + len(p.idleConnections)
 	for _, idleConnection := range p.idleConnections {
 		// If the client has an error then exclude it from the pool
 		if err := idleConnection.pc.client.LastError(); err != nil {
@@ -207,18 +365,32 @@ func (p *pool) purge() {
 
 			// Force underlying connection closed
 			idleConnection.pc.client.Close()
+			p.reportEviction(evictionReasonError)
+			open--
 			continue
 		}
 
 		// If the client is not connected any more then exclude it from the pool
 		if !idleConnection.pc.client.IsConnected() {
 			p.logger.Info().Msg("Remove connection from pool which is not connected")
+			p.reportEviction(evictionReasonError)
+			open--
+			continue
+		}
+
+		// connections that exceeded their lifetime are rotated regardless of the minimum of connections,
+		// a replacement is dialed on demand
+		if idleConnection.pc.isExpired(p.maxLifetime, now) {
+			p.logger.Info().Time("createdAt", idleConnection.pc.createdAt).Msg("Remove connection from pool which exceeded its lifetime")
+			idleConnection.pc.client.Close()
+			p.reportEviction(evictionReasonLifetime)
+			open--
 			continue
 		}
 
 		deadline := idleConnection.idleSince.Add(timeout)
-		if deadline.After(now) {
-			p.logger.Debug().Time("deadline", deadline).Msg("Keep connection which is not expired")
+		if timeout <= 0 || deadline.After(now) || open <= p.minActive {
+			p.logger.Debug().Time("deadline", deadline).Int("minActive", p.minActive).Msg("Keep connection which is not expired or needed to keep the minimum of connections")
 
 			// not expired -> keep it in the idle connection list
 			idleConnectionsAfterPurge = append(idleConnectionsAfterPurge, idleConnection)
@@ -228,9 +400,38 @@ func (p *pool) purge() {
 			// expired -> don't add it to the idle connection list
 			// Force underlying connection closed
 			idleConnection.pc.client.Close()
+			p.reportEviction(evictionReasonIdle)
+			open--
 		}
 	}
 	p.idleConnections = idleConnectionsAfterPurge
+	p.reportConnectionsIdle()
+}
+
+// prewarm eagerly dials new connections until the pool contains at least minActive open connections.
+// The new connections are added to the idle connections. In case dialing fails the error is returned,
+// the connections created so far are kept.
+// The connections are not authenticated in advance: The authentication (SASL) is initiated by the server,
+// which challenges the first request on a connection. Hence authenticating up front would require to execute
+// a query (consuming RUs) on each connection.
+func (p *pool) prewarm() error {
+	p.mu.Lock()
+	missing := p.minActive - p.active - len(p.idleConnections)
+	createQueryExecutor := p.createQueryExecutor
+	p.mu.Unlock()
+
+	for i := 0; i < missing; i++ {
+		client, err := createQueryExecutor()
+		if err != nil {
+			return err
+		}
+		p.reportConnectionCreated()
+
+		p.mu.Lock()
+		p.put(&pooledConnection{pool: p, client: client, createdAt: p.timeNow()})
+		p.mu.Unlock()
+	}
+	return nil
 }
 
 // release decrements active and alerts waiters.
@@ -261,14 +462,98 @@ func (p *pool) first() *idleConnection {
 	return p.idleConnections[0]
 }
 
+// startKeepAlive starts a go routine that pings all idle connections at the given interval in order to prevent them
+// from being closed by the peer due to inactivity. Connections whose ping fails are removed from the pool.
+// The go routine is stopped on Close.
+// Hint: The idle timeout of the pool is not affected by the pings, idle connections are still removed after the idle timeout.
+func (p *pool) startKeepAlive(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keepAliveQuit != nil || p.closed {
+		return
+	}
+	quit := make(chan struct{})
+	p.keepAliveQuit = quit
+
+	p.keepAliveWG.Add(1)
+	go func() {
+		defer p.keepAliveWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.keepAliveIdle()
+			case <-quit:
+				p.logger.Debug().Msg("Keepalive stopped")
+				return
+			}
+		}
+	}()
+}
+
+// stopKeepAlive stops the keepalive go routine and waits until it is finished
+func (p *pool) stopKeepAlive() {
+	p.mu.Lock()
+	quit := p.keepAliveQuit
+	p.keepAliveQuit = nil
+	p.mu.Unlock()
+
+	if quit == nil {
+		return
+	}
+	close(quit)
+	p.keepAliveWG.Wait()
+}
+
+// keepAliveIdle pings all idle connections, the ones whose ping fails are removed from the pool.
+func (p *pool) keepAliveIdle() {
+	// copy the idle connections to avoid holding the lock while pinging
+	p.mu.RLock()
+	idleConnectionsCopy := make([]*idleConnection, len(p.idleConnections))
+	copy(idleConnectionsCopy, p.idleConnections)
+	p.mu.RUnlock()
+
+	for _, idle := range idleConnectionsCopy {
+		err := idle.pc.client.Ping()
+		if err == nil {
+			continue
+		}
+
+		p.mu.Lock()
+		for i, current := range p.idleConnections {
+			// the connection might have been obtained by a caller in the meantime
+			if current != idle {
+				continue
+			}
+			p.idleConnections = append(p.idleConnections[:i], p.idleConnections[i+1:]...)
+			p.logger.Info().Err(err).Msg("Remove connection from pool whose keepalive ping failed")
+			idle.pc.client.Close()
+			p.reportEviction(evictionReasonKeepaliveFailed)
+			p.reportConnectionsIdle()
+			break
+		}
+		p.mu.Unlock()
+	}
+}
+
 // Close closes the pool.
 func (p *pool) Close() error {
+	p.stopKeepAlive()
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	for _, c := range p.idleConnections {
 		c.pc.client.Close()
 	}
+	p.idleConnections = nil
+	p.reportConnectionsIdle()
 
 	p.closed = true
 	return nil
@@ -276,57 +561,142 @@ func (p *pool) Close() error {
 
 // ExecuteWithBindings formats a raw Gremlin query, sends it to Gremlin Server, and returns the result.
 func (p *pool) ExecuteWithBindings(query string, bindings, rebindings map[string]interface{}) (resp []interfaces.Response, err error) {
-	pc, err := p.Get()
-	if err != nil {
-		return nil, err
-	}
-	defer pc.Close()
-	return pc.client.ExecuteWithBindings(query, bindings, rebindings)
+	return p.execute(isRetryable, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.ExecuteWithBindings(query, bindings, rebindings)
+	})
 }
 
 // Execute grabs a connection from the pool, formats a raw Gremlin query, sends it to Gremlin Server, and returns the result.
 func (p *pool) Execute(query string) (resp []interfaces.Response, err error) {
-	pc, err := p.Get()
-	if err != nil {
-		return nil, err
-	}
-	// put the connection back into the idle pool
-	defer pc.Close()
+	return p.execute(isRetryable, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.Execute(query)
+	})
+}
 
-	return pc.client.Execute(query)
+// ExecuteBatch grabs a connection from the pool and executes the given queries pipelined over this connection.
+// The batch is only retried on a fresh connection in case nothing was sent, since otherwise some of the queries
+// might have been executed already.
+func (p *pool) ExecuteBatch(queries []string) (resp [][]interfaces.Response, err error) {
+	_, err = p.execute(isNotSent, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		var errBatch error
+		resp, errBatch = client.ExecuteBatch(queries)
+		return flattenResponses(resp), errBatch
+	})
+	return resp, err
 }
 
 func (p *pool) ExecuteAsync(query string, responseChannel chan interfaces.AsyncResponse) (err error) {
+	return p.executeAsync(responseChannel, func(client interfaces.QueryExecutor, forwardChannel chan interfaces.AsyncResponse) error {
+		return client.ExecuteAsync(query, forwardChannel)
+	})
+}
+
+// executeAsync grabs a connection from the pool and issues the given asynchronous request on it.
+// The connection is put back into the idle pool after the last response was received, hence a connection
+// that exceeds its lifetime meanwhile is not closed before the responses of the query are completed.
+func (p *pool) executeAsync(responseChannel chan interfaces.AsyncResponse, request func(client interfaces.QueryExecutor, forwardChannel chan interfaces.AsyncResponse) error) error {
 	pc, err := p.Get()
 	if err != nil {
 		return err
 	}
-	// put the connection back into the idle pool
-	defer pc.Close()
 
-	return pc.client.ExecuteAsync(query, responseChannel)
+	forwardChannel := make(chan interfaces.AsyncResponse)
+	if err := request(pc.client, forwardChannel); err != nil {
+		pc.Close()
+		return err
+	}
+
+	go func() {
+		for response := range forwardChannel {
+			responseChannel <- response
+		}
+		// put the connection back into the idle pool
+		pc.Close()
+		close(responseChannel)
+	}()
+	return nil
 }
 
-func (p *pool) ExecuteFile(path string) (resp []interfaces.Response, err error) {
-	pc, err := p.Get()
-	if err != nil {
-		return nil, err
-	}
-	// put the connection back into the idle pool
-	defer pc.Close()
+// ExecuteWithID grabs a connection from the pool and executes the given query using the given request id.
+func (p *pool) ExecuteWithID(requestID, query string) (resp []interfaces.Response, err error) {
+	return p.execute(isRetryable, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.ExecuteWithID(requestID, query)
+	})
+}
+
+func (p *pool) ExecuteAsyncWithID(requestID, query string, responseChannel chan interfaces.AsyncResponse) (err error) {
+	return p.executeAsync(responseChannel, func(client interfaces.QueryExecutor, forwardChannel chan interfaces.AsyncResponse) error {
+		return client.ExecuteAsyncWithID(requestID, query, forwardChannel)
+	})
+}
 
-	return pc.client.ExecuteFile(path)
+// ExecuteFile grabs a connection from the pool and executes the script of the given file.
+// Like for ExecuteBatch the script is only retried on a fresh connection in case it was not sent at all.
+func (p *pool) ExecuteFile(path string) (resp []interfaces.Response, err error) {
+	return p.execute(isNotSent, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.ExecuteFile(path)
+	})
 }
 
+// ExecuteFileWithBindings is the same as ExecuteFile but uses the given bindings/ rebindings.
 func (p *pool) ExecuteFileWithBindings(path string, bindings, rebindings map[string]interface{}) (resp []interfaces.Response, err error) {
-	pc, err := p.Get()
-	if err != nil {
-		return nil, err
+	return p.execute(isNotSent, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.ExecuteFileWithBindings(path, bindings, rebindings)
+	})
+}
+
+// execute grabs a connection from the pool and issues the given request using this connection.
+// In case auto reconnect is enabled and the connection turns out to be broken (e.g. the socket was closed by the peer),
+// the connection is removed from the pool. The request is retried once on a fresh connection in case retryable
+// returns true for the obtained error (see isRetryable and isNotSent).
+func (p *pool) execute(retryable func(err error) bool, request func(client interfaces.QueryExecutor) ([]interfaces.Response, error)) ([]interfaces.Response, error) {
+	for attempt := 0; ; attempt++ {
+		pc, err := p.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		responses, err := request(pc.client)
+		if err == nil || !p.autoReconnect || !isConnectionBroken(pc.client, err) {
+			// put the connection back into the idle pool
+			pc.Close()
+			return responses, err
+		}
+
+		// the connection is broken, hence it must not be reused
+		pc.discard()
+		if attempt > 0 || !retryable(err) {
+			return responses, err
+		}
+		p.logger.Info().Err(err).Msg("Connection is broken, retry the query on a fresh connection")
 	}
-	// put the connection back into the idle pool
-	defer pc.Close()
+}
+
+// isRetryable returns true in case the request that failed with the given error can be sent again without the risk
+// of executing it twice. This is the case if the socket was closed by the peer or the request was not sent at all.
+// A QueryTimeoutError is never retried, since the query might still be executed by the server.
+func isRetryable(err error) bool {
+	switch errors.Cause(err).(type) {
+	case socketClosedByServerError:
+		return true
+	case QueryTimeoutError:
+		return false
+	}
+	return isNotSent(err)
+}
+
+// isNotSent returns true in case the request that failed with the given error was not written to the socket at all
+func isNotSent(err error) bool {
+	return errors.Cause(err) == errNoConnection
+}
 
-	return pc.client.ExecuteFileWithBindings(path, bindings, rebindings)
+// isConnectionBroken returns true in case the given error or the state of the given client indicates
+// that the underlying connection is broken, e.g. since the socket was closed by the peer.
+func isConnectionBroken(client interfaces.QueryExecutor, err error) bool {
+	if _, ok := errors.Cause(err).(socketClosedByServerError); ok {
+		return true
+	}
+	return client.LastError() != nil || !client.IsConnected()
 }
 
 // Close signals that the caller is finished with the connection and should be
@@ -339,6 +709,17 @@ func (pc *pooledConnection) Close() {
 	pc.pool.release()
 }
 
+// discard removes the (broken) connection from the pool instead of returning it to the idle connections.
+func (pc *pooledConnection) discard() {
+	pc.client.Close()
+
+	pc.pool.mu.Lock()
+	defer pc.pool.mu.Unlock()
+
+	pc.pool.reportEviction(evictionReasonError)
+	pc.pool.release()
+}
+
 // Ping obtains/ creates a connection from the pool and
 // sends the ping control message over the underlying websocket.
 func (p *pool) Ping() error {
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/supplyon/gremcos/interfaces"
 	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
+	mock_metrics "github.com/supplyon/gremcos/test/mocks/metrics"
 )
 
 func TestIsConnectedRace(t *testing.T) {
@@ -320,6 +323,208 @@ func TestNoPurge(t *testing.T) {
 	assert.Len(t, p.idleConnections, 1, "Expected 1 idle connection after purge")
 	assert.Equal(t, valid.idleSince, p.idleConnections[0].idleSince, "Expected the valid connection to remain in idle pool")
 }
+
+func TestPurgeKeepsMinActive(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedQueryExecutor1 := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	mockedQueryExecutor2 := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+
+	n := time.Now()
+	// both connections have timed out, but only one of them may be cleaned up
+	expired1 := &idleConnection{idleSince: n.Add(-60 * time.Second), pc: &pooledConnection{client: mockedQueryExecutor1}}
+	expired2 := &idleConnection{idleSince: n.Add(-60 * time.Second), pc: &pooledConnection{client: mockedQueryExecutor2}}
+	p := &pool{idleTimeout: time.Second * 30, minActive: 1, idleConnections: []*idleConnection{expired1, expired2}}
+
+	// WHEN
+	mockedQueryExecutor1.EXPECT().LastError().Return(nil)
+	mockedQueryExecutor1.EXPECT().IsConnected().Return(true)
+	mockedQueryExecutor1.EXPECT().Close()
+	mockedQueryExecutor2.EXPECT().LastError().Return(nil)
+	mockedQueryExecutor2.EXPECT().IsConnected().Return(true)
+	p.purge()
+
+	// THEN
+	require.Len(t, p.idleConnections, 1, "Expected the minimum of connections to remain in idle pool")
+	assert.Equal(t, expired2, p.idleConnections[0])
+}
+
+func TestConnectionLifecycleMetrics(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) { return mockedQueryExecutor, nil }, 10, time.Second*30, zerolog.Nop())
+	require.NoError(t, err)
+	pool.metrics = metrics
+
+	// fake clock that can be advanced by the test
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+
+	metricMocks.connectionsCreatedTotal.EXPECT().Inc().Times(1)
+	// purge on Get (no idle connections) and Close (one idle connection)
+	metricMocks.connectionsIdle.EXPECT().Set(float64(0))
+	metricMocks.connectionsIdle.EXPECT().Set(float64(1))
+	pc, err := pool.Get()
+	require.NoError(t, err)
+	pc.Close()
+	assert.Equal(t, now, pc.lastActivity)
+
+	// WHEN
+	// advance the clock past the idle timeout
+	now = now.Add(time.Second * 31)
+	mockedQueryExecutor.EXPECT().LastError().Return(nil)
+	mockedQueryExecutor.EXPECT().IsConnected().Return(true)
+	mockedQueryExecutor.EXPECT().Close()
+	metricMocks.connectionsClosedIdleTotal.EXPECT().Inc().Times(1)
+	mockCountEvictedIdle := mock_metrics.NewMockCounter(mockCtrl)
+	mockCountEvictedIdle.EXPECT().Inc().Times(1)
+	metricMocks.connectionsEvictedTotal.EXPECT().WithLabelValues("idle").Return(mockCountEvictedIdle)
+	metricMocks.connectionsIdle.EXPECT().Set(float64(0))
+	pool.mu.Lock()
+	pool.purge()
+	pool.mu.Unlock()
+
+	// THEN
+	assert.Empty(t, pool.idleConnections)
+}
+
+func TestConnectionLifecycleMetricsClosedByError(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	mockedQueryExecutorErrored := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	mockedQueryExecutorDisconnected := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	p := &pool{idleTimeout: time.Second * 30, metrics: metrics, idleConnections: []*idleConnection{
+		{idleSince: time.Now(), pc: &pooledConnection{client: mockedQueryExecutorErrored}},
+		{idleSince: time.Now(), pc: &pooledConnection{client: mockedQueryExecutorDisconnected}},
+	}}
+
+	// WHEN
+	mockedQueryExecutorErrored.EXPECT().LastError().Return(fmt.Errorf("broken pipe"))
+	mockedQueryExecutorErrored.EXPECT().Close()
+	mockedQueryExecutorDisconnected.EXPECT().LastError().Return(nil)
+	mockedQueryExecutorDisconnected.EXPECT().IsConnected().Return(false)
+	metricMocks.connectionsClosedErrorTotal.EXPECT().Inc().Times(2)
+	mockCountEvictedError := mock_metrics.NewMockCounter(mockCtrl)
+	mockCountEvictedError.EXPECT().Inc().Times(2)
+	metricMocks.connectionsEvictedTotal.EXPECT().WithLabelValues("error").Return(mockCountEvictedError).Times(2)
+	metricMocks.connectionsIdle.EXPECT().Set(float64(0))
+	p.purge()
+
+	// THEN
+	assert.Empty(t, p.idleConnections)
+}
+
+func TestConnMaxLifetimeRotation(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	first := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	second := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	executors := []interfaces.QueryExecutor{first, second}
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) {
+		executor := executors[0]
+		executors = executors[1:]
+		return executor, nil
+	}, 10, 0, zerolog.Nop())
+	require.NoError(t, err)
+	pool.maxLifetime = time.Minute
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	first.EXPECT().LastError().Return(nil).AnyTimes()
+	first.EXPECT().IsConnected().Return(true).AnyTimes()
+
+	// WHEN
+	pc, err := pool.Get()
+	require.NoError(t, err)
+	pc.Close()
+	now = now.Add(time.Second * 30)
+	pcBeforeLifetime, err := pool.Get()
+	require.NoError(t, err)
+	pcBeforeLifetime.Close()
+	now = now.Add(time.Second * 30)
+	first.EXPECT().Close()
+	pcAfterLifetime, err := pool.Get()
+	require.NoError(t, err)
+
+	// THEN
+	assert.Equal(t, first, pcBeforeLifetime.client, "the connection has to be reused within its lifetime")
+	assert.Equal(t, second, pcAfterLifetime.client, "the connection has to be replaced after its lifetime")
+	assert.Equal(t, now, pcAfterLifetime.createdAt)
+	assert.Equal(t, 1, pool.Stats().Active)
+}
+
+func TestConnMaxLifetimeInUse(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) { return mockedQueryExecutor, nil }, 10, time.Second*30, zerolog.Nop())
+	require.NoError(t, err)
+	pool.maxLifetime = time.Minute
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	pc, err := pool.Get()
+	require.NoError(t, err)
+
+	// WHEN
+	// the lifetime expires while the connection is in use
+	now = now.Add(time.Minute * 2)
+	statsInUse := pool.Stats()
+	mockedQueryExecutor.EXPECT().Close()
+	pc.Close()
+
+	// THEN
+	assert.Equal(t, 1, statsInUse.InUse, "the connection must not be closed while it is in use")
+	assert.Equal(t, PoolStats{Max: 10}, pool.Stats(), "the connection has to be closed as soon as it was given back")
+}
+
+func TestConnMaxLifetimeAsyncQuery(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) { return mockedQueryExecutor, nil }, 10, time.Second*30, zerolog.Nop())
+	require.NoError(t, err)
+	pool.maxLifetime = time.Minute
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool.now = func() time.Time { return now }
+	var forwardChannel chan interfaces.AsyncResponse
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.V()", gomock.Any()).DoAndReturn(func(query string, responseChannel chan interfaces.AsyncResponse) error {
+		forwardChannel = responseChannel
+		return nil
+	})
+	responseChannel := make(chan interfaces.AsyncResponse)
+
+	// WHEN
+	err = pool.ExecuteAsync("g.V()", responseChannel)
+	require.NoError(t, err)
+	// the lifetime expires while the responses are streamed
+	now = now.Add(time.Minute * 2)
+	forwardChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "1"}}
+	firstChunk := <-responseChannel
+	statsStreaming := pool.Stats()
+	mockedQueryExecutor.EXPECT().Close()
+	go func() {
+		forwardChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "1"}}
+		close(forwardChannel)
+	}()
+	var remainingChunks []interfaces.AsyncResponse
+	for response := range responseChannel {
+		remainingChunks = append(remainingChunks, response)
+	}
+
+	// THEN
+	assert.Equal(t, "1", firstChunk.Response.RequestID)
+	assert.Len(t, remainingChunks, 1, "the stream must not be cut off")
+	assert.Equal(t, 1, statsStreaming.InUse, "the connection must not be closed while the responses are streamed")
+	assert.Equal(t, PoolStats{Max: 10}, pool.Stats(), "the connection has to be closed as soon as the stream is completed")
+}
 func TestPurgeOnErroredConnection(t *testing.T) {
 	// GIVEN
 	mockCtrl := gomock.NewController(t)
@@ -477,3 +682,272 @@ func newMockedPool(mockCtrl *gomock.Controller) (*mock_interfaces.MockQueryExecu
 	pool, err := NewPool(clientFactory, 2, time.Second*30, logger)
 	return mockedQueryExecutor, pool, err
 }
+
+func TestStats(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) { return mockedQueryExecutor, nil }, 2, 0, zerolog.Nop())
+	require.NoError(t, err)
+
+	// WHEN
+	// drive the pool to its capacity
+	pc1, err := pool.Get()
+	require.NoError(t, err)
+	_, err = pool.Get()
+	require.NoError(t, err)
+	statsAtCapacity := pool.Stats()
+
+	// a third caller has to wait until a connection is given back
+	got := make(chan *pooledConnection)
+	go func() {
+		pc, err := pool.Get()
+		assert.NoError(t, err)
+		got <- pc
+	}()
+	time.Sleep(time.Millisecond * 20)
+	pc1.Close()
+	<-got
+	statsAfterWait := pool.Stats()
+
+	// THEN
+	assert.Equal(t, PoolStats{Active: 2, Idle: 0, InUse: 2, Max: 2}, statsAtCapacity)
+	assert.Equal(t, 2, statsAfterWait.Active)
+	assert.Equal(t, 2, statsAfterWait.InUse)
+	assert.Equal(t, 0, statsAfterWait.Idle)
+	assert.Equal(t, 2, statsAfterWait.Max)
+	assert.Equal(t, int64(1), statsAfterWait.WaitCount)
+	assert.True(t, statsAfterWait.WaitDuration >= time.Millisecond*10, "wait duration %v", statsAfterWait.WaitDuration)
+}
+
+func TestStatsIdle(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) { return mockedQueryExecutor, nil }, 10, 0, zerolog.Nop())
+	require.NoError(t, err)
+
+	// WHEN
+	pc1, err := pool.Get()
+	require.NoError(t, err)
+	_, err = pool.Get()
+	require.NoError(t, err)
+	pc1.Close()
+
+	// THEN
+	assert.Equal(t, PoolStats{Active: 2, Idle: 1, InUse: 1, Max: 10}, pool.Stats())
+}
+
+// newPoolWithConnections returns a pool that creates the given query executors (one per connection)
+func newPoolWithConnections(t *testing.T, queryExecutors ...interfaces.QueryExecutor) *pool {
+	created := 0
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) {
+		queryExecutor := queryExecutors[created]
+		created++
+		return queryExecutor, nil
+	}, 10, time.Second*30, zerolog.Nop())
+	require.NoError(t, err)
+	return pool
+}
+
+func TestAutoReconnect(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	brokenConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	freshConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, brokenConnection, freshConnection)
+	pool.autoReconnect = true
+	query := "g.V()"
+	success := []interfaces.Response{{RequestID: "ok", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+
+	brokenConnection.EXPECT().Execute(query).Return(nil, errors.Wrap(socketClosedByServerError{}, "query: g.V()"))
+	brokenConnection.EXPECT().Close()
+	freshConnection.EXPECT().Execute(query).Return(success, nil)
+
+	// WHEN
+	responses, err := pool.Execute(query)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, success, responses)
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Active, "the broken connection has to be removed from the pool")
+	assert.Equal(t, 1, stats.Idle)
+}
+
+func TestAutoReconnectRetriesOnlyOnce(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	brokenConnection1 := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	brokenConnection2 := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, brokenConnection1, brokenConnection2)
+	pool.autoReconnect = true
+	query := "g.V()"
+	connectionErr := fmt.Errorf("broken pipe")
+
+	brokenConnection1.EXPECT().Execute(query).Return(nil, socketClosedByServerError{})
+	brokenConnection1.EXPECT().Close()
+	brokenConnection2.EXPECT().Execute(query).Return(nil, connectionErr)
+	brokenConnection2.EXPECT().LastError().Return(nil)
+	brokenConnection2.EXPECT().IsConnected().Return(false)
+	brokenConnection2.EXPECT().Close()
+
+	// WHEN
+	_, err := pool.Execute(query)
+
+	// THEN
+	assert.Equal(t, connectionErr, err)
+	assert.Equal(t, 0, pool.Stats().Active)
+}
+
+func TestNoAutoReconnectOnQueryTimeout(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	timedOutConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	unusedConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, timedOutConnection, unusedConnection)
+	pool.autoReconnect = true
+	query := "g.addV('user')"
+	timeoutErr := errors.Wrap(QueryTimeoutError{RequestID: "abc", Timeout: time.Second}, "query: g.addV('user')")
+
+	// the client is closed after a timeout, but the query might still be executed by the server
+	timedOutConnection.EXPECT().Execute(query).Return(nil, timeoutErr)
+	timedOutConnection.EXPECT().LastError().Return(timeoutErr)
+	timedOutConnection.EXPECT().Close()
+
+	// WHEN
+	_, err := pool.Execute(query)
+
+	// THEN
+	assert.Equal(t, timeoutErr, err)
+	assert.Equal(t, 0, pool.Stats().Active, "the timed out connection has to be removed from the pool")
+}
+
+func TestAutoReconnectBatch(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	brokenConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	disconnectedConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	freshConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, brokenConnection, disconnectedConnection, freshConnection)
+	pool.autoReconnect = true
+	queries := []string{"g.addV('a')", "g.addV('b')"}
+	closedErr := errors.Wrap(socketClosedByServerError{}, "query 1: g.addV('b')")
+	success := [][]interfaces.Response{{{RequestID: "a", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, {{RequestID: "b", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}}
+
+	// the socket breaks after the batch was sent, the batch must not be sent again
+	brokenConnection.EXPECT().ExecuteBatch(queries).Return(nil, closedErr)
+	brokenConnection.EXPECT().Close()
+	// nothing was sent, hence the batch is retried
+	disconnectedConnection.EXPECT().ExecuteBatch(queries).Return(nil, errNoConnection)
+	disconnectedConnection.EXPECT().LastError().Return(nil)
+	disconnectedConnection.EXPECT().IsConnected().Return(false)
+	disconnectedConnection.EXPECT().Close()
+	freshConnection.EXPECT().ExecuteBatch(queries).Return(success, nil)
+
+	// WHEN
+	_, errBroken := pool.ExecuteBatch(queries)
+	responses, errRetried := pool.ExecuteBatch(queries)
+
+	// THEN
+	assert.Equal(t, closedErr, errBroken)
+	require.NoError(t, errRetried)
+	assert.Equal(t, success, responses)
+}
+
+func TestNoAutoReconnectOnQueryError(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	connection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, connection)
+	pool.autoReconnect = true
+	query := "g.V()"
+	queryErr := fmt.Errorf("invalid query")
+
+	// the connection is healthy, hence the query must not be retried
+	connection.EXPECT().Execute(query).Return(nil, queryErr)
+	connection.EXPECT().LastError().Return(nil)
+	connection.EXPECT().IsConnected().Return(true)
+
+	// WHEN
+	_, err := pool.Execute(query)
+
+	// THEN
+	assert.Equal(t, queryErr, err)
+	assert.Equal(t, 1, pool.Stats().Idle)
+}
+
+func TestAutoReconnectDisabled(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	brokenConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, brokenConnection)
+	query := "g.V()"
+	closedErr := socketClosedByServerError{}
+
+	brokenConnection.EXPECT().Execute(query).Return(nil, closedErr)
+
+	// WHEN
+	_, err := pool.Execute(query)
+
+	// THEN
+	assert.Equal(t, closedErr, err)
+}
+
+func TestKeepAlive(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	idleConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, idleConnection)
+	pc, err := pool.Get()
+	require.NoError(t, err)
+	pc.Close()
+
+	interval := time.Millisecond * 20
+	var pings int32
+	idleConnection.EXPECT().Ping().DoAndReturn(func() error {
+		atomic.AddInt32(&pings, 1)
+		return nil
+	}).AnyTimes()
+
+	// WHEN
+	pool.startKeepAlive(interval)
+	time.Sleep(interval*5 + interval/2)
+	idleConnection.EXPECT().Close()
+	require.NoError(t, pool.Close())
+	pingsOnClose := atomic.LoadInt32(&pings)
+	time.Sleep(interval * 3)
+
+	// THEN
+	assert.True(t, pingsOnClose >= 3 && pingsOnClose <= 6, "expected about 5 pings but got %d", pingsOnClose)
+	assert.Equal(t, pingsOnClose, atomic.LoadInt32(&pings), "no pings are expected after the pool was closed")
+}
+
+func TestKeepAliveRemovesFailingConnection(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	brokenConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	pool := newPoolWithConnections(t, brokenConnection)
+	pc, err := pool.Get()
+	require.NoError(t, err)
+	pc.Close()
+
+	brokenConnection.EXPECT().Ping().Return(fmt.Errorf("Not connected"))
+	brokenConnection.EXPECT().Close()
+
+	// WHEN
+	pool.keepAliveIdle()
+
+	// THEN
+	assert.Equal(t, 0, pool.Stats().Idle)
+}
@@ -0,0 +1,137 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/supplyon/gremcos/api"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// regexpMutatingStep matches all steps that modify the graph, including the ones of (bare) anonymous traversals
+// like coalesce(unfold(),addV("user")) or sideEffect(property("x",1))
+var regexpMutatingStep = regexp.MustCompile(`(^|[.(,\s])\s*(addV|addE|property|drop|mergeV|mergeE)\s*\(`)
+
+// regexpStringLiteral matches the quoted string literals of a query
+var regexpStringLiteral = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// regexpProfileStep matches the steps that are used for profiling
+var regexpProfileStep = regexp.MustCompile(`\.\s*(executionProfile|profile)\s*\(`)
+
+// isReadQuery returns true in case the given query does not modify the graph and is not already profiled.
+// The string literals are ignored, hence a value like "drop()" doesn't turn a query into a mutating one.
+func isReadQuery(query string) bool {
+	query = regexpStringLiteral.ReplaceAllString(query, `""`)
+	return !regexpMutatingStep.MatchString(query) && !regexpProfileStep.MatchString(query)
+}
+
+// profileStep returns the step to profile a query for the query language in use.
+func profileStep() string {
+	if api.QueryLanguageInUse() == api.QueryLanguageCosmosDB {
+		return ".executionProfile()"
+	}
+	return ".profile()"
+}
+
+// CostEstimate is the summary of a profiled query, see EstimateCost
+type CostEstimate struct {
+	// RequestCharge is the request charge (RU) of the profiled query as provided by the CosmosDB (0 for other backends)
+	RequestCharge float64
+	// Duration is the total time the server spent to execute the query
+	Duration time.Duration
+	// ResultCount is the number of results produced by the last step of the query
+	ResultCount int64
+	// Profiles are the parsed profiles including the metrics of each step
+	Profiles []api.Profile
+}
+
+// EstimateCost executes the given (read) query wrapped with the profile step of the query language in use
+// (.executionProfile() for the CosmosDB and .profile() for TinkerPop) and returns the summary of the obtained profile.
+// Queries that modify the graph or are already profiled are rejected, since the profiled query is actually executed.
+// The mutating steps (e.g. addV, property, drop) are detected in the whole query, including nested anonymous traversals
+// like coalesce(unfold(),addV("user")). Steps hidden in string literals or lambdas are not detected.
+func (c *cosmosImpl) EstimateCost(query string) (CostEstimate, error) {
+	if !isReadQuery(query) {
+		return CostEstimate{}, fmt.Errorf("Only read queries can be profiled, the query modifies the graph or is already profiled")
+	}
+
+	profileQuery := strings.TrimRight(strings.TrimSpace(query), ";") + profileStep()
+	responses, err := c.Execute(profileQuery)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+
+	estimate := CostEstimate{RequestCharge: TotalRequestCharge(responses)}
+	for _, response := range responses {
+		if response.IsEmpty() {
+			continue
+		}
+
+		profiles, err := api.ToProfiles(response.Result.Data)
+		if err != nil {
+			return CostEstimate{}, err
+		}
+
+		for _, profile := range profiles {
+			estimate.Duration += profile.Duration
+			if len(profile.Metrics) > 0 {
+				estimate.ResultCount += profile.Metrics[len(profile.Metrics)-1].Count
+			}
+		}
+		estimate.Profiles = append(estimate.Profiles, profiles...)
+	}
+
+	if len(estimate.Profiles) == 0 {
+		return estimate, fmt.Errorf("No profile was returned for the query")
+	}
+	return estimate, nil
+}
+
+// profile executes the given query wrapped with the profile step of the query language in use
+// and logs the obtained step timings on debug level.
+// Profiling is skipped for queries that modify the graph and in case the logger is not set to debug level.
+// Like any other query the profile query is subject to the concurrency limit and the circuit breaker.
+func (c *cosmosImpl) profile(query string) {
+	if c.logger.GetLevel() > zerolog.DebugLevel || !isReadQuery(query) {
+		return
+	}
+
+	profileQuery := strings.TrimRight(strings.TrimSpace(query), ";") + profileStep()
+	done, err := c.beginQuery(profileQuery)
+	if err != nil {
+		c.logger.Debug().Err(err).Str("query", c.sanitizeQuery(query)).Msg("Profiling query skipped")
+		return
+	}
+	defer done()
+
+	responses, err := c.executeWithRetry(context.Background(), func() ([]interfaces.Response, error) {
+		return c.pool.Execute(profileQuery)
+	})
+	if err != nil {
+		c.logger.Debug().Err(err).Str("query", c.sanitizeQuery(query)).Msg("Profiling query failed")
+		return
+	}
+
+	for _, response := range responses {
+		if response.IsEmpty() {
+			continue
+		}
+
+		profiles, err := api.ToProfiles(response.Result.Data)
+		if err != nil {
+			c.logger.Debug().Err(err).Str("query", c.sanitizeQuery(query)).Msg("Parsing profile of query failed")
+			return
+		}
+
+		for _, profile := range profiles {
+			c.logger.Debug().Str("query", c.sanitizeQuery(query)).Dur("duration", profile.Duration).Msg("Profiled query")
+			for _, metric := range profile.Metrics {
+				c.logger.Debug().Str("query", c.sanitizeQuery(query)).Str("step", metric.Name).Dur("duration", metric.Duration).Int64("count", metric.Count).Msg("Profiled step")
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package gremcos
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/api"
+	"github.com/supplyon/gremcos/interfaces"
+	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
+	mock_metrics "github.com/supplyon/gremcos/test/mocks/metrics"
+)
+
+const dataExecutionProfile = `[{"gremlin":"g.V().hasLabel('user')","totalTime":12,"metrics":[
+	{"name":"GetVertices","time":10,"counts":{"resultCount":3}},
+	{"name":"ProjectOperator","time":2,"counts":{"resultCount":3}}
+]}]`
+
+func expectAnyMetricUpdates(mockCtrl *gomock.Controller, metricMocks *MetricsMocks) {
+	mockCount200 := mock_metrics.NewMockCounter(mockCtrl)
+	mockCount200.EXPECT().Inc().AnyTimes()
+	metricMocks.statusCodeTotal.EXPECT().WithLabelValues(gomock.Any()).Return(mockCount200).AnyTimes()
+	metricMocks.serverTimePerQueryResponseAvgMS.EXPECT().Set(gomock.Any()).AnyTimes()
+	metricMocks.serverTimePerQueryMS.EXPECT().Set(gomock.Any()).AnyTimes()
+	metricMocks.requestChargePerQueryResponseAvg.EXPECT().Set(gomock.Any()).AnyTimes()
+	metricMocks.requestChargePerQuery.EXPECT().Set(gomock.Any()).AnyTimes()
+	metricMocks.requestChargeTotal.EXPECT().Add(gomock.Any()).AnyTimes()
+	metricMocks.retryAfterMS.EXPECT().Set(gomock.Any()).AnyTimes()
+	metricMocks.requestUnitsTotal.EXPECT().Add(gomock.Any()).AnyTimes()
+	metricMocks.queryDurationSeconds.EXPECT().Observe(gomock.Any()).AnyTimes()
+	metricMocks.connectionsCreatedTotal.EXPECT().Inc().AnyTimes()
+	metricMocks.connectionsClosedIdleTotal.EXPECT().Inc().AnyTimes()
+	metricMocks.connectionsClosedErrorTotal.EXPECT().Inc().AnyTimes()
+	metricMocks.connectionsIdle.EXPECT().Set(gomock.Any()).AnyTimes()
+	mockCountEvicted := mock_metrics.NewMockCounter(mockCtrl)
+	mockCountEvicted.EXPECT().Inc().AnyTimes()
+	metricMocks.connectionsEvictedTotal.EXPECT().WithLabelValues(gomock.Any()).Return(mockCountEvicted).AnyTimes()
+}
+
+func TestIsReadQuery(t *testing.T) {
+	assert.True(t, isReadQuery(`g.V().hasLabel('user').values('name')`))
+	assert.True(t, isReadQuery(`g.V().has('name','property(x)')`))
+	assert.False(t, isReadQuery(`g.addV('user')`))
+	assert.False(t, isReadQuery(`g.V('1').property('name','hans')`))
+	assert.False(t, isReadQuery(`g.V('1').drop()`))
+	assert.False(t, isReadQuery(`g.V().executionProfile()`))
+	assert.False(t, isReadQuery(`g.V().profile()`))
+}
+
+func TestIsReadQueryAnonymousTraversals(t *testing.T) {
+	// GIVEN
+	g := api.NewGraph("g")
+	api.SetQueryLanguageTo(api.QueryLanguageTinkerpopGremlin)
+	defer api.SetQueryLanguageTo(api.QueryLanguageCosmosDB)
+
+	upsert := g.V().UpsertV(api.NewSimpleQB(`.has("name","hans")`), api.Underscore().Add(api.NewSimpleQB(`addV("user")`))).String()
+	upsertBare := api.NewVertexG(g).UpsertV(nil, api.NewSimpleQB(`addV("user").property("name","hans")`)).String()
+	increment := g.VByStr("1").IncrementProperty("views", 1).String()
+	sideEffectDrop := g.V().SideEffect(api.Underscore().Properties("x").Drop()).String()
+	sideEffectRead := g.V().SideEffect(api.Underscore().Properties("name")).String()
+
+	// WHEN + THEN
+	assert.False(t, isReadQuery(upsert), upsert)
+	assert.False(t, isReadQuery(upsertBare), upsertBare)
+	assert.False(t, isReadQuery(increment), increment)
+	assert.False(t, isReadQuery(sideEffectDrop), sideEffectDrop)
+	assert.False(t, isReadQuery(`g.V().coalesce(unfold(), addE("knows"))`))
+	assert.True(t, isReadQuery(sideEffectRead), sideEffectRead)
+	assert.True(t, isReadQuery(`g.V().has("name","a,drop()")`))
+}
+
+func TestAutoProfile(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	logBuffer := &bytes.Buffer{}
+	logger := zerolog.New(logBuffer).Level(zerolog.DebugLevel)
+	cosmos, err := New("ws://host", WithAutoProfile(true), WithLogger(logger), withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+
+	query := `g.V().hasLabel('user')`
+	data := []byte(`[{"id":"1","label":"user"}]`)
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: data}}}, nil)
+	mockedQueryExecutor.EXPECT().Execute(query+".executionProfile()").Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(dataExecutionProfile)}}}, nil)
+
+	// WHEN
+	responses, err := cosmos.Execute(query)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	assert.Equal(t, data, []byte(responses[0].Result.Data))
+	assert.Contains(t, logBuffer.String(), "GetVertices")
+	assert.Contains(t, logBuffer.String(), "ProjectOperator")
+}
+
+func TestAutoProfileSkipped(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	logBuffer := &bytes.Buffer{}
+	logger := zerolog.New(logBuffer).Level(zerolog.InfoLevel)
+	cosmos, err := New("ws://host", WithAutoProfile(true), WithLogger(logger), withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+
+	readQuery := `g.V().hasLabel('user')`
+	writeQuery := `g.addV('user')`
+	// only executed once, no profiling query
+	mockedQueryExecutor.EXPECT().Execute(readQuery).Return(nil, nil)
+	mockedQueryExecutor.EXPECT().Execute(writeQuery).Return(nil, nil)
+
+	// WHEN
+	_, errRead := cosmos.Execute(readQuery)
+	cImpl.logger = logger.Level(zerolog.DebugLevel)
+	_, errWrite := cosmos.Execute(writeQuery)
+
+	// THEN
+	assert.NoError(t, errRead)
+	assert.NoError(t, errWrite)
+	assert.Empty(t, logBuffer.String())
+}
+
+func TestAutoProfileLimitedAndGuarded(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	logger := zerolog.New(&bytes.Buffer{}).Level(zerolog.DebugLevel)
+	cosmos, err := New("ws://host", WithAutoProfile(true), WithLogger(logger), withMetrics(metrics),
+		WithMaxConcurrentRequests(1, ConcurrencyLimitFail), WithCircuitBreaker(1, time.Minute))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+
+	query := `g.V().hasLabel('user')`
+	var numInFlightWhileProfiling int32
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+	mockedQueryExecutor.EXPECT().Execute(query+".executionProfile()").DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		numInFlightWhileProfiling = atomic.LoadInt32(&cImpl.numInFlight)
+		return nil, dialError{err: fmt.Errorf("connection refused")}
+	})
+
+	// WHEN
+	_, errProfiled := cosmos.Execute(query)
+	// the failed profile query opened the circuit breaker, thus the query is not sent
+	_, errOpen := cosmos.Execute(query)
+
+	// THEN
+	assert.NoError(t, errProfiled)
+	assert.Equal(t, int32(1), numInFlightWhileProfiling)
+	assert.Equal(t, ErrCircuitOpen, errOpen)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&cImpl.numInFlight))
+}
+
+func TestEstimateCost(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	// executionProfile as returned by the CosmosDB
+	data := []byte(`[{"gremlin":"g.V().hasLabel('user').out()","activityId":"a1c9d5d0-6e0e-4f5b-b1c4-8b4f2bfc1a2e","totalTime":21,"metrics":[
+		{"name":"GetVertices","time":12,"annotations":{"percentTime":57.14},"counts":{"resultCount":4},"storeOps":[{"fanoutFactor":1,"count":4,"size":2048,"time":11.2}]},
+		{"name":"GetEdges","time":7,"annotations":{"percentTime":33.33},"counts":{"resultCount":6},"storeOps":[{"fanoutFactor":1,"count":6,"size":1024,"time":6.1}]},
+		{"name":"ProjectOperator","time":2,"annotations":{"percentTime":9.52},"counts":{"resultCount":6}}
+	]}]`)
+	responses := []interfaces.Response{{
+		Status: interfaces.Status{Code: interfaces.StatusSuccess, Attributes: map[string]interface{}{"x-ms-total-request-charge": 12.4}},
+		Result: interfaces.Result{Data: data},
+	}}
+	mockedQueryExecutor.EXPECT().Execute(`g.V().hasLabel('user').out().executionProfile()`).Return(responses, nil)
+
+	// WHEN
+	estimate, err := cosmos.EstimateCost(`g.V().hasLabel('user').out();`)
+	_, errMutating := cosmos.EstimateCost(`g.addV('user')`)
+	_, errProfiled := cosmos.EstimateCost(`g.V().executionProfile()`)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, 12.4, estimate.RequestCharge)
+	assert.Equal(t, 21*time.Millisecond, estimate.Duration)
+	assert.Equal(t, int64(6), estimate.ResultCount)
+	require.Len(t, estimate.Profiles, 1)
+	require.Len(t, estimate.Profiles[0].Metrics, 3)
+	assert.Equal(t, api.ProfileMetric{Name: "GetVertices", Duration: 12 * time.Millisecond, Count: 4}, estimate.Profiles[0].Metrics[0])
+	assert.Error(t, errMutating)
+	assert.Error(t, errProfiled)
+}
+
+func TestEstimateCostFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	noProfile := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}, Result: interfaces.Result{Data: []byte(`[{"id":"1"}]`)}}}
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Execute("g.V().executionProfile()").Return(nil, fmt.Errorf("connection lost")),
+		mockedQueryExecutor.EXPECT().Execute("g.V().executionProfile()").Return(noProfile, nil),
+	)
+
+	// WHEN
+	_, errExecute := cosmos.EstimateCost("g.V()")
+	_, errParse := cosmos.EstimateCost("g.V()")
+
+	// THEN
+	assert.Error(t, errExecute)
+	assert.Error(t, errParse)
+}
+
+func TestEstimateCostRejectsNestedMutations(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	// no query is expected to be executed
+	cosmos, _ := newCosmosWithMockedPool(t, mockCtrl)
+	g := api.NewGraph("g")
+	upsert := api.NewVertexG(g).UpsertV(api.NewSimpleQB(`.V().has("name","hans")`), api.NewSimpleQB(`addV("user").property("name","hans")`)).String()
+	api.SetQueryLanguageTo(api.QueryLanguageTinkerpopGremlin)
+	increment := g.VByStr("1").IncrementProperty("views", 1).String()
+	api.SetQueryLanguageTo(api.QueryLanguageCosmosDB)
+
+	// WHEN
+	_, errUpsert := cosmos.EstimateCost(upsert)
+	_, errIncrement := cosmos.EstimateCost(increment)
+
+	// THEN
+	assert.Error(t, errUpsert)
+	assert.Error(t, errIncrement)
+}
@@ -0,0 +1,172 @@
+package gremcos
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// QueryRecord is an entry of the query history. It contains information about an executed query.
+type QueryRecord struct {
+	// Query is the executed query with redacted (string) values
+	Query string
+	// RequestID is the id of the request that was used to execute the query
+	RequestID string
+	// Start is the time when the query was issued
+	Start time.Time
+	// Duration is the time it took to execute the query and to retrieve all of its responses
+	Duration time.Duration
+	// StatusCode is the (most specific) status code of the last response
+	StatusCode int
+	// RequestCharge is the total request charge (RU) of the query
+	RequestCharge float32
+	// Error is the error message in case the query failed
+	Error string
+}
+
+// queryHistory is a ring buffer that keeps the last n query records
+type queryHistory struct {
+	mux     sync.Mutex
+	records []QueryRecord
+	next    int
+	full    bool
+}
+
+func newQueryHistory(size int) *queryHistory {
+	return &queryHistory{
+		records: make([]QueryRecord, size),
+	}
+}
+
+// add adds the given record, overwriting the oldest one in case the history is full
+func (h *queryHistory) add(record QueryRecord) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns a copy of all records, the oldest first
+func (h *queryHistory) list() []QueryRecord {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if !h.full {
+		result := make([]QueryRecord, h.next)
+		copy(result, h.records[:h.next])
+		return result
+	}
+
+	result := make([]QueryRecord, 0, len(h.records))
+	result = append(result, h.records[h.next:]...)
+	result = append(result, h.records[:h.next]...)
+	return result
+}
+
+// regexpQuotedLiteral matches all double or single quoted strings
+var regexpQuotedLiteral = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// regexpPropertyKey matches the has and property steps starting with a quoted property key. The key is the
+// first parameter (after an optional cardinality) e.g. .has("name","hans") or .property(list,"name","hans").
+// In case of .has("user","name","hans") the first parameter is the label and the key is captured as second group.
+var regexpPropertyKey = regexp.MustCompile(`(?:^|[.(,\s])(?:has|property)\(\s*(?:(?:list|set|single)\s*,\s*)?("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')(?:\s*,\s*("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')\s*,)?`)
+
+// RedactValues replaces all quoted strings in the given query by "***", except for the property keys of has and
+// property steps. The structure of the query is kept, e.g. g.V("1").has("name","hans") becomes g.V("***").has("name","***").
+// It can be used as sanitizer for WithQueryLogger.
+func RedactValues(query string) string {
+	// starting positions of the quoted property keys that are kept
+	keys := make(map[int]bool)
+	for _, match := range regexpPropertyKey.FindAllStringSubmatchIndex(query, -1) {
+		if match[4] >= 0 {
+			keys[match[4]] = true
+			continue
+		}
+		keys[match[2]] = true
+	}
+
+	result := strings.Builder{}
+	last := 0
+	for _, literal := range regexpQuotedLiteral.FindAllStringIndex(query, -1) {
+		result.WriteString(query[last:literal[0]])
+		if keys[literal[0]] {
+			result.WriteString(query[literal[0]:literal[1]])
+		} else {
+			result.WriteString(`"***"`)
+		}
+		last = literal[1]
+	}
+	result.WriteString(query[last:])
+	return result.String()
+}
+
+// newQueryRecord creates the record for the given query based on the obtained responses and error
+func newQueryRecord(query string, start time.Time, responses []interfaces.Response, err error) QueryRecord {
+	record := QueryRecord{
+		Query:    RedactValues(query),
+		Start:    start,
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for _, response := range responses {
+		record.RequestID = response.RequestID
+		record.StatusCode = response.Status.Code
+
+		respInfo, err := parseAttributeMap(response.Status.Attributes)
+		if err != nil {
+			continue
+		}
+		record.StatusCode = respInfo.statusCode
+
+		// only take the largest value since cosmos already accumulates this value
+		if record.RequestCharge < respInfo.requestChargeTotal {
+			record.RequestCharge = respInfo.requestChargeTotal
+		}
+	}
+	return record
+}
+
+// recordQuery adds the given query to the query history (if enabled)
+func (c *cosmosImpl) recordQuery(query string, start time.Time, responses []interfaces.Response, err error) {
+	if c.queryHistory == nil {
+		return
+	}
+	c.queryHistory.add(newQueryRecord(query, start, responses, err))
+}
+
+// logQuery logs the given query on debug level after it was sanitized by the query logger (see WithQueryLogger).
+// Nothing is logged in case no query logger is set.
+func (c *cosmosImpl) logQuery(query string) {
+	if c.queryLogger == nil {
+		return
+	}
+	c.logger.Debug().Str("query", c.queryLogger(query)).Msg("Execute query")
+}
+
+// sanitizeQuery returns the given query as it should be logged, i.e. sanitized by the query logger if set
+func (c *cosmosImpl) sanitizeQuery(query string) string {
+	if c.queryLogger == nil {
+		return query
+	}
+	return c.queryLogger(query)
+}
+
+// QueryHistory returns the last executed queries (the oldest first). The history has to be enabled using WithQueryHistory,
+// otherwise an empty list is returned.
+func (c *cosmosImpl) QueryHistory() []QueryRecord {
+	if c.queryHistory == nil {
+		return []QueryRecord{}
+	}
+	return c.queryHistory.list()
+}
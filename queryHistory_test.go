@@ -0,0 +1,178 @@
+package gremcos
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
+)
+
+func TestQueryHistoryRingBuffer(t *testing.T) {
+	// GIVEN
+	history := newQueryHistory(3)
+
+	// WHEN
+	history.add(QueryRecord{Query: "q1"})
+	history.add(QueryRecord{Query: "q2"})
+	notFull := history.list()
+	history.add(QueryRecord{Query: "q3"})
+	history.add(QueryRecord{Query: "q4"})
+	history.add(QueryRecord{Query: "q5"})
+	full := history.list()
+
+	// THEN
+	require.Len(t, notFull, 2)
+	assert.Equal(t, "q1", notFull[0].Query)
+	assert.Equal(t, "q2", notFull[1].Query)
+	require.Len(t, full, 3)
+	assert.Equal(t, "q3", full[0].Query)
+	assert.Equal(t, "q4", full[1].Query)
+	assert.Equal(t, "q5", full[2].Query)
+}
+
+func TestRedactQuery(t *testing.T) {
+	assert.Equal(t, `g.V().has("name","***")`, RedactValues(`g.V().has("name","hans")`))
+	assert.Equal(t, `g.V("***").property("name", "***").property("age",32)`, RedactValues(`g.V("1").property("name", 'hans').property("age",32)`))
+	assert.Equal(t, `g.V().hasLabel("***")`, RedactValues(`g.V().hasLabel("user")`))
+	assert.Equal(t, `g.V().has("name","***").has("city","***")`, RedactValues(`g.V().has("name","a\"b").has("city","c,d")`))
+}
+
+func TestRedactQueryAllLiterals(t *testing.T) {
+	assert.Equal(t, `g.V("***")`, RedactValues(`g.V("id")`))
+	assert.Equal(t, `g.V().hasId("***")`, RedactValues(`g.V().hasId("x")`))
+	assert.Equal(t, `g.inject("***")`, RedactValues(`g.inject("secret")`))
+	assert.Equal(t, `g.V().has("name",within("***","***"))`, RedactValues(`g.V().has("name",within("a","b"))`))
+	assert.Equal(t, `g.mergeV([name:"***"])`, RedactValues(`g.mergeV([name:"hans"])`))
+	assert.Equal(t, `g.V().has("***","name","***")`, RedactValues(`g.V().has("user","name","hans")`))
+	assert.Equal(t, `g.V().property(list,"name","***")`, RedactValues(`g.V().property(list,"name","hans")`))
+	assert.Equal(t, `g.V().where(has("name","***"))`, RedactValues(`g.V().where(has("name","hans"))`))
+	assert.Equal(t, `g.V().has("name","***")`, RedactValues(`g.V().has("name",".has(\"x\",")`))
+}
+
+func TestQueryHistory(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, err := New("ws://host", WithQueryHistory(2), withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+
+	attributes := map[string]interface{}{
+		"x-ms-status-code":          200,
+		"x-ms-total-request-charge": 11.5,
+	}
+	mockedQueryExecutor.EXPECT().Execute(`g.V().has("name","hans")`).Return([]interfaces.Response{{RequestID: "id1", Status: interfaces.Status{Code: interfaces.StatusSuccess, Attributes: attributes}}}, nil)
+	mockedQueryExecutor.EXPECT().Execute(`g.V().count()`).Return(nil, fmt.Errorf("connection lost"))
+
+	// WHEN
+	_, errFirst := cosmos.Execute(`g.V().has("name","hans")`)
+	_, errSecond := cosmos.Execute(`g.V().count()`)
+	history := cosmos.QueryHistory()
+
+	// THEN
+	assert.NoError(t, errFirst)
+	assert.Error(t, errSecond)
+	require.Len(t, history, 2)
+	assert.Equal(t, `g.V().has("name","***")`, history[0].Query)
+	assert.Equal(t, "id1", history[0].RequestID)
+	assert.Equal(t, 200, history[0].StatusCode)
+	assert.Equal(t, float32(11.5), history[0].RequestCharge)
+	assert.Empty(t, history[0].Error)
+	assert.False(t, history[0].Start.IsZero())
+	assert.Equal(t, `g.V().count()`, history[1].Query)
+	assert.Equal(t, "connection lost", history[1].Error)
+}
+
+func TestQueryHistoryDisabled(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, err := New("ws://host", withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+	mockedQueryExecutor.EXPECT().Execute(`g.V()`).Return(nil, nil)
+
+	// WHEN
+	_, err = cosmos.Execute(`g.V()`)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Empty(t, cosmos.QueryHistory())
+}
+
+func TestExecuteBatchHistory(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, err := New("ws://host", WithQueryHistory(2), withMetrics(metrics))
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+	queries := []string{`g.addV("a")`, `g.addV("b")`}
+	responses := [][]interfaces.Response{{{RequestID: "1", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, {{RequestID: "2", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}}
+	mockedQueryExecutor.EXPECT().ExecuteBatch(queries).Return(responses, nil)
+
+	// WHEN
+	resp, err := cosmos.ExecuteBatch(queries)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, responses, resp)
+	history := cosmos.QueryHistory()
+	require.Len(t, history, 1)
+	assert.Equal(t, `g.addV("***");g.addV("***")`, history[0].Query)
+}
+
+func TestQueryLogger(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logBuffer := &bytes.Buffer{}
+	logger := zerolog.New(logBuffer).Level(zerolog.DebugLevel)
+	sanitizer := func(query string) string { return strings.ToUpper(RedactValues(query)) }
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithLogger(logger), WithQueryLogger(sanitizer))
+	mockedQueryExecutor.EXPECT().Execute(`g.V().has("name","hans")`).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+
+	// WHEN
+	_, err := cosmos.Execute(`g.V().has("name","hans")`)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Contains(t, logBuffer.String(), `"query":"G.V().HAS(\"NAME\",\"***\")"`)
+	assert.NotContains(t, logBuffer.String(), "hans")
+}
+
+func TestQueryLoggerDisabled(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	logBuffer := &bytes.Buffer{}
+	logger := zerolog.New(logBuffer).Level(zerolog.DebugLevel)
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithLogger(logger))
+	mockedQueryExecutor.EXPECT().Execute(`g.V().has("name","hans")`).Return([]interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+
+	// WHEN
+	_, err := cosmos.Execute(`g.V().has("name","hans")`)
+
+	// THEN
+	require.NoError(t, err)
+	assert.NotContains(t, logBuffer.String(), "hans")
+}
@@ -2,7 +2,6 @@ package gremcos
 
 import (
 	"encoding/base64"
-	"encoding/json"
 
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
@@ -11,8 +10,8 @@ import (
 // MimeType used for communication with the gremlin server.
 var MimeType = []byte("application/vnd.gremlin-v2.0+json")
 
-// request is a container for all evaluation request parameters to be sent to the Gremlin Server.
-type request struct {
+// Request is a container for all evaluation request parameters to be sent to the Gremlin Server.
+type Request struct {
 	RequestID string                 `json:"requestId"`
 	Op        string                 `json:"op"`
 	Processor string                 `json:"processor"`
@@ -20,14 +19,33 @@ type request struct {
 }
 
 // prepareRequest packages a query and binding into the format that Gremlin Server accepts
-func prepareRequest(query string) (request, string, error) {
+func prepareRequest(query string) (Request, string, error) {
 	var uuID uuid.UUID
 	uuID, err := uuid.NewV4()
 	if err != nil {
-		return request{}, "", err
+		return Request{}, "", err
+	}
+	return prepareRequestWithID(uuID.String(), query)
+}
+
+// parseRequestID parses the given request id, an error is returned in case it is not a well-formed UUID
+func parseRequestID(requestID string) (uuid.UUID, error) {
+	uuID, err := uuid.FromString(requestID)
+	if err != nil {
+		return uuid.UUID{}, errors.Wrapf(err, "Invalid request id '%s'", requestID)
+	}
+	return uuID, nil
+}
+
+// prepareRequestWithID packages a query into the format that Gremlin Server accepts using the given request id.
+// The request id has to be a well-formed UUID.
+func prepareRequestWithID(requestID string, query string) (Request, string, error) {
+	uuID, err := parseRequestID(requestID)
+	if err != nil {
+		return Request{}, "", err
 	}
 
-	req := request{}
+	req := Request{}
 	req.RequestID = uuID.String()
 	req.Op = "eval"
 	req.Processor = ""
@@ -40,13 +58,13 @@ func prepareRequest(query string) (request, string, error) {
 }
 
 // prepareRequest packages a query and binding into the format that Gremlin Server accepts
-func prepareRequestWithBindings(query string, bindings, rebindings map[string]interface{}) (request, string, error) {
+func prepareRequestWithBindings(query string, bindings, rebindings map[string]interface{}) (Request, string, error) {
 	uuID, err := uuid.NewV4()
 	if err != nil {
-		return request{}, "", err
+		return Request{}, "", err
 	}
 
-	req := request{}
+	req := Request{}
 	req.RequestID = uuID.String()
 	req.Op = "eval"
 	req.Processor = ""
@@ -61,8 +79,8 @@ func prepareRequestWithBindings(query string, bindings, rebindings map[string]in
 }
 
 //prepareAuthRequest creates a ws request for Gremlin Server
-func prepareAuthRequest(requestID string, username string, password string) request {
-	req := request{}
+func prepareAuthRequest(requestID string, username string, password string) Request {
+	req := Request{}
 	req.RequestID = requestID
 	req.Op = "authentication"
 	req.Processor = "trasversal"
@@ -83,15 +101,17 @@ func prepareAuthRequest(requestID string, username string, password string) requ
 }
 
 // formatMessage takes a request type and formats it into being able to be delivered to Gremlin Server
-func packageRequest(req request) ([]byte, error) {
-	j, err := json.Marshal(req) // Formats request into byte format
+// using the given serializer
+func packageRequest(req Request, serializer Serializer) ([]byte, error) {
+	j, err := serializer.Marshal(req) // Formats request into byte format
 	if err != nil {
 		return nil, errors.Wrap(err, "marshalling request")
 	}
-	lenMimeType := byte(len(MimeType))
+	mimeType := []byte(serializer.MimeType())
+	lenMimeType := byte(len(mimeType))
 
 	//lenMimeType is the fixed length of mimeType in hex
-	msg := append([]byte{lenMimeType}, MimeType...)
+	msg := append([]byte{lenMimeType}, mimeType...)
 	msg = append(msg, j...)
 
 	return msg, nil
@@ -18,7 +18,7 @@ func TestRequestPreparation(t *testing.T) {
 	req, id, err := prepareRequestWithBindings(query, bindings, rebindings)
 	require.NoError(t, err)
 
-	expectedRequest := request{
+	expectedRequest := Request{
 		RequestID: id,
 		Op:        "eval",
 		Processor: "",
@@ -35,7 +35,7 @@ func TestRequestPreparation(t *testing.T) {
 
 // TestRequestPackaging tests the ability for gremcos to format a request using the established Gremlin Server WebSockets protocol for delivery to the server
 func TestRequestPackaging(t *testing.T) {
-	testRequest := request{
+	testRequest := Request{
 		RequestID: "1d6d02bd-8e56-421d-9438-3bd6d0079ff1",
 		Op:        "eval",
 		Processor: "",
@@ -46,7 +46,7 @@ func TestRequestPackaging(t *testing.T) {
 		},
 	}
 
-	msg, err := packageRequest(testRequest)
+	msg, err := packageRequest(testRequest, GraphSONv2Serializer{})
 	require.NoError(t, err)
 
 	j, err := json.Marshal(testRequest)
@@ -69,7 +69,7 @@ func TestRequestDispatch(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	mockedDialer := mock_interfaces.NewMockDialer(mockCtrl)
-	testRequest := request{
+	testRequest := Request{
 		RequestID: "1d6d02bd-8e56-421d-9438-3bd6d0079ff1",
 		Op:        "eval",
 		Processor: "",
@@ -80,7 +80,7 @@ func TestRequestDispatch(t *testing.T) {
 		},
 	}
 	c := newClient(mockedDialer)
-	msg, err := packageRequest(testRequest)
+	msg, err := packageRequest(testRequest, GraphSONv2Serializer{})
 	require.NoError(t, err)
 
 	// WHEN
@@ -103,7 +103,7 @@ func TestAuthRequestDispatch(t *testing.T) {
 	testRequest := prepareAuthRequest(id, "test", "root")
 
 	c := newClient(mockedDialer)
-	msg, err := packageRequest(testRequest)
+	msg, err := packageRequest(testRequest, GraphSONv2Serializer{})
 	require.NoError(t, err)
 
 	// WHEN
@@ -1,14 +1,13 @@
 package gremcos
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/supplyon/gremcos/interfaces"
 )
 
 func (c *client) handleResponse(msg []byte) error {
-	resp, err := marshalResponse(msg)
+	resp, err := marshalResponse(msg, c.serializer)
 
 	// ignore the error here in case the response status code tells that an authentication is needed
 	if resp.Status.Code == interfaces.StatusAuthenticate { //Server request authentication
@@ -20,9 +19,8 @@ func (c *client) handleResponse(msg []byte) error {
 }
 
 // marshalResponse creates a response struct for every incoming response for further manipulation
-func marshalResponse(msg []byte) (interfaces.Response, error) {
-	resp := interfaces.Response{}
-	err := json.Unmarshal(msg, &resp)
+func marshalResponse(msg []byte, serializer Serializer) (interfaces.Response, error) {
+	resp, err := serializer.Unmarshal(msg)
 	if err != nil {
 		return resp, err
 	}
@@ -67,6 +65,8 @@ func (c *client) saveResponse(resp interfaces.Response, err error) {
 }
 
 // retrieveResponseAsync retrieves the response saved by saveResponse and send the retrieved repose to the channel .
+// The responses are sent in the order they were received (which is the order the server sent them), each of them
+// tagged with its chunk index.
 func (c *client) retrieveResponseAsync(id string, responseChannel chan interfaces.AsyncResponse) {
 	var responseProcessedIndex int
 	responseNotifier, _ := c.responseNotifier.Load(id)
@@ -74,8 +74,23 @@ func (c *client) retrieveResponseAsync(id string, responseChannel chan interface
 	responseStatusNotifier, _ := c.responseStatusNotifier.Load(id)
 	responseStatusNotifierChannel := responseStatusNotifier.(*safeCloseIntChannel)
 
-	for status := range responseStatusNotifierChannel.c {
-		_ = status
+	// the query timeout applies to the whole stream of responses
+	timeout, stopTimer := c.startQueryTimer()
+	defer stopTimer()
+
+	timedOut := false
+
+receiveLoop:
+	for {
+		select {
+		case _, ok := <-responseStatusNotifierChannel.c:
+			if !ok {
+				break receiveLoop
+			}
+		case <-timeout:
+			timedOut = true
+			break receiveLoop
+		}
 
 		// this block retrieves all but the last of the partial responses
 		// and sends it to the response channel
@@ -86,6 +101,7 @@ func (c *client) retrieveResponseAsync(id string, responseChannel chan interface
 				responseProcessedIndex++
 				var asyncResponse interfaces.AsyncResponse = interfaces.AsyncResponse{}
 				asyncResponse.Response = d[i].(interfaces.Response)
+				asyncResponse.ChunkIndex = i
 				// Send the Partial response object to the responseChannel
 				responseChannel <- asyncResponse
 			}
@@ -107,6 +123,7 @@ func (c *client) retrieveResponseAsync(id string, responseChannel chan interface
 				responseProcessedIndex++
 				asyncResponse := interfaces.AsyncResponse{}
 				asyncResponse.Response = d[i].(interfaces.Response)
+				asyncResponse.ChunkIndex = i
 				//when final partial response it sent it also sends the error message if there was an error on the last partial response retrival
 				if responseProcessedIndex == len(d) && err != nil {
 					asyncResponse.ErrorMessage = err.Error()
@@ -126,6 +143,11 @@ func (c *client) retrieveResponseAsync(id string, responseChannel chan interface
 	c.responseNotifier.Delete(id)
 	c.responseStatusNotifier.Delete(id)
 	c.deleteResponse(id)
+
+	if timedOut {
+		err := c.onQueryTimeout(id)
+		responseChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: id}, ErrorMessage: err.Error(), ChunkIndex: responseProcessedIndex}
+	}
 	close(responseChannel)
 }
 
@@ -167,7 +189,15 @@ func (c *client) retrieveResponse(id string) ([]interfaces.Response, error) {
 	}
 	responseStatusNotifierChannel = responseStatusNotifierUntyped.(*safeCloseIntChannel)
 
-	err := <-responseErrorChannel.c
+	timeout, stopTimer := c.startQueryTimer()
+	defer stopTimer()
+
+	var err error
+	select {
+	case err = <-responseErrorChannel.c:
+	case <-timeout:
+		return nil, c.onQueryTimeout(id)
+	}
 	// Hint: Don't return here immediately in case the obtained error is != nil.
 	// We don't want to loose the responses obtained so far, especially the
 	// data stored in the attribute map of each response is useful.
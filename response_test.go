@@ -97,7 +97,7 @@ func TestPrepareAuthenRequest(t *testing.T) {
 
 	req := prepareAuthRequest(dummyNeedAuthenticationResponseMarshalled.RequestID, "test", "test")
 
-	sampleAuthRequest, err := packageRequest(req)
+	sampleAuthRequest, err := packageRequest(req, GraphSONv2Serializer{})
 	require.NoError(t, err)
 
 	c.dispatchRequest(sampleAuthRequest)
@@ -126,7 +126,7 @@ func TestAuthCompleted(t *testing.T) {
 
 // TestResponseMarshalling tests the ability to marshal a response into a designated response struct for further manipulation
 func TestResponseMarshalling(t *testing.T) {
-	resp, err := marshalResponse(dummySuccessfulResponse)
+	resp, err := marshalResponse(dummySuccessfulResponse, GraphSONv2Serializer{})
 	require.NoError(t, err)
 
 	assert.Equal(t, resp.RequestID, dummySuccessfulResponseMarshalled.RequestID)
@@ -254,7 +254,7 @@ func TestAsyncResponseRetrieval(t *testing.T) {
 	assert.Equal(t, expectedAsync, resp)
 
 	resp = <-responseChannel
-	expectedAsync = interfaces.AsyncResponse{Response: dummyPartialResponse2Marshalled}
+	expectedAsync = interfaces.AsyncResponse{Response: dummyPartialResponse2Marshalled, ChunkIndex: 1}
 	assert.Equal(t, expectedAsync, resp)
 }
 
@@ -0,0 +1,128 @@
+package gremcos
+
+import (
+	"context"
+	"time"
+
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// statusTooManyRequests is the status code used by the CosmosDB in case a request was throttled (RequestRateTooLarge)
+const statusTooManyRequests = 429
+
+// executeFunc is a function that executes a query and returns the according responses
+type executeFunc func() ([]interfaces.Response, error)
+
+// executeWithRetry executes the query using the given function. If the query was throttled by the CosmosDB
+// (status code 429) and retries are enabled, the query is retried after the wait time proposed by the CosmosDB.
+// All other errors are returned immediately. Script evaluation (597) and serialization (599) errors are deterministic,
+// hence they are never retried unless this guard was explicitly disabled via WithNoRetryOnScriptError(false).
+// The waiting for the next retry is aborted as soon as the given context is done, then the error of the context is returned.
+func (c *cosmosImpl) executeWithRetry(ctx context.Context, execute executeFunc) ([]interfaces.Response, error) {
+	for attempt := 0; ; attempt++ {
+		responses, err := execute()
+
+		// try to investigate the responses and to find out if we can find more specific error information
+		if respErr := extractFirstError(responses); respErr != nil {
+			err = respErr
+		}
+
+		updateRequestMetrics(responses, c.metrics)
+		c.health.recordResponses(responses, err)
+		c.recordQueryConnectivity(responses, err)
+
+		if err == nil || attempt >= c.maxRetries {
+			return responses, err
+		}
+
+		if c.noRetryOnScriptError && containsScriptError(responses) {
+			return responses, err
+		}
+
+		retryAfter, throttled := extractThrottling(responses)
+		if !throttled {
+			return responses, err
+		}
+
+		wait := c.retryWaitTime(attempt, retryAfter)
+		c.logger.Debug().Err(err).Int("attempt", attempt+1).Dur("wait", wait).Msg("Request was throttled, retrying")
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return responses, ctx.Err()
+		}
+	}
+}
+
+// runWithContext runs the given function and waits until it is completed or the given context is done.
+// In the latter case the error of the context is returned immediately, while the function keeps running in the
+// background (the query can't be aborted once it was sent, hence it is completed by the CosmosDB anyway).
+func runWithContext(ctx context.Context, run func()) error {
+	if ctx.Done() == nil {
+		run()
+		return nil
+	}
+
+	completed := make(chan struct{})
+	go func() {
+		defer close(completed)
+		run()
+	}()
+
+	select {
+	case <-completed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryWaitTime returns the time to wait before the next retry. The retry-after hint of the CosmosDB is used
+// if available, otherwise the wait time is calculated using an exponential backoff based on the base backoff.
+func (c *cosmosImpl) retryWaitTime(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	return c.retryBaseBackoff * time.Duration(1<<uint(attempt))
+}
+
+// extractThrottling returns true in case one of the given responses tells that the request was throttled.
+// Additionally the largest retry-after hint of the responses is returned (0 if there is none).
+func extractThrottling(responses []interfaces.Response) (time.Duration, bool) {
+	throttled := false
+	retryAfter := time.Duration(0)
+
+	for _, response := range responses {
+		if response.Status.Code == statusTooManyRequests {
+			throttled = true
+		}
+
+		respInfo, err := parseAttributeMap(response.Status.Attributes)
+		if err != nil {
+			continue
+		}
+
+		if respInfo.statusCode == statusTooManyRequests {
+			throttled = true
+		}
+
+		if retryAfter < respInfo.retryAfter {
+			retryAfter = respInfo.retryAfter
+		}
+	}
+	return retryAfter, throttled
+}
+
+// containsScriptError returns true in case one of the given responses failed with a script evaluation (597)
+// or a serialization (599) error.
+func containsScriptError(responses []interfaces.Response) bool {
+	for _, response := range responses {
+		switch response.Status.Code {
+		case interfaces.StatusScriptEvaluationError, interfaces.StatusServerSerializationError:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package gremcos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
+)
+
+func newThrottledResponse(retryAfter string) interfaces.Response {
+	return interfaces.Response{
+		RequestID: "throttled",
+		Status: interfaces.Status{
+			Code: interfaces.StatusServerError,
+			Attributes: map[string]interface{}{
+				"x-ms-status-code":    statusTooManyRequests,
+				"x-ms-retry-after-ms": retryAfter,
+			},
+		},
+	}
+}
+
+func newCosmosWithMockedPool(t *testing.T, mockCtrl *gomock.Controller, options ...Option) (Cosmos, *mock_interfaces.MockQueryExecutor) {
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	mockedQueryExecutor := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+
+	cosmos, err := New("ws://host", append(options, withMetrics(metrics))...)
+	require.NoError(t, err)
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = mockedQueryExecutor
+	return cosmos, mockedQueryExecutor
+}
+
+func TestRetryOnThrottling(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(3, time.Second))
+	query := "g.V()"
+	success := []interfaces.Response{{RequestID: "ok", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledResponse("00:00:00.010")}, nil),
+		mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledResponse("00:00:00.010")}, nil),
+		mockedQueryExecutor.EXPECT().Execute(query).Return(success, nil),
+	)
+
+	// WHEN
+	start := time.Now()
+	responses, err := cosmos.Execute(query)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, success, responses)
+	// the retry-after hint is used instead of the (much larger) base backoff
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestRetryWaitAbortedByContext(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(3, time.Second))
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return([]interfaces.Response{newThrottledResponse("00:01:00.000")}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// WHEN
+	start := time.Now()
+	_, err := cosmos.ExecuteWithContext(ctx, "g.V()")
+
+	// THEN
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < 10*time.Second, "the retry-after of one minute must not be awaited")
+}
+
+func TestRetryOnThrottlingExhausted(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(2, time.Millisecond))
+	query := "g.V()"
+	throttled := []interfaces.Response{{RequestID: "throttled", Status: interfaces.Status{Code: statusTooManyRequests}}}
+
+	// one initial try plus two retries
+	mockedQueryExecutor.EXPECT().Execute(query).Return(throttled, nil).Times(3)
+
+	// WHEN
+	responses, err := cosmos.Execute(query)
+
+	// THEN
+	assert.Error(t, err)
+	assert.Equal(t, throttled, responses)
+}
+
+func TestNoRetryOnOtherErrors(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(3, time.Millisecond))
+	query := "g.V()"
+	failed := []interfaces.Response{{RequestID: "failed", Status: interfaces.Status{Code: interfaces.StatusMalformedRequest}}}
+
+	mockedQueryExecutor.EXPECT().Execute(query).Return(failed, nil).Times(1)
+
+	// WHEN
+	_, err := cosmos.Execute(query)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func TestNoRetryPerDefault(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V()"
+
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledResponse("00:00:00.001")}, nil).Times(1)
+
+	// WHEN
+	_, err := cosmos.Execute(query)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func newThrottledScriptErrorResponse() interfaces.Response {
+	response := newThrottledResponse("00:00:00.001")
+	response.Status.Code = interfaces.StatusScriptEvaluationError
+	return response
+}
+
+func TestNoRetryOnScriptError(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(3, time.Millisecond))
+	query := "g.V().foo()"
+
+	// even though flagged as throttled, the script error has to be returned immediately
+	mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledScriptErrorResponse()}, nil).Times(1)
+
+	// WHEN
+	_, err := cosmos.Execute(query)
+
+	// THEN
+	assert.Error(t, err)
+}
+
+func TestRetryOnScriptErrorIfGuardDisabled(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithRetry(3, time.Millisecond), WithNoRetryOnScriptError(false))
+	query := "g.V()"
+	success := []interfaces.Response{{RequestID: "ok", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+
+	gomock.InOrder(
+		mockedQueryExecutor.EXPECT().Execute(query).Return([]interfaces.Response{newThrottledScriptErrorResponse()}, nil),
+		mockedQueryExecutor.EXPECT().Execute(query).Return(success, nil),
+	)
+
+	// WHEN
+	responses, err := cosmos.Execute(query)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.Equal(t, success, responses)
+}
+
+func TestRetryWaitTime(t *testing.T) {
+	// GIVEN
+	cImpl := &cosmosImpl{retryBaseBackoff: time.Millisecond * 100}
+
+	// WHEN + THEN
+	assert.Equal(t, time.Millisecond*100, cImpl.retryWaitTime(0, 0))
+	assert.Equal(t, time.Millisecond*200, cImpl.retryWaitTime(1, 0))
+	assert.Equal(t, time.Millisecond*400, cImpl.retryWaitTime(2, 0))
+	assert.Equal(t, time.Millisecond*5, cImpl.retryWaitTime(2, time.Millisecond*5))
+}
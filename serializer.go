@@ -0,0 +1,184 @@
+package gremcos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/supplyon/gremcos/api"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// Serializer serializes the requests that are sent to the gremlin server and deserializes the received responses.
+// Per default GraphSONv2Serializer is used, another serializer (GraphSONv1Serializer, GraphSONv3Serializer or a custom one)
+// can be specified using WithSerializer.
+type Serializer interface {
+	// MimeType is the mime type that is sent along with each request, it tells the server which serialization is used
+	MimeType() string
+	// Marshal serializes the given request
+	Marshal(req Request) ([]byte, error)
+	// Unmarshal deserializes the given response
+	Unmarshal(msg []byte) (interfaces.Response, error)
+}
+
+// GraphSONv2Serializer serializes requests and responses as GraphSON v2 (application/vnd.gremlin-v2.0+json).
+// This is the default serializer.
+type GraphSONv2Serializer struct{}
+
+// MimeType returns application/vnd.gremlin-v2.0+json
+func (GraphSONv2Serializer) MimeType() string {
+	return string(MimeType)
+}
+
+// Marshal serializes the given request as plain json
+func (GraphSONv2Serializer) Marshal(req Request) ([]byte, error) {
+	return json.Marshal(req)
+}
+
+// Unmarshal deserializes the given response from plain json
+func (GraphSONv2Serializer) Unmarshal(msg []byte) (interfaces.Response, error) {
+	resp := interfaces.Response{}
+	err := json.Unmarshal(msg, &resp)
+	return resp, err
+}
+
+// GraphSONv1Serializer serializes requests and responses as GraphSON v1 (application/vnd.gremlin-v1.0+json).
+// GraphSON v1 is untyped like v2, hence requests and responses are plain json. This is only needed for old servers
+// that don't support GraphSON v2.
+type GraphSONv1Serializer struct{}
+
+// MimeType returns application/vnd.gremlin-v1.0+json
+func (GraphSONv1Serializer) MimeType() string {
+	return "application/vnd.gremlin-v1.0+json"
+}
+
+// Marshal serializes the given request as plain json
+func (GraphSONv1Serializer) Marshal(req Request) ([]byte, error) {
+	return GraphSONv2Serializer{}.Marshal(req)
+}
+
+// Unmarshal deserializes the given response from plain json
+func (GraphSONv1Serializer) Unmarshal(msg []byte) (interfaces.Response, error) {
+	return GraphSONv2Serializer{}.Unmarshal(msg)
+}
+
+// GraphSONv3Serializer serializes requests and responses as GraphSON v3 (application/vnd.gremlin-v3.0+json).
+// The values of the request (e.g. request id and arguments) are sent as typed GraphSON values, e.g. the arguments
+// as {"@type":"g:Map","@value":["gremlin","g.V()","language","gremlin-groovy"]}. The typed status attributes and
+// meta data of the responses are converted into plain maps, the result data is kept as it is (see api.Decode).
+type GraphSONv3Serializer struct{}
+
+// graphSONv3Response is the GraphSON v3 representation of a response
+type graphSONv3Response struct {
+	RequestID json.RawMessage `json:"requestId"`
+	Status    struct {
+		Message    string          `json:"message"`
+		Code       int             `json:"code"`
+		Attributes json.RawMessage `json:"attributes"`
+	} `json:"status"`
+	Result struct {
+		Data json.RawMessage `json:"data"`
+		Meta json.RawMessage `json:"meta"`
+	} `json:"result"`
+}
+
+// MimeType returns application/vnd.gremlin-v3.0+json
+func (GraphSONv3Serializer) MimeType() string {
+	return "application/vnd.gremlin-v3.0+json"
+}
+
+// Marshal serializes the given request as GraphSON v3
+func (GraphSONv3Serializer) Marshal(req Request) ([]byte, error) {
+	typed := map[string]interface{}{
+		"requestId": toGraphSONv3Typed("g:UUID", req.RequestID),
+		"op":        req.Op,
+		"processor": req.Processor,
+		"args":      toGraphSONv3(req.Args),
+	}
+	return json.Marshal(typed)
+}
+
+// Unmarshal deserializes the given GraphSON v3 response
+func (GraphSONv3Serializer) Unmarshal(msg []byte) (interfaces.Response, error) {
+	typed := graphSONv3Response{}
+	if err := json.Unmarshal(msg, &typed); err != nil {
+		return interfaces.Response{}, err
+	}
+
+	resp := interfaces.Response{}
+	resp.Status.Message = typed.Status.Message
+	resp.Status.Code = typed.Status.Code
+	resp.Result.Data = typed.Result.Data
+
+	if err := unmarshalGraphSONv3Value(typed.RequestID, &resp.RequestID); err != nil {
+		return resp, errors.Wrap(err, "Failed to parse the request id")
+	}
+	if err := unmarshalGraphSONv3Value(typed.Status.Attributes, &resp.Status.Attributes); err != nil {
+		return resp, errors.Wrap(err, "Failed to parse the status attributes")
+	}
+	if err := unmarshalGraphSONv3Value(typed.Result.Meta, &resp.Result.Meta); err != nil {
+		return resp, errors.Wrap(err, "Failed to parse the result meta data")
+	}
+	return resp, nil
+}
+
+// unmarshalGraphSONv3Value unmarshals the given (typed) GraphSON value into target, missing values are skipped
+func unmarshalGraphSONv3Value(data json.RawMessage, target interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return api.UnmarshalGraphSON(data, target)
+}
+
+// toGraphSONv3Typed wraps the given value into a GraphSON envelope of the given type
+func toGraphSONv3Typed(typ string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"@type": typ, "@value": value}
+}
+
+// toGraphSONv3 converts the given value into its typed GraphSON v3 representation.
+// Maps are converted into g:Map (sorted by key), slices into g:List and numbers into g:Int32, g:Int64, g:Float or g:Double.
+// Strings, booleans and nil are not typed in GraphSON.
+func toGraphSONv3(value interface{}) interface{} {
+	switch casted := value.(type) {
+	case nil, string, bool, json.RawMessage:
+		return casted
+	case int32:
+		return toGraphSONv3Typed("g:Int32", casted)
+	case int, int64:
+		return toGraphSONv3Typed("g:Int64", casted)
+	case float32:
+		return toGraphSONv3Typed("g:Float", casted)
+	case float64:
+		return toGraphSONv3Typed("g:Double", casted)
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Map:
+		type entry struct {
+			key   string
+			value interface{}
+		}
+		entries := make([]entry, 0, reflected.Len())
+		for _, key := range reflected.MapKeys() {
+			entries = append(entries, entry{key: fmt.Sprintf("%v", key.Interface()), value: reflected.MapIndex(key).Interface()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+		list := make([]interface{}, 0, 2*len(entries))
+		for _, entry := range entries {
+			list = append(list, entry.key, toGraphSONv3(entry.value))
+		}
+		return toGraphSONv3Typed("g:Map", list)
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, 0, reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			list = append(list, toGraphSONv3(reflected.Index(i).Interface()))
+		}
+		return toGraphSONv3Typed("g:List", list)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,160 @@
+package gremcos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/api"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+func TestGraphSONv3SerializerMarshal(t *testing.T) {
+	// GIVEN
+	serializer := GraphSONv3Serializer{}
+	req := Request{
+		RequestID: "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21",
+		Op:        "eval",
+		Args: map[string]interface{}{
+			"gremlin":  "g.V(id).has('age',gt(age))",
+			"language": "gremlin-groovy",
+			"bindings": map[string]interface{}{"id": "1", "age": 21, "weight": 70.5, "tags": []string{"a", "b"}},
+		},
+	}
+
+	// WHEN
+	data, err := serializer.Marshal(req)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.gremlin-v3.0+json", serializer.MimeType())
+	assert.JSONEq(t, `{
+		"requestId":{"@type":"g:UUID","@value":"5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21"},
+		"op":"eval",
+		"processor":"",
+		"args":{"@type":"g:Map","@value":[
+			"bindings",{"@type":"g:Map","@value":[
+				"age",{"@type":"g:Int64","@value":21},
+				"id","1",
+				"tags",{"@type":"g:List","@value":["a","b"]},
+				"weight",{"@type":"g:Double","@value":70.5}
+			]},
+			"gremlin","g.V(id).has('age',gt(age))",
+			"language","gremlin-groovy"
+		]}
+	}`, string(data))
+}
+
+func TestGraphSONv3SerializerRoundTrip(t *testing.T) {
+	// GIVEN
+	serializer := GraphSONv3Serializer{}
+	req, _, err := prepareRequestWithBindings("g.V(id)", map[string]interface{}{"id": int32(1)}, map[string]interface{}{})
+	require.NoError(t, err)
+
+	// WHEN
+	data, err := serializer.Marshal(req)
+	require.NoError(t, err)
+	var decoded struct {
+		RequestID json.RawMessage `json:"requestId"`
+		Op        string          `json:"op"`
+		Args      json.RawMessage `json:"args"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	var requestID string
+	errRequestID := api.UnmarshalGraphSON(decoded.RequestID, &requestID)
+	var args map[string]interface{}
+	errArgs := api.UnmarshalGraphSON(decoded.Args, &args)
+
+	// THEN
+	require.NoError(t, errRequestID)
+	require.NoError(t, errArgs)
+	assert.Equal(t, req.RequestID, requestID)
+	assert.Equal(t, req.Op, decoded.Op)
+	assert.Equal(t, map[string]interface{}{
+		"gremlin":    "g.V(id)",
+		"language":   "gremlin-groovy",
+		"bindings":   map[string]interface{}{"id": int32(1)},
+		"rebindings": map[string]interface{}{},
+	}, args)
+}
+
+func TestGraphSONv3SerializerUnmarshal(t *testing.T) {
+	// GIVEN
+	serializer := GraphSONv3Serializer{}
+	msg := []byte(`{
+		"requestId":"5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21",
+		"status":{"message":"","code":200,"attributes":{"@type":"g:Map","@value":[
+			"x-ms-status-code",{"@type":"g:Int32","@value":200},
+			"x-ms-total-request-charge",{"@type":"g:Double","@value":2.5}
+		]}},
+		"result":{"data":{"@type":"g:List","@value":[{"@type":"g:Int64","@value":42}]},"meta":{"@type":"g:Map","@value":[]}}
+	}`)
+
+	// WHEN
+	resp, err := serializer.Unmarshal(msg)
+
+	// THEN
+	require.NoError(t, err)
+	assert.Equal(t, "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21", resp.RequestID)
+	assert.Equal(t, interfaces.StatusSuccess, resp.Status.Code)
+	assert.Equal(t, map[string]interface{}{"x-ms-status-code": int32(200), "x-ms-total-request-charge": 2.5}, resp.Status.Attributes)
+	assert.Equal(t, map[string]interface{}{}, resp.Result.Meta)
+	charge, ok := RequestCharge(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 2.5, charge)
+	var count []int64
+	require.NoError(t, api.Decode(resp, &count))
+	assert.Equal(t, []int64{42}, count)
+}
+
+func TestGraphSONv3SerializerUnmarshalFail(t *testing.T) {
+	// GIVEN
+	serializer := GraphSONv3Serializer{}
+
+	// WHEN
+	_, errInvalid := serializer.Unmarshal([]byte(`{"requestId":`))
+	_, errAttributes := serializer.Unmarshal([]byte(`{"requestId":"1","status":{"code":200,"attributes":{"@type":"g:Map","@value":["odd"]}}}`))
+
+	// THEN
+	assert.Error(t, errInvalid)
+	assert.Error(t, errAttributes)
+}
+
+func TestPackageRequestWithSerializer(t *testing.T) {
+	// GIVEN
+	req := Request{RequestID: "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21", Op: "eval", Args: map[string]interface{}{"gremlin": "g.V()"}}
+	mimeType := GraphSONv3Serializer{}.MimeType()
+
+	// WHEN
+	msg, err := packageRequest(req, GraphSONv3Serializer{})
+
+	// THEN
+	require.NoError(t, err)
+	require.True(t, len(msg) > len(mimeType)+1)
+	assert.Equal(t, byte(len(mimeType)), msg[0])
+	assert.Equal(t, mimeType, string(msg[1:len(mimeType)+1]))
+	assert.JSONEq(t, `{"requestId":{"@type":"g:UUID","@value":"5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21"},"op":"eval","processor":"","args":{"@type":"g:Map","@value":["gremlin","g.V()"]}}`, string(msg[len(mimeType)+1:]))
+}
+
+func TestGraphSONv1Serializer(t *testing.T) {
+	// GIVEN
+	serializer := GraphSONv1Serializer{}
+	req := Request{RequestID: "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21", Op: "eval", Args: map[string]interface{}{"gremlin": "g.V()"}}
+	msg := []byte(`{"requestId":"5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21","status":{"message":"","code":200,"attributes":{}},"result":{"data":[{"id":"1","label":"user"}],"meta":{}}}`)
+
+	// WHEN
+	data, errMarshal := serializer.Marshal(req)
+	resp, errUnmarshal := serializer.Unmarshal(msg)
+	packed, errPackage := packageRequest(req, serializer)
+
+	// THEN
+	require.NoError(t, errMarshal)
+	assert.JSONEq(t, `{"requestId":"5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21","op":"eval","processor":"","args":{"gremlin":"g.V()"}}`, string(data))
+	require.NoError(t, errUnmarshal)
+	assert.Equal(t, "5b4a4bd2-e7c1-4a9b-9c6e-5d8a7d3f6e21", resp.RequestID)
+	assert.Equal(t, interfaces.StatusSuccess, resp.Status.Code)
+	assert.JSONEq(t, `[{"id":"1","label":"user"}]`, string(resp.Result.Data))
+	require.NoError(t, errPackage)
+	assert.Equal(t, "application/vnd.gremlin-v1.0+json", string(packed[1:len(serializer.MimeType())+1]))
+}
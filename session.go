@@ -0,0 +1,152 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// Session is a set of requests that are pinned to one connection (connection affinity).
+// All requests of the session are sent over the same connection, which is not given back to the
+// connection pool (and thus not used by other requests) until the session is closed.
+type Session interface {
+	// ExecuteQuery executes the given query over the connection of the session
+	ExecuteQuery(query interfaces.QueryBuilder) ([]interfaces.Response, error)
+
+	// Execute executes the given raw query (string) over the connection of the session
+	Execute(query string) ([]interfaces.Response, error)
+
+	// ExecuteWithBindings executes the given raw query (string) with optional bindings/rebindings over the connection of the session
+	ExecuteWithBindings(query string, bindings, rebindings map[string]interface{}) ([]interfaces.Response, error)
+
+	// ExecuteSequential executes the given raw queries (strings) one after another over the connection of the session.
+	// The responses are returned per query (in the order of the queries). In case a query fails the error of the first
+	// failing query is returned. With continueOnError the remaining queries are executed anyway, otherwise the execution stops
+	// at the failing query.
+	ExecuteSequential(queries []string, continueOnError bool) ([][]interfaces.Response, error)
+
+	// Close ends the session and gives the connection back to the pool. Further requests of the session fail.
+	Close() error
+}
+
+// connectionProvider is implemented by query executors that are able to hand out dedicated connections
+type connectionProvider interface {
+	Get() (*pooledConnection, error)
+}
+
+type session struct {
+	cosmos *cosmosImpl
+
+	// conn is the connection all requests of the session are sent over, nil as soon as the session is closed
+	conn *pooledConnection
+	mu   sync.Mutex
+}
+
+// NewSession obtains a connection from the pool and returns a Session whose requests are all sent
+// over this connection. The connection is kept until the session is closed.
+func (c *cosmosImpl) NewSession() (Session, error) {
+	provider, ok := c.pool.(connectionProvider)
+	if !ok {
+		return nil, fmt.Errorf("Sessions are not supported by the query executor in use (%T)", c.pool)
+	}
+
+	conn, err := provider.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &session{cosmos: c, conn: conn}, nil
+}
+
+// ExecuteSequential obtains a connection from the pool and executes the given queries one after another over this connection.
+// The connection is given back to the pool afterwards.
+func (c *cosmosImpl) ExecuteSequential(queries []string, continueOnError bool) ([][]interfaces.Response, error) {
+	session, err := c.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	return session.ExecuteSequential(queries, continueOnError)
+}
+
+func (s *session) ExecuteQuery(query interfaces.QueryBuilder) ([]interfaces.Response, error) {
+	if query == nil {
+		return nil, fmt.Errorf("Query is nil")
+	}
+	return s.Execute(query.String())
+}
+
+func (s *session) Execute(query string) ([]interfaces.Response, error) {
+	return s.execute(query, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.Execute(query)
+	})
+}
+
+func (s *session) ExecuteWithBindings(query string, bindings, rebindings map[string]interface{}) ([]interfaces.Response, error) {
+	return s.execute(query, func(client interfaces.QueryExecutor) ([]interfaces.Response, error) {
+		return client.ExecuteWithBindings(query, bindings, rebindings)
+	})
+}
+
+func (s *session) ExecuteSequential(queries []string, continueOnError bool) ([][]interfaces.Response, error) {
+	results := make([][]interfaces.Response, 0, len(queries))
+	var firstErr error
+	for i, query := range queries {
+		responses, err := s.Execute(query)
+		results = append(results, responses)
+		if err == nil {
+			continue
+		}
+
+		if firstErr == nil {
+			firstErr = errors.Wrapf(err, "query %d: %s", i, query)
+		}
+		if !continueOnError {
+			break
+		}
+	}
+	return results, firstErr
+}
+
+// execute runs the given request over the connection of the session. The requests of one session are serialized.
+func (s *session) execute(query string, request func(client interfaces.QueryExecutor) ([]interfaces.Response, error)) ([]interfaces.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil, fmt.Errorf("Can't execute the query, the session is closed")
+	}
+
+	c := s.cosmos
+	done, err := c.beginQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	start := time.Now()
+	responses, err := c.executeWithRetry(context.Background(), func() ([]interfaces.Response, error) {
+		return request(s.conn.client)
+	})
+	c.metrics.queryDurationSeconds.Observe(time.Since(start).Seconds())
+	c.recordQuery(query, start, responses, err)
+	return responses, err
+}
+
+func (s *session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	// give the connection back to the pool
+	s.conn.Close()
+	s.conn = nil
+	return nil
+}
@@ -0,0 +1,177 @@
+package gremcos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/api"
+	"github.com/supplyon/gremcos/interfaces"
+	mock_interfaces "github.com/supplyon/gremcos/test/mocks/interfaces"
+)
+
+// newCosmosWithSessionPool returns a cosmos using a pool that creates the given query executors (one per connection)
+func newCosmosWithSessionPool(t *testing.T, mockCtrl *gomock.Controller, queryExecutors ...interfaces.QueryExecutor) (*cosmosImpl, *pool) {
+	metrics, metricMocks := NewMockedMetrics(mockCtrl)
+	expectAnyMetricUpdates(mockCtrl, metricMocks)
+	cosmos, err := New("ws://host", withMetrics(metrics))
+	require.NoError(t, err)
+
+	created := 0
+	pool, err := NewPool(func() (interfaces.QueryExecutor, error) {
+		queryExecutor := queryExecutors[created]
+		created++
+		return queryExecutor, nil
+	}, len(queryExecutors), time.Second*30, zerolog.Nop())
+	require.NoError(t, err)
+
+	cImpl := toCosmosImpl(t, cosmos)
+	cImpl.pool = pool
+	return cImpl, pool
+}
+
+func TestSessionAffinity(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	sessionConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	otherConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, pool := newCosmosWithSessionPool(t, mockCtrl, sessionConnection, otherConnection)
+	success := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+
+	sessionConnection.EXPECT().Execute("g.V().count()").Return(success, nil).Times(2)
+	sessionConnection.EXPECT().ExecuteWithBindings("g.V(id)", map[string]interface{}{"id": "1"}, nil).Return(success, nil)
+	sessionConnection.EXPECT().Execute("g.V()").Return(success, nil)
+	otherConnection.EXPECT().Execute("g.E()").Return(success, nil)
+	otherConnection.EXPECT().LastError().Return(nil).AnyTimes()
+	otherConnection.EXPECT().IsConnected().Return(true).AnyTimes()
+
+	// WHEN
+	session, err := cosmos.NewSession()
+	require.NoError(t, err)
+	_, err1 := session.Execute("g.V().count()")
+	// a request outside of the session must not use the pinned connection
+	_, errOther := cosmos.Execute("g.E()")
+	_, err2 := session.ExecuteWithBindings("g.V(id)", map[string]interface{}{"id": "1"}, nil)
+	_, err3 := session.ExecuteQuery(api.NewSimpleQB("g.V().count()"))
+	statsInSession := pool.Stats()
+	_, err4 := session.Execute("g.V()")
+	errClose := session.Close()
+	statsAfterSession := pool.Stats()
+
+	// THEN
+	assert.NoError(t, err1)
+	assert.NoError(t, errOther)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.NoError(t, err4)
+	assert.NoError(t, errClose)
+	assert.Equal(t, 1, statsInSession.InUse, "the connection of the session has to be in use")
+	assert.Equal(t, 0, statsAfterSession.InUse)
+	assert.Equal(t, 2, statsAfterSession.Idle)
+}
+
+func TestSessionClosed(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	sessionConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, _ := newCosmosWithSessionPool(t, mockCtrl, sessionConnection)
+	session, err := cosmos.NewSession()
+	require.NoError(t, err)
+
+	// WHEN
+	errClose := session.Close()
+	errCloseAgain := session.Close()
+	_, errExecute := session.Execute("g.V()")
+
+	// THEN
+	assert.NoError(t, errClose)
+	assert.NoError(t, errCloseAgain)
+	assert.Error(t, errExecute)
+}
+
+func TestExecuteSequential(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	batchConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	otherConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	cosmos, pool := newCosmosWithSessionPool(t, mockCtrl, batchConnection, otherConnection)
+	queries := []string{
+		`g.addV("user").property("name","hans")`,
+		`g.addV("user").property("name","peter")`,
+		`g.addV("user").property("name","rudi")`,
+	}
+	for _, query := range queries {
+		batchConnection.EXPECT().Execute(query).Return([]interfaces.Response{{RequestID: query, Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+	}
+
+	// WHEN
+	results, err := cosmos.ExecuteSequential(queries, false)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, results, len(queries))
+	for i, query := range queries {
+		require.Len(t, results[i], 1)
+		assert.Equal(t, query, results[i][0].RequestID)
+	}
+	stats := pool.Stats()
+	assert.Equal(t, 0, stats.InUse)
+	assert.Equal(t, 1, stats.Idle, "only one connection should have been used")
+}
+
+func TestExecuteSequentialFail(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	success := []interfaces.Response{{Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	queries := []string{"g.V(1)", "g.V(2)", "g.V(3)"}
+
+	stopConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	stopConnection.EXPECT().Execute("g.V(1)").Return(success, nil)
+	stopConnection.EXPECT().Execute("g.V(2)").Return(nil, fmt.Errorf("failed"))
+	stopConnection.EXPECT().LastError().Return(nil).AnyTimes()
+	stopConnection.EXPECT().IsConnected().Return(true).AnyTimes()
+	stopCosmos, _ := newCosmosWithSessionPool(t, mockCtrl, stopConnection)
+
+	continueConnection := mock_interfaces.NewMockQueryExecutor(mockCtrl)
+	continueConnection.EXPECT().Execute("g.V(1)").Return(nil, fmt.Errorf("failed"))
+	continueConnection.EXPECT().Execute("g.V(2)").Return(success, nil)
+	continueConnection.EXPECT().Execute("g.V(3)").Return(success, nil)
+	continueConnection.EXPECT().LastError().Return(nil).AnyTimes()
+	continueConnection.EXPECT().IsConnected().Return(true).AnyTimes()
+	continueCosmos, _ := newCosmosWithSessionPool(t, mockCtrl, continueConnection)
+
+	// WHEN
+	stopResults, stopErr := stopCosmos.ExecuteSequential(queries, false)
+	continueResults, continueErr := continueCosmos.ExecuteSequential(queries, true)
+
+	// THEN
+	require.Error(t, stopErr)
+	assert.Contains(t, stopErr.Error(), "query 1: g.V(2)")
+	assert.Len(t, stopResults, 2, "execution has to stop at the failing query")
+	require.Error(t, continueErr)
+	assert.Contains(t, continueErr.Error(), "query 0: g.V(1)")
+	require.Len(t, continueResults, 3)
+	assert.Equal(t, success, continueResults[2])
+}
+
+func TestNewSessionNotSupported(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, _ := newCosmosWithMockedPool(t, mockCtrl)
+
+	// WHEN
+	session, err := cosmos.NewSession()
+
+	// THEN
+	assert.Error(t, err)
+	assert.Nil(t, session)
+}
@@ -0,0 +1,133 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AbandonedQueriesError is returned by StopWithContext in case the context expired before all in-flight queries were completed.
+type AbandonedQueriesError struct {
+	// Abandoned is the number of queries that were still in-flight when the connections were closed
+	Abandoned int
+	// Queries are the (redacted) queries that were still in-flight when the connections were closed, the oldest first
+	Queries []string
+	// Cause is the reason why the context is done
+	Cause error
+}
+
+func (abandonedErr AbandonedQueriesError) Error() string {
+	if len(abandonedErr.Queries) == 0 {
+		return fmt.Sprintf("graceful stop aborted (%v), %d in-flight queries have been abandoned", abandonedErr.Cause, abandonedErr.Abandoned)
+	}
+	return fmt.Sprintf("graceful stop aborted (%v), %d in-flight queries have been abandoned: %s", abandonedErr.Cause, abandonedErr.Abandoned, strings.Join(abandonedErr.Queries, ", "))
+}
+
+// inFlightQuery is a query that is currently executed
+type inFlightQuery struct {
+	query string
+	start time.Time
+}
+
+// beginQuery registers the given query as in-flight. The returned function has to be called as soon as the query
+// is completed. An error is returned in case the connector is stopping, then the query must not be executed.
+// In case the number of concurrent requests is limited (see WithMaxConcurrentRequests) beginQuery waits for a free slot
+// or fails, depending on the mode.
+func (c *cosmosImpl) beginQuery(query string) (func(), error) {
+	// acquired before locking, since waiting for a free slot must not block the completion of other queries
+	if err := c.limiter.acquire(); err != nil {
+		return nil, err
+	}
+
+	c.inFlightMux.Lock()
+	defer c.inFlightMux.Unlock()
+
+	if c.stopping {
+		c.limiter.release()
+		return nil, fmt.Errorf("Can't execute the query, the connector is stopping")
+	}
+
+	probe, err := c.breaker.allow()
+	if err != nil {
+		c.limiter.release()
+		return nil, err
+	}
+	c.inFlight.Add(1)
+	atomic.AddInt32(&c.numInFlight, 1)
+
+	if c.inFlightQueries == nil {
+		c.inFlightQueries = make(map[uint64]inFlightQuery)
+	}
+	c.lastInFlightID++
+	id := c.lastInFlightID
+	c.inFlightQueries[id] = inFlightQuery{query: query, start: time.Now()}
+	c.logQuery(query)
+
+	return func() {
+		if probe {
+			c.breaker.endProbe()
+		}
+		c.endQuery(id)
+		c.limiter.release()
+	}, nil
+}
+
+// endQuery marks the in-flight query with the given id as completed
+func (c *cosmosImpl) endQuery(id uint64) {
+	c.inFlightMux.Lock()
+	delete(c.inFlightQueries, id)
+	c.inFlightMux.Unlock()
+
+	atomic.AddInt32(&c.numInFlight, -1)
+	c.inFlight.Done()
+}
+
+// listInFlightQueries returns the (redacted) queries that are currently in-flight, the oldest first
+func (c *cosmosImpl) listInFlightQueries() []string {
+	c.inFlightMux.Lock()
+	defer c.inFlightMux.Unlock()
+
+	queries := make([]inFlightQuery, 0, len(c.inFlightQueries))
+	for _, query := range c.inFlightQueries {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].start.Before(queries[j].start) })
+
+	result := make([]string, 0, len(queries))
+	for _, query := range queries {
+		result = append(result, RedactValues(query.query))
+	}
+	return result
+}
+
+// StopWithContext stops accepting new queries, waits until all in-flight queries are completed and then stops the connector
+// (like Stop). In case the given context is done before, the connector is stopped anyway and an AbandonedQueriesError
+// containing the in-flight queries is returned.
+func (c *cosmosImpl) StopWithContext(ctx context.Context) error {
+	c.inFlightMux.Lock()
+	c.stopping = true
+	c.inFlightMux.Unlock()
+
+	c.logger.Info().Int32("inFlight", atomic.LoadInt32(&c.numInFlight)).Msg("Graceful teardown requested, waiting for in-flight queries")
+
+	drained := make(chan struct{})
+	go func() {
+		c.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return c.Stop()
+	case <-ctx.Done():
+		abandoned := int(atomic.LoadInt32(&c.numInFlight))
+		queries := c.listInFlightQueries()
+		if err := c.Stop(); err != nil {
+			c.logger.Error().Err(err).Msg("Failed to stop the connector")
+		}
+		return AbandonedQueriesError{Abandoned: abandoned, Queries: queries, Cause: ctx.Err()}
+	}
+}
@@ -0,0 +1,162 @@
+package gremcos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+func TestStopWithContext(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V()"
+	success := []interfaces.Response{{RequestID: "ok", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+	queryStarted := make(chan struct{})
+	mockedQueryExecutor.EXPECT().Execute(query).DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		close(queryStarted)
+		time.Sleep(time.Millisecond * 50)
+		return success, nil
+	})
+	mockedQueryExecutor.EXPECT().Close().Return(nil)
+
+	var responses []interfaces.Response
+	var queryErr error
+	queryDone := make(chan struct{})
+	go func() {
+		defer close(queryDone)
+		responses, queryErr = cosmos.Execute(query)
+	}()
+	<-queryStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	// WHEN
+	err := cosmos.StopWithContext(ctx)
+
+	// THEN
+	assert.NoError(t, err)
+	<-queryDone
+	assert.NoError(t, queryErr)
+	assert.Equal(t, success, responses)
+
+	// new queries are rejected
+	_, err = cosmos.Execute(query)
+	assert.Error(t, err)
+}
+
+func TestStopWithContextExpired(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V()"
+	queryStarted := make(chan struct{})
+	releaseQuery := make(chan struct{})
+	mockedQueryExecutor.EXPECT().Execute(query).DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		close(queryStarted)
+		<-releaseQuery
+		return nil, nil
+	})
+	mockedQueryExecutor.EXPECT().Close().Return(nil)
+
+	queryDone := make(chan struct{})
+	go func() {
+		defer close(queryDone)
+		cosmos.Execute(query)
+	}()
+	<-queryStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	// WHEN
+	err := cosmos.StopWithContext(ctx)
+
+	// THEN
+	require.Error(t, err)
+	abandonedErr, ok := errors.Cause(err).(AbandonedQueriesError)
+	require.True(t, ok, "expected an AbandonedQueriesError but got %T", err)
+	assert.Equal(t, 1, abandonedErr.Abandoned)
+	assert.Equal(t, context.DeadlineExceeded, abandonedErr.Cause)
+
+	close(releaseQuery)
+	<-queryDone
+}
+
+func TestStopWithContextWaitsForSlowQuery(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V().has(\"name\",\"hans\")"
+	queryDuration := time.Millisecond * 100
+	queryStarted := make(chan struct{})
+	mockedQueryExecutor.EXPECT().Execute(query).DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		close(queryStarted)
+		time.Sleep(queryDuration)
+		return nil, nil
+	})
+	mockedQueryExecutor.EXPECT().Close().Return(nil)
+
+	go cosmos.Execute(query)
+	<-queryStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	// WHEN
+	start := time.Now()
+	err := cosmos.StopWithContext(ctx)
+
+	// THEN
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= queryDuration/2, "StopWithContext returned before the in-flight query was completed")
+}
+
+func TestStopWithContextListsAbandonedQueries(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl)
+	query := "g.V().has(\"name\",\"hans\")"
+	queryStarted := make(chan struct{})
+	releaseQuery := make(chan struct{})
+	mockedQueryExecutor.EXPECT().Execute(query).DoAndReturn(func(query string) ([]interfaces.Response, error) {
+		close(queryStarted)
+		<-releaseQuery
+		return nil, nil
+	})
+	mockedQueryExecutor.EXPECT().Close().Return(nil)
+
+	queryDone := make(chan struct{})
+	go func() {
+		defer close(queryDone)
+		cosmos.Execute(query)
+	}()
+	<-queryStarted
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	// WHEN
+	err := cosmos.StopWithContext(ctx)
+
+	// THEN
+	require.Error(t, err)
+	abandonedErr, ok := errors.Cause(err).(AbandonedQueriesError)
+	require.True(t, ok, "expected an AbandonedQueriesError but got %T", err)
+	assert.Equal(t, []string{"g.V().has(\"name\",\"***\")"}, abandonedErr.Queries)
+	assert.Contains(t, err.Error(), "g.V().has(\"name\",\"***\")")
+
+	close(releaseQuery)
+	<-queryDone
+}
@@ -5,9 +5,12 @@
 package mock_gremcos
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	gremcos "github.com/supplyon/gremcos"
 	interfaces "github.com/supplyon/gremcos/interfaces"
 )
 
@@ -34,6 +37,50 @@ func (m *MockCosmos) EXPECT() *MockCosmosMockRecorder {
 	return m.recorder
 }
 
+// BulkAddVertices mocks base method.
+func (m *MockCosmos) BulkAddVertices(label string, rows []map[string]interface{}, concurrency int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkAddVertices", label, rows, concurrency)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkAddVertices indicates an expected call of BulkAddVertices.
+func (mr *MockCosmosMockRecorder) BulkAddVertices(label, rows, concurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkAddVertices", reflect.TypeOf((*MockCosmos)(nil).BulkAddVertices), label, rows, concurrency)
+}
+
+// CircuitBreakerState mocks base method.
+func (m *MockCosmos) CircuitBreakerState() gremcos.CircuitState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitBreakerState")
+	ret0, _ := ret[0].(gremcos.CircuitState)
+	return ret0
+}
+
+// CircuitBreakerState indicates an expected call of CircuitBreakerState.
+func (mr *MockCosmosMockRecorder) CircuitBreakerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitBreakerState", reflect.TypeOf((*MockCosmos)(nil).CircuitBreakerState))
+}
+
+// EstimateCost mocks base method.
+func (m *MockCosmos) EstimateCost(query string) (gremcos.CostEstimate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateCost", query)
+	ret0, _ := ret[0].(gremcos.CostEstimate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateCost indicates an expected call of EstimateCost.
+func (mr *MockCosmosMockRecorder) EstimateCost(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateCost", reflect.TypeOf((*MockCosmos)(nil).EstimateCost), query)
+}
+
 // Execute mocks base method.
 func (m *MockCosmos) Execute(query string) ([]interfaces.Response, error) {
 	m.ctrl.T.Helper()
@@ -63,6 +110,49 @@ func (mr *MockCosmosMockRecorder) ExecuteAsync(query, responseChannel interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAsync", reflect.TypeOf((*MockCosmos)(nil).ExecuteAsync), query, responseChannel)
 }
 
+// ExecuteAsyncWithContext mocks base method.
+func (m *MockCosmos) ExecuteAsyncWithContext(ctx context.Context, query string, responseChannel chan interfaces.AsyncResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAsyncWithContext", ctx, query, responseChannel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteAsyncWithContext indicates an expected call of ExecuteAsyncWithContext.
+func (mr *MockCosmosMockRecorder) ExecuteAsyncWithContext(ctx, query, responseChannel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAsyncWithContext", reflect.TypeOf((*MockCosmos)(nil).ExecuteAsyncWithContext), ctx, query, responseChannel)
+}
+
+// ExecuteAsyncWithID mocks base method.
+func (m *MockCosmos) ExecuteAsyncWithID(requestID, query string, responseChannel chan interfaces.AsyncResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAsyncWithID", requestID, query, responseChannel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteAsyncWithID indicates an expected call of ExecuteAsyncWithID.
+func (mr *MockCosmosMockRecorder) ExecuteAsyncWithID(requestID, query, responseChannel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAsyncWithID", reflect.TypeOf((*MockCosmos)(nil).ExecuteAsyncWithID), requestID, query, responseChannel)
+}
+
+// ExecuteBatch mocks base method.
+func (m *MockCosmos) ExecuteBatch(queries []string) ([][]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteBatch", queries)
+	ret0, _ := ret[0].([][]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteBatch indicates an expected call of ExecuteBatch.
+func (mr *MockCosmosMockRecorder) ExecuteBatch(queries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBatch", reflect.TypeOf((*MockCosmos)(nil).ExecuteBatch), queries)
+}
+
 // ExecuteQuery mocks base method.
 func (m *MockCosmos) ExecuteQuery(query interfaces.QueryBuilder) ([]interfaces.Response, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +168,35 @@ func (mr *MockCosmosMockRecorder) ExecuteQuery(query interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteQuery", reflect.TypeOf((*MockCosmos)(nil).ExecuteQuery), query)
 }
 
+// ExecuteSequential mocks base method.
+func (m *MockCosmos) ExecuteSequential(queries []string, continueOnError bool) ([][]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteSequential", queries, continueOnError)
+	ret0, _ := ret[0].([][]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteSequential indicates an expected call of ExecuteSequential.
+func (mr *MockCosmosMockRecorder) ExecuteSequential(queries, continueOnError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteSequential", reflect.TypeOf((*MockCosmos)(nil).ExecuteSequential), queries, continueOnError)
+}
+
+// ExecuteStream mocks base method.
+func (m *MockCosmos) ExecuteStream(query string, handler func(interfaces.Response) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteStream", query, handler)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteStream indicates an expected call of ExecuteStream.
+func (mr *MockCosmosMockRecorder) ExecuteStream(query, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteStream", reflect.TypeOf((*MockCosmos)(nil).ExecuteStream), query, handler)
+}
+
 // ExecuteWithBindings mocks base method.
 func (m *MockCosmos) ExecuteWithBindings(path string, bindings, rebindings map[string]interface{}) ([]interfaces.Response, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +212,65 @@ func (mr *MockCosmosMockRecorder) ExecuteWithBindings(path, bindings, rebindings
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithBindings", reflect.TypeOf((*MockCosmos)(nil).ExecuteWithBindings), path, bindings, rebindings)
 }
 
+// ExecuteWithContext mocks base method.
+func (m *MockCosmos) ExecuteWithContext(ctx context.Context, query string) ([]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteWithContext", ctx, query)
+	ret0, _ := ret[0].([]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteWithContext indicates an expected call of ExecuteWithContext.
+func (mr *MockCosmosMockRecorder) ExecuteWithContext(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithContext", reflect.TypeOf((*MockCosmos)(nil).ExecuteWithContext), ctx, query)
+}
+
+// ExecuteWithID mocks base method.
+func (m *MockCosmos) ExecuteWithID(requestID, query string) ([]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteWithID", requestID, query)
+	ret0, _ := ret[0].([]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteWithID indicates an expected call of ExecuteWithID.
+func (mr *MockCosmosMockRecorder) ExecuteWithID(requestID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithID", reflect.TypeOf((*MockCosmos)(nil).ExecuteWithID), requestID, query)
+}
+
+// GroupCount mocks base method.
+func (m *MockCosmos) GroupCount(query string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GroupCount", query)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupCount indicates an expected call of GroupCount.
+func (mr *MockCosmosMockRecorder) GroupCount(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupCount", reflect.TypeOf((*MockCosmos)(nil).GroupCount), query)
+}
+
+// HealthStatus mocks base method.
+func (m *MockCosmos) HealthStatus() gremcos.HealthStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthStatus")
+	ret0, _ := ret[0].(gremcos.HealthStatus)
+	return ret0
+}
+
+// HealthStatus indicates an expected call of HealthStatus.
+func (mr *MockCosmosMockRecorder) HealthStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthStatus", reflect.TypeOf((*MockCosmos)(nil).HealthStatus))
+}
+
 // IsConnected mocks base method.
 func (m *MockCosmos) IsConnected() bool {
 	m.ctrl.T.Helper()
@@ -121,6 +299,65 @@ func (mr *MockCosmosMockRecorder) IsHealthy() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsHealthy", reflect.TypeOf((*MockCosmos)(nil).IsHealthy))
 }
 
+// NewSession mocks base method.
+func (m *MockCosmos) NewSession() (gremcos.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSession")
+	ret0, _ := ret[0].(gremcos.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewSession indicates an expected call of NewSession.
+func (mr *MockCosmosMockRecorder) NewSession() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSession", reflect.TypeOf((*MockCosmos)(nil).NewSession))
+}
+
+// PageWithTotal mocks base method.
+func (m *MockCosmos) PageWithTotal(baseQuery string, pageSize, pageNum int) ([]interfaces.Response, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PageWithTotal", baseQuery, pageSize, pageNum)
+	ret0, _ := ret[0].([]interfaces.Response)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PageWithTotal indicates an expected call of PageWithTotal.
+func (mr *MockCosmosMockRecorder) PageWithTotal(baseQuery, pageSize, pageNum interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PageWithTotal", reflect.TypeOf((*MockCosmos)(nil).PageWithTotal), baseQuery, pageSize, pageNum)
+}
+
+// QueryHistory mocks base method.
+func (m *MockCosmos) QueryHistory() []gremcos.QueryRecord {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryHistory")
+	ret0, _ := ret[0].([]gremcos.QueryRecord)
+	return ret0
+}
+
+// QueryHistory indicates an expected call of QueryHistory.
+func (mr *MockCosmosMockRecorder) QueryHistory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryHistory", reflect.TypeOf((*MockCosmos)(nil).QueryHistory))
+}
+
+// Stats mocks base method.
+func (m *MockCosmos) Stats() gremcos.PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(gremcos.PoolStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockCosmosMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockCosmos)(nil).Stats))
+}
+
 // Stop mocks base method.
 func (m *MockCosmos) Stop() error {
 	m.ctrl.T.Helper()
@@ -135,6 +372,20 @@ func (mr *MockCosmosMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCosmos)(nil).Stop))
 }
 
+// StopWithContext mocks base method.
+func (m *MockCosmos) StopWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopWithContext indicates an expected call of StopWithContext.
+func (mr *MockCosmosMockRecorder) StopWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWithContext", reflect.TypeOf((*MockCosmos)(nil).StopWithContext), ctx)
+}
+
 // String mocks base method.
 func (m *MockCosmos) String() string {
 	m.ctrl.T.Helper()
@@ -148,3 +399,69 @@ func (mr *MockCosmosMockRecorder) String() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockCosmos)(nil).String))
 }
+
+// VerticesInBoundingBox mocks base method.
+func (m *MockCosmos) VerticesInBoundingBox(label, latKey, lonKey string, minLat, maxLat, minLon, maxLon float64) ([]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerticesInBoundingBox", label, latKey, lonKey, minLat, maxLat, minLon, maxLon)
+	ret0, _ := ret[0].([]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerticesInBoundingBox indicates an expected call of VerticesInBoundingBox.
+func (mr *MockCosmosMockRecorder) VerticesInBoundingBox(label, latKey, lonKey, minLat, maxLat, minLon, maxLon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerticesInBoundingBox", reflect.TypeOf((*MockCosmos)(nil).VerticesInBoundingBox), label, latKey, lonKey, minLat, maxLat, minLon, maxLon)
+}
+
+// WaitUntilHealthy mocks base method.
+func (m *MockCosmos) WaitUntilHealthy(ctx context.Context, interval time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitUntilHealthy", ctx, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitUntilHealthy indicates an expected call of WaitUntilHealthy.
+func (mr *MockCosmosMockRecorder) WaitUntilHealthy(ctx, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitUntilHealthy", reflect.TypeOf((*MockCosmos)(nil).WaitUntilHealthy), ctx, interval)
+}
+
+// MockpoolStatsProvider is a mock of poolStatsProvider interface.
+type MockpoolStatsProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockpoolStatsProviderMockRecorder
+}
+
+// MockpoolStatsProviderMockRecorder is the mock recorder for MockpoolStatsProvider.
+type MockpoolStatsProviderMockRecorder struct {
+	mock *MockpoolStatsProvider
+}
+
+// NewMockpoolStatsProvider creates a new mock instance.
+func NewMockpoolStatsProvider(ctrl *gomock.Controller) *MockpoolStatsProvider {
+	mock := &MockpoolStatsProvider{ctrl: ctrl}
+	mock.recorder = &MockpoolStatsProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpoolStatsProvider) EXPECT() *MockpoolStatsProviderMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockpoolStatsProvider) Stats() gremcos.PoolStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(gremcos.PoolStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockpoolStatsProviderMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockpoolStatsProvider)(nil).Stats))
+}
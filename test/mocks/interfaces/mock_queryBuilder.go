@@ -100,6 +100,70 @@ func (mr *MockGraphMockRecorder) E() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "E", reflect.TypeOf((*MockGraph)(nil).E))
 }
 
+// EById mocks base method.
+func (m *MockGraph) EById(ids ...interface{}) interfaces.Edge {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EById", varargs...)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// EById indicates an expected call of EById.
+func (mr *MockGraphMockRecorder) EById(ids ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EById", reflect.TypeOf((*MockGraph)(nil).EById), ids...)
+}
+
+// Inject mocks base method.
+func (m *MockGraph) Inject(values ...interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Inject", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Inject indicates an expected call of Inject.
+func (mr *MockGraphMockRecorder) Inject(values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inject", reflect.TypeOf((*MockGraph)(nil).Inject), values...)
+}
+
+// MergeE mocks base method.
+func (m *MockGraph) MergeE(searchCriteria map[interface{}]interface{}) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeE", searchCriteria)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// MergeE indicates an expected call of MergeE.
+func (mr *MockGraphMockRecorder) MergeE(searchCriteria interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeE", reflect.TypeOf((*MockGraph)(nil).MergeE), searchCriteria)
+}
+
+// MergeV mocks base method.
+func (m *MockGraph) MergeV(searchCriteria map[interface{}]interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeV", searchCriteria)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// MergeV indicates an expected call of MergeV.
+func (mr *MockGraphMockRecorder) MergeV(searchCriteria interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeV", reflect.TypeOf((*MockGraph)(nil).MergeV), searchCriteria)
+}
+
 // String mocks base method.
 func (m *MockGraph) String() string {
 	m.ctrl.T.Helper()
@@ -142,6 +206,24 @@ func (mr *MockGraphMockRecorder) VBy(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VBy", reflect.TypeOf((*MockGraph)(nil).VBy), id)
 }
 
+// VById mocks base method.
+func (m *MockGraph) VById(ids ...interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VById", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// VById indicates an expected call of VById.
+func (mr *MockGraphMockRecorder) VById(ids ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VById", reflect.TypeOf((*MockGraph)(nil).VById), ids...)
+}
+
 // VByStr mocks base method.
 func (m *MockGraph) VByStr(id string) interfaces.Vertex {
 	m.ctrl.T.Helper()
@@ -221,6 +303,38 @@ func (mr *MockVertexMockRecorder) AddE(label interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddE", reflect.TypeOf((*MockVertex)(nil).AddE), label)
 }
 
+// Aggregate mocks base method.
+func (m *MockVertex) Aggregate(key string) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Aggregate", key)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Aggregate indicates an expected call of Aggregate.
+func (mr *MockVertexMockRecorder) Aggregate(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockVertex)(nil).Aggregate), key)
+}
+
+// And mocks base method.
+func (m *MockVertex) And(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range traversals {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "And", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// And indicates an expected call of And.
+func (mr *MockVertexMockRecorder) And(traversals ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "And", reflect.TypeOf((*MockVertex)(nil).And), traversals...)
+}
+
 // As mocks base method.
 func (m *MockVertex) As(labels ...string) interfaces.Vertex {
 	m.ctrl.T.Helper()
@@ -239,6 +353,126 @@ func (mr *MockVertexMockRecorder) As(labels ...interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "As", reflect.TypeOf((*MockVertex)(nil).As), labels...)
 }
 
+// Barrier mocks base method.
+func (m *MockVertex) Barrier(maxBarrierSize ...int) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range maxBarrierSize {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Barrier", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Barrier indicates an expected call of Barrier.
+func (mr *MockVertexMockRecorder) Barrier(maxBarrierSize ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Barrier", reflect.TypeOf((*MockVertex)(nil).Barrier), maxBarrierSize...)
+}
+
+// By mocks base method.
+func (m *MockVertex) By(key string, order ...interfaces.Order) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{key}
+	for _, a := range order {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "By", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// By indicates an expected call of By.
+func (mr *MockVertexMockRecorder) By(key interface{}, order ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{key}, order...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "By", reflect.TypeOf((*MockVertex)(nil).By), varargs...)
+}
+
+// ByTraversal mocks base method.
+func (m *MockVertex) ByTraversal(traversal interfaces.QueryBuilder, order ...interfaces.Order) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{traversal}
+	for _, a := range order {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ByTraversal", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// ByTraversal indicates an expected call of ByTraversal.
+func (mr *MockVertexMockRecorder) ByTraversal(traversal interface{}, order ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{traversal}, order...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByTraversal", reflect.TypeOf((*MockVertex)(nil).ByTraversal), varargs...)
+}
+
+// Cap mocks base method.
+func (m *MockVertex) Cap(keys ...string) interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Cap", varargs...)
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Cap indicates an expected call of Cap.
+func (mr *MockVertexMockRecorder) Cap(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cap", reflect.TypeOf((*MockVertex)(nil).Cap), keys...)
+}
+
+// Choose mocks base method.
+func (m *MockVertex) Choose(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range traversals {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Choose", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Choose indicates an expected call of Choose.
+func (mr *MockVertexMockRecorder) Choose(traversals ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Choose", reflect.TypeOf((*MockVertex)(nil).Choose), traversals...)
+}
+
+// CollectInto mocks base method.
+func (m *MockVertex) CollectInto(key string) interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectInto", key)
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// CollectInto indicates an expected call of CollectInto.
+func (mr *MockVertexMockRecorder) CollectInto(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectInto", reflect.TypeOf((*MockVertex)(nil).CollectInto), key)
+}
+
+// Constant mocks base method.
+func (m *MockVertex) Constant(value interface{}) interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Constant", value)
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Constant indicates an expected call of Constant.
+func (mr *MockVertexMockRecorder) Constant(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Constant", reflect.TypeOf((*MockVertex)(nil).Constant), value)
+}
+
 // Count mocks base method.
 func (m *MockVertex) Count() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -253,6 +487,76 @@ func (mr *MockVertexMockRecorder) Count() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockVertex)(nil).Count))
 }
 
+// CountLocal mocks base method.
+func (m *MockVertex) CountLocal() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocal")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// CountLocal indicates an expected call of CountLocal.
+func (mr *MockVertexMockRecorder) CountLocal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocal", reflect.TypeOf((*MockVertex)(nil).CountLocal))
+}
+
+// CyclicPath mocks base method.
+func (m *MockVertex) CyclicPath() interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CyclicPath")
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// CyclicPath indicates an expected call of CyclicPath.
+func (mr *MockVertexMockRecorder) CyclicPath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CyclicPath", reflect.TypeOf((*MockVertex)(nil).CyclicPath))
+}
+
+// Dedup mocks base method.
+func (m *MockVertex) Dedup() interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dedup")
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Dedup indicates an expected call of Dedup.
+func (mr *MockVertexMockRecorder) Dedup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dedup", reflect.TypeOf((*MockVertex)(nil).Dedup))
+}
+
+// DedupBy mocks base method.
+func (m *MockVertex) DedupBy(key string) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DedupBy", key)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// DedupBy indicates an expected call of DedupBy.
+func (mr *MockVertexMockRecorder) DedupBy(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedupBy", reflect.TypeOf((*MockVertex)(nil).DedupBy), key)
+}
+
+// DedupById mocks base method.
+func (m *MockVertex) DedupById() interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DedupById")
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// DedupById indicates an expected call of DedupById.
+func (mr *MockVertexMockRecorder) DedupById() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DedupById", reflect.TypeOf((*MockVertex)(nil).DedupById))
+}
+
 // Drop mocks base method.
 func (m *MockVertex) Drop() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -267,6 +571,38 @@ func (mr *MockVertexMockRecorder) Drop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drop", reflect.TypeOf((*MockVertex)(nil).Drop))
 }
 
+// ElementMap mocks base method.
+func (m *MockVertex) ElementMap(keys ...string) interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ElementMap", varargs...)
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// ElementMap indicates an expected call of ElementMap.
+func (mr *MockVertexMockRecorder) ElementMap(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ElementMap", reflect.TypeOf((*MockVertex)(nil).ElementMap), keys...)
+}
+
+// EmitRepeat mocks base method.
+func (m *MockVertex) EmitRepeat(emitTraversal, traversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitRepeat", emitTraversal, traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// EmitRepeat indicates an expected call of EmitRepeat.
+func (mr *MockVertexMockRecorder) EmitRepeat(emitTraversal, traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitRepeat", reflect.TypeOf((*MockVertex)(nil).EmitRepeat), emitTraversal, traversal)
+}
+
 // Has mocks base method.
 func (m *MockVertex) Has(key string, value ...interface{}) interfaces.Vertex {
 	m.ctrl.T.Helper()
@@ -286,6 +622,21 @@ func (mr *MockVertexMockRecorder) Has(key interface{}, value ...interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Has", reflect.TypeOf((*MockVertex)(nil).Has), varargs...)
 }
 
+// HasE mocks base method.
+func (m *MockVertex) HasE(key string, value interface{}) (interfaces.Vertex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasE", key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasE indicates an expected call of HasE.
+func (mr *MockVertexMockRecorder) HasE(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasE", reflect.TypeOf((*MockVertex)(nil).HasE), key, value)
+}
+
 // HasId mocks base method.
 func (m *MockVertex) HasId(id string) interfaces.Vertex {
 	m.ctrl.T.Helper()
@@ -318,6 +669,34 @@ func (mr *MockVertexMockRecorder) HasLabel(vertexLabel ...interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLabel", reflect.TypeOf((*MockVertex)(nil).HasLabel), vertexLabel...)
 }
 
+// HasLabelPredicate mocks base method.
+func (m *MockVertex) HasLabelPredicate(predicate interfaces.Predicate) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasLabelPredicate", predicate)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// HasLabelPredicate indicates an expected call of HasLabelPredicate.
+func (mr *MockVertexMockRecorder) HasLabelPredicate(predicate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasLabelPredicate", reflect.TypeOf((*MockVertex)(nil).HasLabelPredicate), predicate)
+}
+
+// HasNotValue mocks base method.
+func (m *MockVertex) HasNotValue(key string, value interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasNotValue", key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// HasNotValue indicates an expected call of HasNotValue.
+func (mr *MockVertexMockRecorder) HasNotValue(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasNotValue", reflect.TypeOf((*MockVertex)(nil).HasNotValue), key, value)
+}
+
 // Id mocks base method.
 func (m *MockVertex) Id() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -326,134 +705,614 @@ func (m *MockVertex) Id() interfaces.QueryBuilder {
 	return ret0
 }
 
-// Id indicates an expected call of Id.
-func (mr *MockVertexMockRecorder) Id() *gomock.Call {
+// Id indicates an expected call of Id.
+func (mr *MockVertexMockRecorder) Id() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Id", reflect.TypeOf((*MockVertex)(nil).Id))
+}
+
+// InE mocks base method.
+func (m *MockVertex) InE(labels ...string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range labels {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InE", varargs...)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// InE indicates an expected call of InE.
+func (mr *MockVertexMockRecorder) InE(labels ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InE", reflect.TypeOf((*MockVertex)(nil).InE), labels...)
+}
+
+// IncrementProperty mocks base method.
+func (m *MockVertex) IncrementProperty(key string, by int) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementProperty", key, by)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// IncrementProperty indicates an expected call of IncrementProperty.
+func (mr *MockVertexMockRecorder) IncrementProperty(key, by interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementProperty", reflect.TypeOf((*MockVertex)(nil).IncrementProperty), key, by)
+}
+
+// IncrementPropertyE mocks base method.
+func (m *MockVertex) IncrementPropertyE(key string, by int) (interfaces.Vertex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementPropertyE", key, by)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementPropertyE indicates an expected call of IncrementPropertyE.
+func (mr *MockVertexMockRecorder) IncrementPropertyE(key, by interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPropertyE", reflect.TypeOf((*MockVertex)(nil).IncrementPropertyE), key, by)
+}
+
+// Inject mocks base method.
+func (m *MockVertex) Inject(values ...interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Inject", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Inject indicates an expected call of Inject.
+func (mr *MockVertexMockRecorder) Inject(values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inject", reflect.TypeOf((*MockVertex)(nil).Inject), values...)
+}
+
+// Label mocks base method.
+func (m *MockVertex) Label() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Label")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Label indicates an expected call of Label.
+func (mr *MockVertexMockRecorder) Label() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Label", reflect.TypeOf((*MockVertex)(nil).Label))
+}
+
+// Limit mocks base method.
+func (m *MockVertex) Limit(maxElements int) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit", maxElements)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockVertexMockRecorder) Limit(maxElements interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockVertex)(nil).Limit), maxElements)
+}
+
+// LimitLocal mocks base method.
+func (m *MockVertex) LimitLocal(maxElements int) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LimitLocal", maxElements)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// LimitLocal indicates an expected call of LimitLocal.
+func (mr *MockVertexMockRecorder) LimitLocal(maxElements interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LimitLocal", reflect.TypeOf((*MockVertex)(nil).LimitLocal), maxElements)
+}
+
+// Local mocks base method.
+func (m *MockVertex) Local(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Local", traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Local indicates an expected call of Local.
+func (mr *MockVertexMockRecorder) Local(traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Local", reflect.TypeOf((*MockVertex)(nil).Local), traversal)
+}
+
+// Math mocks base method.
+func (m *MockVertex) Math(expression string) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Math", expression)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Math indicates an expected call of Math.
+func (mr *MockVertexMockRecorder) Math(expression interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Math", reflect.TypeOf((*MockVertex)(nil).Math), expression)
+}
+
+// Max mocks base method.
+func (m *MockVertex) Max() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Max")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Max indicates an expected call of Max.
+func (mr *MockVertexMockRecorder) Max() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Max", reflect.TypeOf((*MockVertex)(nil).Max))
+}
+
+// Mean mocks base method.
+func (m *MockVertex) Mean() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mean")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Mean indicates an expected call of Mean.
+func (mr *MockVertexMockRecorder) Mean() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mean", reflect.TypeOf((*MockVertex)(nil).Mean))
+}
+
+// Min mocks base method.
+func (m *MockVertex) Min() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Min")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Min indicates an expected call of Min.
+func (mr *MockVertexMockRecorder) Min() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Min", reflect.TypeOf((*MockVertex)(nil).Min))
+}
+
+// Not mocks base method.
+func (m *MockVertex) Not(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Not", traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Not indicates an expected call of Not.
+func (mr *MockVertexMockRecorder) Not(traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Not", reflect.TypeOf((*MockVertex)(nil).Not), traversal)
+}
+
+// OnCreate mocks base method.
+func (m *MockVertex) OnCreate(properties map[interface{}]interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnCreate", properties)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// OnCreate indicates an expected call of OnCreate.
+func (mr *MockVertexMockRecorder) OnCreate(properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCreate", reflect.TypeOf((*MockVertex)(nil).OnCreate), properties)
+}
+
+// OnMatch mocks base method.
+func (m *MockVertex) OnMatch(properties map[interface{}]interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnMatch", properties)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// OnMatch indicates an expected call of OnMatch.
+func (mr *MockVertexMockRecorder) OnMatch(properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnMatch", reflect.TypeOf((*MockVertex)(nil).OnMatch), properties)
+}
+
+// Option mocks base method.
+func (m *MockVertex) Option(match interface{}, thenTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Option", match, thenTraversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Option indicates an expected call of Option.
+func (mr *MockVertexMockRecorder) Option(match, thenTraversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Option", reflect.TypeOf((*MockVertex)(nil).Option), match, thenTraversal)
+}
+
+// Or mocks base method.
+func (m *MockVertex) Or(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range traversals {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Or", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Or indicates an expected call of Or.
+func (mr *MockVertexMockRecorder) Or(traversals ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Or", reflect.TypeOf((*MockVertex)(nil).Or), traversals...)
+}
+
+// Order mocks base method.
+func (m *MockVertex) Order() interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Order")
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Order indicates an expected call of Order.
+func (mr *MockVertexMockRecorder) Order() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Order", reflect.TypeOf((*MockVertex)(nil).Order))
+}
+
+// OutE mocks base method.
+func (m *MockVertex) OutE(labels ...string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range labels {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OutE", varargs...)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// OutE indicates an expected call of OutE.
+func (mr *MockVertexMockRecorder) OutE(labels ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutE", reflect.TypeOf((*MockVertex)(nil).OutE), labels...)
+}
+
+// Path mocks base method.
+func (m *MockVertex) Path() interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Path")
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Path indicates an expected call of Path.
+func (mr *MockVertexMockRecorder) Path() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Path", reflect.TypeOf((*MockVertex)(nil).Path))
+}
+
+// Profile mocks base method.
+func (m *MockVertex) Profile() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Profile")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Profile indicates an expected call of Profile.
+func (mr *MockVertexMockRecorder) Profile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockVertex)(nil).Profile))
+}
+
+// Project mocks base method.
+func (m *MockVertex) Project(keys ...string) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Project", varargs...)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Project indicates an expected call of Project.
+func (mr *MockVertexMockRecorder) Project(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Project", reflect.TypeOf((*MockVertex)(nil).Project), keys...)
+}
+
+// Properties mocks base method.
+func (m *MockVertex) Properties(key ...string) interfaces.Property {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range key {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Properties", varargs...)
+	ret0, _ := ret[0].(interfaces.Property)
+	return ret0
+}
+
+// Properties indicates an expected call of Properties.
+func (mr *MockVertexMockRecorder) Properties(key ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Properties", reflect.TypeOf((*MockVertex)(nil).Properties), key...)
+}
+
+// Property mocks base method.
+func (m *MockVertex) Property(key, value interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Property", key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Property indicates an expected call of Property.
+func (mr *MockVertexMockRecorder) Property(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Property", reflect.TypeOf((*MockVertex)(nil).Property), key, value)
+}
+
+// PropertyE mocks base method.
+func (m *MockVertex) PropertyE(key, value interface{}) (interfaces.Vertex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropertyE", key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PropertyE indicates an expected call of PropertyE.
+func (mr *MockVertexMockRecorder) PropertyE(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertyE", reflect.TypeOf((*MockVertex)(nil).PropertyE), key, value)
+}
+
+// PropertyList mocks base method.
+func (m *MockVertex) PropertyList(key, value string) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropertyList", key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// PropertyList indicates an expected call of PropertyList.
+func (mr *MockVertexMockRecorder) PropertyList(key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertyList", reflect.TypeOf((*MockVertex)(nil).PropertyList), key, value)
+}
+
+// PropertyWithCardinality mocks base method.
+func (m *MockVertex) PropertyWithCardinality(cardinality interfaces.Cardinality, key string, value interface{}) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PropertyWithCardinality", cardinality, key, value)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// PropertyWithCardinality indicates an expected call of PropertyWithCardinality.
+func (mr *MockVertexMockRecorder) PropertyWithCardinality(cardinality, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertyWithCardinality", reflect.TypeOf((*MockVertex)(nil).PropertyWithCardinality), cardinality, key, value)
+}
+
+// Repeat mocks base method.
+func (m *MockVertex) Repeat(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repeat", traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Repeat indicates an expected call of Repeat.
+func (mr *MockVertexMockRecorder) Repeat(traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repeat", reflect.TypeOf((*MockVertex)(nil).Repeat), traversal)
+}
+
+// RepeatEmit mocks base method.
+func (m *MockVertex) RepeatEmit(traversal, emitTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepeatEmit", traversal, emitTraversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// RepeatEmit indicates an expected call of RepeatEmit.
+func (mr *MockVertexMockRecorder) RepeatEmit(traversal, emitTraversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepeatEmit", reflect.TypeOf((*MockVertex)(nil).RepeatEmit), traversal, emitTraversal)
+}
+
+// RepeatUntil mocks base method.
+func (m *MockVertex) RepeatUntil(traversal, untilTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepeatUntil", traversal, untilTraversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// RepeatUntil indicates an expected call of RepeatUntil.
+func (mr *MockVertexMockRecorder) RepeatUntil(traversal, untilTraversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepeatUntil", reflect.TypeOf((*MockVertex)(nil).RepeatUntil), traversal, untilTraversal)
+}
+
+// Sample mocks base method.
+func (m *MockVertex) Sample(n int) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sample", n)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// Sample indicates an expected call of Sample.
+func (mr *MockVertexMockRecorder) Sample(n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sample", reflect.TypeOf((*MockVertex)(nil).Sample), n)
+}
+
+// SideEffect mocks base method.
+func (m *MockVertex) SideEffect(traversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SideEffect", traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// SideEffect indicates an expected call of SideEffect.
+func (mr *MockVertexMockRecorder) SideEffect(traversal interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Id", reflect.TypeOf((*MockVertex)(nil).Id))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SideEffect", reflect.TypeOf((*MockVertex)(nil).SideEffect), traversal)
 }
 
-// InE mocks base method.
-func (m *MockVertex) InE(labels ...string) interfaces.Edge {
+// SimplePath mocks base method.
+func (m *MockVertex) SimplePath() interfaces.Vertex {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{}
-	for _, a := range labels {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "InE", varargs...)
-	ret0, _ := ret[0].(interfaces.Edge)
+	ret := m.ctrl.Call(m, "SimplePath")
+	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// InE indicates an expected call of InE.
-func (mr *MockVertexMockRecorder) InE(labels ...interface{}) *gomock.Call {
+// SimplePath indicates an expected call of SimplePath.
+func (mr *MockVertexMockRecorder) SimplePath() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InE", reflect.TypeOf((*MockVertex)(nil).InE), labels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimplePath", reflect.TypeOf((*MockVertex)(nil).SimplePath))
 }
 
-// Limit mocks base method.
-func (m *MockVertex) Limit(maxElements int) interfaces.Vertex {
+// Store mocks base method.
+func (m *MockVertex) Store(key string) interfaces.Vertex {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Limit", maxElements)
+	ret := m.ctrl.Call(m, "Store", key)
 	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// Limit indicates an expected call of Limit.
-func (mr *MockVertexMockRecorder) Limit(maxElements interface{}) *gomock.Call {
+// Store indicates an expected call of Store.
+func (mr *MockVertexMockRecorder) Store(key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockVertex)(nil).Limit), maxElements)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockVertex)(nil).Store), key)
 }
 
-// OutE mocks base method.
-func (m *MockVertex) OutE(labels ...string) interfaces.Edge {
+// String mocks base method.
+func (m *MockVertex) String() string {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{}
-	for _, a := range labels {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "OutE", varargs...)
-	ret0, _ := ret[0].(interfaces.Edge)
+	ret := m.ctrl.Call(m, "String")
+	ret0, _ := ret[0].(string)
 	return ret0
 }
 
-// OutE indicates an expected call of OutE.
-func (mr *MockVertexMockRecorder) OutE(labels ...interface{}) *gomock.Call {
+// String indicates an expected call of String.
+func (mr *MockVertexMockRecorder) String() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutE", reflect.TypeOf((*MockVertex)(nil).OutE), labels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockVertex)(nil).String))
 }
 
-// Profile mocks base method.
-func (m *MockVertex) Profile() interfaces.QueryBuilder {
+// Sum mocks base method.
+func (m *MockVertex) Sum() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Profile")
+	ret := m.ctrl.Call(m, "Sum")
 	ret0, _ := ret[0].(interfaces.QueryBuilder)
 	return ret0
 }
 
-// Profile indicates an expected call of Profile.
-func (mr *MockVertexMockRecorder) Profile() *gomock.Call {
+// Sum indicates an expected call of Sum.
+func (mr *MockVertexMockRecorder) Sum() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Profile", reflect.TypeOf((*MockVertex)(nil).Profile))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sum", reflect.TypeOf((*MockVertex)(nil).Sum))
 }
 
-// Properties mocks base method.
-func (m *MockVertex) Properties(key ...string) interfaces.Property {
+// Times mocks base method.
+func (m *MockVertex) Times(maxLoops int) interfaces.Vertex {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{}
-	for _, a := range key {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "Properties", varargs...)
-	ret0, _ := ret[0].(interfaces.Property)
+	ret := m.ctrl.Call(m, "Times", maxLoops)
+	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// Properties indicates an expected call of Properties.
-func (mr *MockVertexMockRecorder) Properties(key ...interface{}) *gomock.Call {
+// Times indicates an expected call of Times.
+func (mr *MockVertexMockRecorder) Times(maxLoops interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Properties", reflect.TypeOf((*MockVertex)(nil).Properties), key...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Times", reflect.TypeOf((*MockVertex)(nil).Times), maxLoops)
 }
 
-// Property mocks base method.
-func (m *MockVertex) Property(key, value interface{}) interfaces.Vertex {
+// TimesRepeat mocks base method.
+func (m *MockVertex) TimesRepeat(maxLoops int, traversal interfaces.QueryBuilder) interfaces.Vertex {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Property", key, value)
+	ret := m.ctrl.Call(m, "TimesRepeat", maxLoops, traversal)
 	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// Property indicates an expected call of Property.
-func (mr *MockVertexMockRecorder) Property(key, value interface{}) *gomock.Call {
+// TimesRepeat indicates an expected call of TimesRepeat.
+func (mr *MockVertexMockRecorder) TimesRepeat(maxLoops, traversal interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Property", reflect.TypeOf((*MockVertex)(nil).Property), key, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TimesRepeat", reflect.TypeOf((*MockVertex)(nil).TimesRepeat), maxLoops, traversal)
 }
 
-// PropertyList mocks base method.
-func (m *MockVertex) PropertyList(key, value string) interfaces.Vertex {
+// Union mocks base method.
+func (m *MockVertex) Union(traversals ...interfaces.QueryBuilder) interfaces.Vertex {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PropertyList", key, value)
+	varargs := []interface{}{}
+	for _, a := range traversals {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Union", varargs...)
 	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// PropertyList indicates an expected call of PropertyList.
-func (mr *MockVertexMockRecorder) PropertyList(key, value interface{}) *gomock.Call {
+// Union indicates an expected call of Union.
+func (mr *MockVertexMockRecorder) Union(traversals ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PropertyList", reflect.TypeOf((*MockVertex)(nil).PropertyList), key, value)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Union", reflect.TypeOf((*MockVertex)(nil).Union), traversals...)
 }
 
-// String mocks base method.
-func (m *MockVertex) String() string {
+// UntilRepeat mocks base method.
+func (m *MockVertex) UntilRepeat(untilTraversal, traversal interfaces.QueryBuilder) interfaces.Vertex {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "String")
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "UntilRepeat", untilTraversal, traversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
 	return ret0
 }
 
-// String indicates an expected call of String.
-func (mr *MockVertexMockRecorder) String() *gomock.Call {
+// UntilRepeat indicates an expected call of UntilRepeat.
+func (mr *MockVertexMockRecorder) UntilRepeat(untilTraversal, traversal interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockVertex)(nil).String))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntilRepeat", reflect.TypeOf((*MockVertex)(nil).UntilRepeat), untilTraversal, traversal)
+}
+
+// UpsertV mocks base method.
+func (m *MockVertex) UpsertV(matchTraversal, createTraversal interfaces.QueryBuilder) interfaces.Vertex {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertV", matchTraversal, createTraversal)
+	ret0, _ := ret[0].(interfaces.Vertex)
+	return ret0
+}
+
+// UpsertV indicates an expected call of UpsertV.
+func (mr *MockVertexMockRecorder) UpsertV(matchTraversal, createTraversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertV", reflect.TypeOf((*MockVertex)(nil).UpsertV), matchTraversal, createTraversal)
 }
 
 // ValueMap mocks base method.
@@ -470,6 +1329,20 @@ func (mr *MockVertexMockRecorder) ValueMap() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueMap", reflect.TypeOf((*MockVertex)(nil).ValueMap))
 }
 
+// ValueMapWithTokens mocks base method.
+func (m *MockVertex) ValueMapWithTokens() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValueMapWithTokens")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// ValueMapWithTokens indicates an expected call of ValueMapWithTokens.
+func (mr *MockVertexMockRecorder) ValueMapWithTokens() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueMapWithTokens", reflect.TypeOf((*MockVertex)(nil).ValueMapWithTokens))
+}
+
 // Values mocks base method.
 func (m *MockVertex) Values() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -567,6 +1440,20 @@ func (mr *MockEdgeMockRecorder) Count() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockEdge)(nil).Count))
 }
 
+// CountLocal mocks base method.
+func (m *MockEdge) CountLocal() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocal")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// CountLocal indicates an expected call of CountLocal.
+func (mr *MockEdgeMockRecorder) CountLocal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocal", reflect.TypeOf((*MockEdge)(nil).CountLocal))
+}
+
 // Drop mocks base method.
 func (m *MockEdge) Drop() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -582,17 +1469,45 @@ func (mr *MockEdgeMockRecorder) Drop() *gomock.Call {
 }
 
 // From mocks base method.
-func (m *MockEdge) From(v interfaces.Vertex) interfaces.Edge {
+func (m *MockEdge) From(traversal interfaces.QueryBuilder) interfaces.Edge {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "From", v)
+	ret := m.ctrl.Call(m, "From", traversal)
 	ret0, _ := ret[0].(interfaces.Edge)
 	return ret0
 }
 
 // From indicates an expected call of From.
-func (mr *MockEdgeMockRecorder) From(v interface{}) *gomock.Call {
+func (mr *MockEdgeMockRecorder) From(traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "From", reflect.TypeOf((*MockEdge)(nil).From), traversal)
+}
+
+// FromId mocks base method.
+func (m *MockEdge) FromId(id string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FromId", id)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// FromId indicates an expected call of FromId.
+func (mr *MockEdgeMockRecorder) FromId(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "From", reflect.TypeOf((*MockEdge)(nil).From), v)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromId", reflect.TypeOf((*MockEdge)(nil).FromId), id)
+}
+
+// FromLabel mocks base method.
+func (m *MockEdge) FromLabel(stepLabel string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FromLabel", stepLabel)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// FromLabel indicates an expected call of FromLabel.
+func (mr *MockEdgeMockRecorder) FromLabel(stepLabel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromLabel", reflect.TypeOf((*MockEdge)(nil).FromLabel), stepLabel)
 }
 
 // HasId mocks base method.
@@ -669,6 +1584,34 @@ func (mr *MockEdgeMockRecorder) Limit(maxElements interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockEdge)(nil).Limit), maxElements)
 }
 
+// OnCreate mocks base method.
+func (m *MockEdge) OnCreate(properties map[interface{}]interface{}) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnCreate", properties)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// OnCreate indicates an expected call of OnCreate.
+func (mr *MockEdgeMockRecorder) OnCreate(properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCreate", reflect.TypeOf((*MockEdge)(nil).OnCreate), properties)
+}
+
+// OnMatch mocks base method.
+func (m *MockEdge) OnMatch(properties map[interface{}]interface{}) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnMatch", properties)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// OnMatch indicates an expected call of OnMatch.
+func (mr *MockEdgeMockRecorder) OnMatch(properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnMatch", reflect.TypeOf((*MockEdge)(nil).OnMatch), properties)
+}
+
 // OutV mocks base method.
 func (m *MockEdge) OutV() interfaces.Vertex {
 	m.ctrl.T.Helper()
@@ -712,17 +1655,45 @@ func (mr *MockEdgeMockRecorder) String() *gomock.Call {
 }
 
 // To mocks base method.
-func (m *MockEdge) To(v interfaces.Vertex) interfaces.Edge {
+func (m *MockEdge) To(traversal interfaces.QueryBuilder) interfaces.Edge {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "To", v)
+	ret := m.ctrl.Call(m, "To", traversal)
 	ret0, _ := ret[0].(interfaces.Edge)
 	return ret0
 }
 
 // To indicates an expected call of To.
-func (mr *MockEdgeMockRecorder) To(v interface{}) *gomock.Call {
+func (mr *MockEdgeMockRecorder) To(traversal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "To", reflect.TypeOf((*MockEdge)(nil).To), traversal)
+}
+
+// ToId mocks base method.
+func (m *MockEdge) ToId(id string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToId", id)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// ToId indicates an expected call of ToId.
+func (mr *MockEdgeMockRecorder) ToId(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "To", reflect.TypeOf((*MockEdge)(nil).To), v)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToId", reflect.TypeOf((*MockEdge)(nil).ToId), id)
+}
+
+// ToLabel mocks base method.
+func (m *MockEdge) ToLabel(stepLabel string) interfaces.Edge {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToLabel", stepLabel)
+	ret0, _ := ret[0].(interfaces.Edge)
+	return ret0
+}
+
+// ToLabel indicates an expected call of ToLabel.
+func (mr *MockEdgeMockRecorder) ToLabel(stepLabel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToLabel", reflect.TypeOf((*MockEdge)(nil).ToLabel), stepLabel)
 }
 
 // MockProperty is a mock of Property interface.
@@ -794,6 +1765,20 @@ func (mr *MockPropertyMockRecorder) Count() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockProperty)(nil).Count))
 }
 
+// CountLocal mocks base method.
+func (m *MockProperty) CountLocal() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocal")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// CountLocal indicates an expected call of CountLocal.
+func (mr *MockPropertyMockRecorder) CountLocal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocal", reflect.TypeOf((*MockProperty)(nil).CountLocal))
+}
+
 // Drop mocks base method.
 func (m *MockProperty) Drop() interfaces.QueryBuilder {
 	m.ctrl.T.Helper()
@@ -850,6 +1835,85 @@ func (mr *MockPropertyMockRecorder) String() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockProperty)(nil).String))
 }
 
+// MockPredicate is a mock of Predicate interface.
+type MockPredicate struct {
+	ctrl     *gomock.Controller
+	recorder *MockPredicateMockRecorder
+}
+
+// MockPredicateMockRecorder is the mock recorder for MockPredicate.
+type MockPredicateMockRecorder struct {
+	mock *MockPredicate
+}
+
+// NewMockPredicate creates a new mock instance.
+func NewMockPredicate(ctrl *gomock.Controller) *MockPredicate {
+	mock := &MockPredicate{ctrl: ctrl}
+	mock.recorder = &MockPredicateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPredicate) EXPECT() *MockPredicateMockRecorder {
+	return m.recorder
+}
+
+// And mocks base method.
+func (m *MockPredicate) And(other interfaces.Predicate) interfaces.Predicate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "And", other)
+	ret0, _ := ret[0].(interfaces.Predicate)
+	return ret0
+}
+
+// And indicates an expected call of And.
+func (mr *MockPredicateMockRecorder) And(other interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "And", reflect.TypeOf((*MockPredicate)(nil).And), other)
+}
+
+// Negate mocks base method.
+func (m *MockPredicate) Negate() interfaces.Predicate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Negate")
+	ret0, _ := ret[0].(interfaces.Predicate)
+	return ret0
+}
+
+// Negate indicates an expected call of Negate.
+func (mr *MockPredicateMockRecorder) Negate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Negate", reflect.TypeOf((*MockPredicate)(nil).Negate))
+}
+
+// Or mocks base method.
+func (m *MockPredicate) Or(other interfaces.Predicate) interfaces.Predicate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Or", other)
+	ret0, _ := ret[0].(interfaces.Predicate)
+	return ret0
+}
+
+// Or indicates an expected call of Or.
+func (mr *MockPredicateMockRecorder) Or(other interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Or", reflect.TypeOf((*MockPredicate)(nil).Or), other)
+}
+
+// String mocks base method.
+func (m *MockPredicate) String() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "String")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// String indicates an expected call of String.
+func (mr *MockPredicateMockRecorder) String() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockPredicate)(nil).String))
+}
+
 // MockDropper is a mock of Dropper interface.
 type MockDropper struct {
 	ctrl     *gomock.Controller
@@ -960,3 +2024,96 @@ func (mr *MockCounterMockRecorder) Count() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockCounter)(nil).Count))
 }
+
+// CountLocal mocks base method.
+func (m *MockCounter) CountLocal() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLocal")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// CountLocal indicates an expected call of CountLocal.
+func (mr *MockCounterMockRecorder) CountLocal() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLocal", reflect.TypeOf((*MockCounter)(nil).CountLocal))
+}
+
+// MockNumericAggregator is a mock of NumericAggregator interface.
+type MockNumericAggregator struct {
+	ctrl     *gomock.Controller
+	recorder *MockNumericAggregatorMockRecorder
+}
+
+// MockNumericAggregatorMockRecorder is the mock recorder for MockNumericAggregator.
+type MockNumericAggregatorMockRecorder struct {
+	mock *MockNumericAggregator
+}
+
+// NewMockNumericAggregator creates a new mock instance.
+func NewMockNumericAggregator(ctrl *gomock.Controller) *MockNumericAggregator {
+	mock := &MockNumericAggregator{ctrl: ctrl}
+	mock.recorder = &MockNumericAggregatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNumericAggregator) EXPECT() *MockNumericAggregatorMockRecorder {
+	return m.recorder
+}
+
+// Max mocks base method.
+func (m *MockNumericAggregator) Max() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Max")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Max indicates an expected call of Max.
+func (mr *MockNumericAggregatorMockRecorder) Max() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Max", reflect.TypeOf((*MockNumericAggregator)(nil).Max))
+}
+
+// Mean mocks base method.
+func (m *MockNumericAggregator) Mean() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mean")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Mean indicates an expected call of Mean.
+func (mr *MockNumericAggregatorMockRecorder) Mean() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mean", reflect.TypeOf((*MockNumericAggregator)(nil).Mean))
+}
+
+// Min mocks base method.
+func (m *MockNumericAggregator) Min() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Min")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Min indicates an expected call of Min.
+func (mr *MockNumericAggregatorMockRecorder) Min() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Min", reflect.TypeOf((*MockNumericAggregator)(nil).Min))
+}
+
+// Sum mocks base method.
+func (m *MockNumericAggregator) Sum() interfaces.QueryBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sum")
+	ret0, _ := ret[0].(interfaces.QueryBuilder)
+	return ret0
+}
+
+// Sum indicates an expected call of Sum.
+func (mr *MockNumericAggregatorMockRecorder) Sum() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sum", reflect.TypeOf((*MockNumericAggregator)(nil).Sum))
+}
@@ -77,6 +77,35 @@ func (mr *MockQueryExecutorMockRecorder) ExecuteAsync(query, responseChannel int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAsync", reflect.TypeOf((*MockQueryExecutor)(nil).ExecuteAsync), query, responseChannel)
 }
 
+// ExecuteAsyncWithID mocks base method.
+func (m *MockQueryExecutor) ExecuteAsyncWithID(requestID, query string, responseChannel chan interfaces.AsyncResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAsyncWithID", requestID, query, responseChannel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteAsyncWithID indicates an expected call of ExecuteAsyncWithID.
+func (mr *MockQueryExecutorMockRecorder) ExecuteAsyncWithID(requestID, query, responseChannel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAsyncWithID", reflect.TypeOf((*MockQueryExecutor)(nil).ExecuteAsyncWithID), requestID, query, responseChannel)
+}
+
+// ExecuteBatch mocks base method.
+func (m *MockQueryExecutor) ExecuteBatch(queries []string) ([][]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteBatch", queries)
+	ret0, _ := ret[0].([][]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteBatch indicates an expected call of ExecuteBatch.
+func (mr *MockQueryExecutorMockRecorder) ExecuteBatch(queries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBatch", reflect.TypeOf((*MockQueryExecutor)(nil).ExecuteBatch), queries)
+}
+
 // ExecuteFile mocks base method.
 func (m *MockQueryExecutor) ExecuteFile(path string) ([]interfaces.Response, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +151,21 @@ func (mr *MockQueryExecutorMockRecorder) ExecuteWithBindings(query, bindings, re
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithBindings", reflect.TypeOf((*MockQueryExecutor)(nil).ExecuteWithBindings), query, bindings, rebindings)
 }
 
+// ExecuteWithID mocks base method.
+func (m *MockQueryExecutor) ExecuteWithID(requestID, query string) ([]interfaces.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteWithID", requestID, query)
+	ret0, _ := ret[0].([]interfaces.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteWithID indicates an expected call of ExecuteWithID.
+func (mr *MockQueryExecutorMockRecorder) ExecuteWithID(requestID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteWithID", reflect.TypeOf((*MockQueryExecutor)(nil).ExecuteWithID), requestID, query)
+}
+
 // IsConnected mocks base method.
 func (m *MockQueryExecutor) IsConnected() bool {
 	m.ctrl.T.Helper()
@@ -0,0 +1,104 @@
+package gremcos
+
+import (
+	"context"
+
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// The attributes that are set on the spans of the executed queries
+const (
+	spanAttributeDBSystem      = "db.system"
+	spanAttributeDBStatement   = "db.statement"
+	spanAttributeRequestID     = "gremcos.request_id"
+	spanAttributeRequestCharge = "gremcos.request_charge"
+	spanAttributeChunks        = "gremcos.chunks"
+)
+
+// Tracer creates the spans for the executed queries. It follows the OpenTelemetry tracing API, which means a
+// trace.Tracer of OpenTelemetry can be adapted easily, e.g.
+//	type otelTracer struct{ tracer trace.Tracer }
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, gremcos.Span) {
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+//	New("wss://example.com", WithTracer(otelTracer{tracerProvider.Tracer("gremcos")}))
+// Hint: gremcos does not depend on OpenTelemetry itself (which requires a newer go version), that's why a Tracer
+// is used instead of a trace.TracerProvider.
+type Tracer interface {
+	// Start creates a span with the given name as child of the span contained in the given context
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a span created by the Tracer, see trace.Span of OpenTelemetry
+type Span interface {
+	// SetAttribute sets the given attribute, the value is a string, int64 or float64
+	SetAttribute(key string, value interface{})
+	// RecordError records the given error as event of the span
+	RecordError(err error)
+	// SetStatusError marks the span as failed using the given description
+	SetStatusError(description string)
+	// End completes the span
+	End()
+}
+
+// WithTracer enables tracing, for each query (Execute, ExecuteQuery, ExecuteWithBindings, ExecuteBatch and ExecuteAsync)
+// a span is created using the given tracer. Use ExecuteWithContext or ExecuteAsyncWithContext to create the span as child of the
+// span contained in the context of the caller, otherwise a new trace is started. The spans contain the (redacted) query, the request id, the request charge (RU)
+// and the number of responses (chunks). Failed queries are recorded as error on the span.
+// Per default no tracing is done.
+func WithTracer(tracer Tracer) Option {
+	return func(c *cosmosImpl) {
+		c.tracer = tracer
+	}
+}
+
+// WithSpanQueryText specifies whether the (redacted) query is added to the spans (see WithTracer) as db.statement attribute.
+// Even though the values of the query are redacted it can be disabled if the keys or labels of the queries are sensitive (PII).
+// Per default the query is added.
+func WithSpanQueryText(enabled bool) Option {
+	return func(c *cosmosImpl) {
+		c.spanQueryText = enabled
+	}
+}
+
+// querySpan is the span of one query, it is a no-op in case tracing is disabled
+type querySpan struct {
+	span Span
+}
+
+// startSpan starts a span for the given query as child of the span contained in the given context, it has to be ended by calling end
+func (c *cosmosImpl) startSpan(ctx context.Context, operation string, query string) querySpan {
+	if c.tracer == nil {
+		return querySpan{}
+	}
+
+	_, span := c.tracer.Start(ctx, "gremcos."+operation)
+	span.SetAttribute(spanAttributeDBSystem, "cosmosdb")
+	if c.spanQueryText {
+		span.SetAttribute(spanAttributeDBStatement, RedactValues(query))
+	}
+	return querySpan{span: span}
+}
+
+// end adds the information of the given responses to the span, records the given error and ends the span
+func (s querySpan) end(responses []interfaces.Response, err error) {
+	if s.span == nil {
+		return
+	}
+	defer s.span.End()
+
+	if len(responses) > 0 {
+		s.span.SetAttribute(spanAttributeRequestID, responses[len(responses)-1].RequestID)
+	}
+	s.span.SetAttribute(spanAttributeRequestCharge, TotalRequestCharge(responses))
+	s.span.SetAttribute(spanAttributeChunks, int64(len(responses)))
+
+	if err == nil {
+		err = extractFirstError(responses)
+	}
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatusError(err.Error())
+	}
+}
@@ -0,0 +1,174 @@
+package gremcos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/supplyon/gremcos/interfaces"
+)
+
+// recordedSpan is a span that was created by the recordingTracer
+type recordedSpan struct {
+	name        string
+	parent      context.Context
+	attributes  map[string]interface{}
+	errors      []error
+	statusError string
+	ended       bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *recordedSpan) RecordError(err error)                      { s.errors = append(s.errors, err) }
+func (s *recordedSpan) SetStatusError(description string)          { s.statusError = description }
+func (s *recordedSpan) End()                                       { s.ended = true }
+
+// recordingTracer is an in-memory tracer that keeps all created spans
+type recordingTracer struct {
+	mux   sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	span := &recordedSpan{name: spanName, parent: ctx, attributes: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTracingExecute(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	tracer := &recordingTracer{}
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithTracer(tracer))
+	query := `g.V().has("name","hans")`
+	responses := []interfaces.Response{
+		{RequestID: "abc", Status: interfaces.Status{Code: interfaces.StatusPartialContent, Attributes: map[string]interface{}{"x-ms-total-request-charge": 2.5}}},
+		{RequestID: "abc", Status: interfaces.Status{Code: interfaces.StatusSuccess, Attributes: map[string]interface{}{"x-ms-total-request-charge": 4.5}}},
+	}
+	mockedQueryExecutor.EXPECT().Execute(query).Return(responses, nil)
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return(nil, fmt.Errorf("connection lost"))
+
+	// WHEN
+	_, err := cosmos.Execute(query)
+	_, errFailed := cosmos.Execute("g.V()")
+
+	// THEN
+	require.NoError(t, err)
+	require.Error(t, errFailed)
+	require.Len(t, tracer.spans, 2)
+	span := tracer.spans[0]
+	assert.Equal(t, "gremcos.execute", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, map[string]interface{}{
+		spanAttributeDBSystem:      "cosmosdb",
+		spanAttributeDBStatement:   `g.V().has("name","***")`,
+		spanAttributeRequestID:     "abc",
+		spanAttributeRequestCharge: 4.5,
+		spanAttributeChunks:        int64(2),
+	}, span.attributes)
+	assert.Empty(t, span.errors)
+	assert.Empty(t, span.statusError)
+
+	failedSpan := tracer.spans[1]
+	assert.True(t, failedSpan.ended)
+	require.Len(t, failedSpan.errors, 1)
+	assert.Equal(t, errFailed, failedSpan.errors[0])
+	assert.Equal(t, "connection lost", failedSpan.statusError)
+}
+
+func TestTracingWithoutQueryText(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	tracer := &recordingTracer{}
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithTracer(tracer), WithSpanQueryText(false))
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return([]interfaces.Response{{RequestID: "abc", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+
+	// WHEN
+	_, err := cosmos.Execute("g.V()")
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+	assert.NotContains(t, tracer.spans[0].attributes, spanAttributeDBStatement)
+	assert.Equal(t, "abc", tracer.spans[0].attributes[spanAttributeRequestID])
+}
+
+func TestTracingExecuteAsync(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	tracer := &recordingTracer{}
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithTracer(tracer))
+	numChunks := 3
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.V()", gomock.Any()).DoAndReturn(func(query string, responseChannel chan interfaces.AsyncResponse) error {
+		go func() {
+			for i := 0; i < numChunks; i++ {
+				responseChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "abc"}, ChunkIndex: i}
+			}
+			close(responseChannel)
+		}()
+		return nil
+	})
+	responseChannel := make(chan interfaces.AsyncResponse)
+
+	// WHEN
+	err := cosmos.ExecuteAsync("g.V()", responseChannel)
+	// the span is ended before the channel is closed
+	for range responseChannel {
+	}
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	assert.Equal(t, "gremcos.execute_async", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, int64(numChunks), span.attributes[spanAttributeChunks])
+	assert.Equal(t, "abc", span.attributes[spanAttributeRequestID])
+}
+
+// parentSpanKey is the context key of the (fake) span of the caller
+type parentSpanKey struct{}
+
+func TestTracingPropagatesContext(t *testing.T) {
+	// GIVEN
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	tracer := &recordingTracer{}
+	cosmos, mockedQueryExecutor := newCosmosWithMockedPool(t, mockCtrl, WithTracer(tracer))
+	ctx := context.WithValue(context.Background(), parentSpanKey{}, "parent")
+	mockedQueryExecutor.EXPECT().Execute("g.V()").Return([]interfaces.Response{{RequestID: "abc", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}, nil)
+	mockedQueryExecutor.EXPECT().ExecuteAsync("g.E()", gomock.Any()).DoAndReturn(func(query string, forwardChannel chan interfaces.AsyncResponse) error {
+		go func() {
+			forwardChannel <- interfaces.AsyncResponse{Response: interfaces.Response{RequestID: "def", Status: interfaces.Status{Code: interfaces.StatusSuccess}}}
+			close(forwardChannel)
+		}()
+		return nil
+	})
+
+	// WHEN
+	_, err := cosmos.ExecuteWithContext(ctx, "g.V()")
+	responseChannel := make(chan interfaces.AsyncResponse)
+	errAsync := cosmos.ExecuteAsyncWithContext(ctx, "g.E()", responseChannel)
+	for range responseChannel {
+	}
+
+	// THEN
+	require.NoError(t, err)
+	require.NoError(t, errAsync)
+	tracer.mux.Lock()
+	defer tracer.mux.Unlock()
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, "gremcos.execute", tracer.spans[0].name)
+	assert.Equal(t, "parent", tracer.spans[0].parent.Value(parentSpanKey{}))
+	assert.Equal(t, "gremcos.execute_async", tracer.spans[1].name)
+	assert.Equal(t, "parent", tracer.spans[1].parent.Value(parentSpanKey{}))
+}
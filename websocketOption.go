@@ -1,6 +1,8 @@
 package gremcos
 
 import (
+	"crypto/tls"
+	"net/url"
 	"time"
 )
 
@@ -36,6 +38,20 @@ func SetBufferSize(readBufferSize int, writeBufferSize int) optionWebsocket {
 	}
 }
 
+//SetTLSConfig sets the tls configuration used for wss connections
+func SetTLSConfig(tlsConfig *tls.Config) optionWebsocket {
+	return func(ws *websocket) {
+		ws.tlsConfig = tlsConfig
+	}
+}
+
+//SetProxy sets the url of the (http or socks5) proxy the connection is established through
+func SetProxy(proxyURL *url.URL) optionWebsocket {
+	return func(ws *websocket) {
+		ws.proxyURL = proxyURL
+	}
+}
+
 // websocketDialerFactoryFun exchange/ set the factory function used to create the dialer which
 // is then used to open the websocket connection.
 // This function is not exported on purpose, it should only used for injection and mocking in tests!!